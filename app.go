@@ -3,25 +3,38 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	backend "monitoring_serv/backend"
 	"net"
 	"net/http"
+	netmail "net/mail"
 	"os"
 	"os/exec"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/emersion/go-sasl"    // Authentification du serveur SMTP embarqué
 	"github.com/emersion/go-smtp"    // Serveur SMTP embarqué
 	//smtpbackend "github.com/emersion/go-smtp/backend"
 	"github.com/wneessen/go-mail"    // Client SMTP pour l'envoi d'emails
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime" // Événements vers le frontend
 )
 
 // App - Structure principale de l'application
@@ -31,25 +44,37 @@ type App struct {
 	notifier   *backend.NotificationManager     // Gestionnaire de notifications avec cooldown
 	settings   backend.Settings                 // Configuration utilisateur
 	settingsMu sync.RWMutex                     // Mutex pour accès concurrent aux paramètres
+	smtpMu     sync.Mutex                       // Protège le serveur SMTP embarqué (redémarré par applySettings)
 	smtpServer *smtp.Server                     // Serveur SMTP embarqué
 	smtpPort   int                              // Port du serveur SMTP embarqué
+	smtpRelay  *EmbeddedSMTP                    // Backend du serveur SMTP embarqué (identifiants)
+	mailQueue  *backend.MailQueue               // File persistante des emails sortants
+	pushMu     sync.Mutex                       // Protège pushServer (redémarré par applySettings)
+	pushServer *http.Server                     // Serveur HTTP des pings (checks push)
+	watcher    *backend.ConfigWatcher           // Rechargement de servers.json et settings.json modifiés sur le disque
+	configFile string                           // Fichier de configuration déclaratif (YAML/TOML), vide si absent
+
+	digestStop chan struct{} // Arrêt de l'envoi des résumés des heures calmes
 }
 
 // EmbeddedSMTP - Gestionnaire du serveur SMTP embarqué
 type EmbeddedSMTP struct {
-	// Stockage temporaire des emails reçus par le serveur embarqué
-	emails     []EmailMessage        // Liste des emails en attente de traitement
-	smtpConfig backend.SMTPConfig    // Configuration SMTP pour le transfert externe
+	// Les emails reçus sont placés dans la file d'envoi vers le serveur externe
+	queue          *backend.MailQueue // File persistante des emails à relayer
+	username       string             // Identifiant généré pour le client interne
+	password       string             // Mot de passe généré à chaque démarrage
+	allowRecipient func(string) bool  // Liste blanche des destinataires du relais
+
+	// Emails de heartbeat (checks email-heartbeat): consommés, jamais relayés
+	isHeartbeat func(string) bool                      // Destinataire surveillé par un check email-heartbeat
+	onHeartbeat func(recipients []string, subject string) // Enregistrement d'un email de heartbeat reçu
 }
 
-// EmailMessage - Structure représentant un email
-type EmailMessage struct {
-	From    string      // Adresse de l'expéditeur
-	To      []string    // Liste des destinataires
-	Subject string      // Sujet de l'email
-	Body    string      // Corps du message
-	Time    time.Time   // Horodatage de création
-}
+// embeddedSMTPHost - Adresse d'écoute du SMTP embarqué (boucle locale uniquement)
+const embeddedSMTPHost = "127.0.0.1"
+
+// undisclosedRecipients - En-tête To des emails envoyés uniquement en copie cachée (RFC 5322)
+const undisclosedRecipients = "undisclosed-recipients:;"
 
 // NewApp - Constructeur de l'application
 // Crée une nouvelle instance de App en se basant sur les settings chargés
@@ -68,8 +93,8 @@ func NewApp(notifier *backend.NotificationManager) *App {
 		// Notifications dans l'application seulement
 		notifier.SetEnabled(true)
 	case "email":
-		// Notifications par email, désactiver les notifications in-app
-		notifier.SetEnabled(false)
+		// Notifications par email (le cooldown du gestionnaire s'applique aussi)
+		notifier.SetEnabled(true)
 	case "none":
 		// Aucune notification
 		notifier.SetEnabled(false)
@@ -85,13 +110,28 @@ func NewApp(notifier *backend.NotificationManager) *App {
 		monitor: &Monitor{
 			servers:    make(map[string]*Server),    // Map des serveurs surveillés
 			stopChans:  make(map[string]chan bool), // Canaux d'arrêt par serveur
+			history:    make(map[string][]ServerStatus), // Historique récent par serveur
+			heartbeats: make(map[string]passiveSignal),   // Derniers signaux des checks passifs
+			triggers:   make(map[string]chan struct{}),   // Déclencheurs de vérification immédiate
+			subjects:   make(map[string]*regexp.Regexp),  // Motifs de sujet des checks email-heartbeat
 			statusChan: make(chan ServerStatusUpdate, 100), // Canal des mises à jour de statut
 			mutex:      sync.RWMutex{},             // Mutex pour accès concurrent
 			Notifier:   notifier,                   // Gestionnaire de notifications
+			policy:     s.CriticalPolicy(),         // Seuils d'alerte critique
+
+			maintenance: backend.NewMaintenanceSchedule(s.MaintenanceWindows), // Fenêtres de maintenance
+			quiet:       s.QuietPolicy(),                                      // Heures calmes
 		},
 		notifier: notifier,  // Référence au gestionnaire de notifications
 		settings: s,         // Configuration utilisateur
 	}
+	// Router les événements du monitoring vers le canal de notification configuré
+	app.monitor.OnAlert = app.handleAlert
+	// File d'envoi des emails relayés par le SMTP embarqué
+	app.mailQueue = backend.NewMailQueue(app.relayQueuedEmail)
+	app.mailQueue.Ready = app.relayReady
+	// Conserver le refresh token OAuth2 renouvelé par le fournisseur
+	backend.OnRefreshTokenRotated(app.persistRefreshToken)
 	return app
 }
 
@@ -101,15 +141,53 @@ func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
 	// Charger les serveurs existants depuis le fichier de configuration
 	a.monitor.LoadServersFromFile()
+	// Le fichier déclaratif, s'il existe, remplace les serveurs et les settings qu'il déclare
+	if a.configFile = backend.DeclarativeConfigPath(); a.configFile != "" {
+		if err := a.applyDeclarativeConfig(false); err != nil {
+			log.Printf("❌ %s ignoré, configuration JSON conservée:\n%s", a.configFile, err)
+		}
+	}
+	// Reprendre les emails restés en file lors de la dernière exécution
+	if err := a.mailQueue.Start(); err != nil {
+		log.Printf("⚠️ Impossible de charger la file d'emails: %s", err)
+	}
 	// Démarrer le serveur SMTP embarqué pour les notifications email
 	a.StartEmbeddedSMTP()
+	// Démarrer la réception des pings des checks push
+	if err := a.StartPushServer(); err != nil {
+		log.Printf("❌ %s", err)
+	}
+	// Appliquer à chaud les modifications des fichiers de configuration
+	handlers := map[string]func(){
+		"servers.json":  a.reloadServers,
+		"settings.json": a.reloadSettings,
+	}
+	// Le fichier déclaratif n'est surveillé que s'il est dans le dossier de configuration
+	if dir, err := backend.ConfigDir(); err == nil && a.configFile != "" {
+		if abs, err := filepath.Abs(a.configFile); err == nil && filepath.Dir(abs) == dir {
+			handlers[filepath.Base(abs)] = a.reloadDeclarativeConfig
+		}
+	}
+	watcher, err := backend.WatchConfigFiles(handlers)
+	if err != nil {
+		log.Printf("⚠️ Rechargement à chaud désactivé: %s", err)
+	}
+	a.watcher = watcher
+	// Envoyer les résumés des heures calmes à leur échéance
+	a.digestStop = make(chan struct{})
+	go a.quietDigestLoop(a.digestStop)
 }
 
 // onDomReady - Fonction appelée après le chargement des ressources front-end
 // Démarre le monitoring de tous les serveurs
 func (a *App) onDomReady(ctx context.Context) {
 	// Démarrer le monitoring pour tous les serveurs chargés
+	// (les serveurs en pause restent arrêtés jusqu'à leur reprise)
 	for _, server := range a.monitor.servers {
+		if server.Paused {
+			log.Printf("⏸️ %s en pause, non surveillé", server.Name)
+			continue
+		}
 		a.monitor.StartMonitoring(server)
 	}
 }
@@ -117,11 +195,23 @@ func (a *App) onDomReady(ctx context.Context) {
 // onShutdown - Fonction appelée à la fermeture de l'application
 // Sauvegarde les serveurs dans le fichier de configuration
 func (a *App) onShutdown(ctx context.Context) {
+	// Ne plus recharger les fichiers pendant la sauvegarde finale
+	if a.watcher != nil {
+		a.watcher.Close()
+	}
+	if a.digestStop != nil {
+		close(a.digestStop)
+	}
 	fmt.Println(">>> onShutdown called, saving servers to file")
 	err := a.monitor.SaveServersToFile()
 	if err != nil {
 		fmt.Println(">>> Error saving servers:", err)
 	}
+	// Arrêter le worker d'envoi et sauvegarder la file d'emails
+	if err := a.mailQueue.Stop(); err != nil {
+		fmt.Println(">>> Error saving mail queue:", err)
+	}
+	a.stopPushServer()
 }
 
 // Server - Structure représentant un serveur à surveiller
@@ -129,10 +219,33 @@ type Server struct {
 	ID       string       `json:"id"`       // Identifiant unique du serveur
 	Name     string       `json:"name"`     // Nom convivial du serveur
 	URL      string       `json:"url"`      // URL ou adresse à surveiller
-	Type     string       `json:"type"`     // Type de monitoring: http, tcp, ping
+	Type     string       `json:"type"`     // Type de monitoring: http, tcp, ping, email-heartbeat, push
 	Interval string       `json:"interval"` // Intervalle de vérification (format string)
 	Timeout  string       `json:"timeout"`  // Timeout pour les vérifications (format string)
 	Status   ServerStatus `json:"status"`   // Statut actuel du serveur
+
+	// Organisation des serveurs
+	Group  string   `json:"group,omitempty"`  // Groupe hiérarchique (ex: "prod/eu/web")
+	Tags   []string `json:"tags,omitempty"`   // Libellés libres (ex: "critique", "client-a")
+	Paused bool     `json:"paused,omitempty"` // Monitoring suspendu, configuration conservée
+
+	// Dépendances: un parent DOWN rend le serveur injoignable (ses alertes sont suspendues)
+	Parents []string `json:"parents,omitempty"` // Identifiants des serveurs parents (ex: routeur)
+
+	// Heures calmes propres au serveur (nil = réglage de son type de check, sinon global)
+	QuietHours *backend.QuietHours `json:"quiet_hours,omitempty"`
+
+	// Surcharges des seuils d'alerte critique (valeur nulle = réglage global)
+	CriticalFailures int    `json:"critical_failures,omitempty"` // Échecs consécutifs avant alerte critique (-1: jamais)
+	CriticalRepeat   int    `json:"critical_repeat,omitempty"`   // Répétition tous les N échecs (-1: jamais)
+	CriticalAfter    string `json:"critical_after,omitempty"`    // Durée de panne avant alerte critique (ex: "10m", "off": jamais)
+
+	// Check email-heartbeat: URL contient le motif du destinataire (ex: "backup-*@monitor.local")
+	SubjectPattern string `json:"subject_pattern,omitempty"` // Expression régulière attendue dans le sujet (optionnelle)
+
+	// Check push: URL contient le chemin de ping généré ("/push/<jeton>")
+	PushToken string `json:"push_token,omitempty"` // Jeton secret de l'URL de ping
+	Grace     string `json:"grace,omitempty"`      // Délai de grâce après l'intervalle (checks passifs, ex: "5m")
 }
 
 // ServerStatus - Structure représentant l'état d'un serveur
@@ -141,17 +254,59 @@ type ServerStatus struct {
 	ResponseTime int64     `json:"response_time_ms"`  // Temps de réponse en millisecondes
 	LastCheck    time.Time `json:"last_check"`        // Horodatage de la dernière vérification
 	LastError    string    `json:"last_error,omitempty"` // Dernière erreur rencontrée
+
+	ConsecutiveFailures int        `json:"consecutive_failures"` // Nombre d'échecs consécutifs
+	DownSince           *time.Time `json:"down_since,omitempty"` // Début de la panne en cours
+
+	LastHeartbeat *time.Time `json:"last_heartbeat,omitempty"` // Dernier signal reçu (checks passifs)
+	LastMessage   string     `json:"last_message,omitempty"`   // Message joint au dernier ping (checks push)
+
+	Maintenance string `json:"maintenance,omitempty"` // Fenêtre de maintenance en cours (alertes suspendues)
+
+	Unreachable bool   `json:"unreachable,omitempty"` // Injoignable: un serveur parent est DOWN
+	RootCause   string `json:"root_cause,omitempty"`  // Serveur parent à l'origine de la panne
 }
 
 // Monitor - Gestionnaire du monitoring des serveurs
 type Monitor struct {
 	servers    map[string]*Server              // Map des serveurs surveillés par ID
 	stopChans  map[string]chan bool           // Canaux d'arrêt pour chaque serveur
+	history    map[string][]ServerStatus      // Dernières vérifications par serveur
+	heartbeats map[string]passiveSignal       // Dernier signal reçu par serveur (checks passifs)
+	triggers   map[string]chan struct{}       // Demandes de vérification immédiate par serveur
+	subjects   map[string]*regexp.Regexp      // Motifs de sujet compilés des checks email-heartbeat
 	statusChan chan ServerStatusUpdate       // Canal pour les mises à jour de statut
 	mutex      sync.RWMutex                  // Mutex pour accès concurrent sécurisé
 	Notifier   *backend.NotificationManager // Gestionnaire de notifications
+	OnAlert    func(AlertEvent)             // Routage des alertes (desktop par défaut si nil)
+	policy     backend.CriticalPolicy       // Seuils globaux d'alerte critique
+
+	maintenance *backend.MaintenanceSchedule // Fenêtres de maintenance (alertes suspendues)
+	quiet       backend.QuietPolicy          // Heures calmes globales et par type de check
+}
+
+// AlertEvent - Événement d'alerte émis lors d'un changement d'état d'un serveur
+type AlertEvent struct {
+	Server   Server        // Copie du serveur au moment de l'alerte
+	Kind     string        // Type d'alerte: DOWN, UP ou CRITICAL
+	Message  string        // Détail de l'alerte (ex: "DOWN (échecs: 3)")
+	Downtime time.Duration  // Durée de la panne (UP et CRITICAL)
+	Time     time.Time      // Horodatage de l'événement
+	History  []ServerStatus // Dernières vérifications (plus récente en premier)
+
+	Dependents []string // Serveurs dépendants injoignables (alerte DOWN de cause racine)
+}
+
+// passiveSignal - Dernier signal envoyé par un serveur à check passif
+type passiveSignal struct {
+	Time    time.Time // Réception du signal
+	IsUp    bool      // État déclaré (un email de heartbeat est toujours UP)
+	Message string    // Message optionnel joint au ping
 }
 
+// maxHistory - Nombre de vérifications conservées par serveur
+const maxHistory = 20
+
 // ServerStatusUpdate - Structure pour les mises à jour de statut
 type ServerStatusUpdate struct {
 	ServerID string  // Identifiant du serveur concerné
@@ -164,6 +319,10 @@ func NewMonitor() *Monitor {
 	return &Monitor{
 		servers:    make(map[string]*Server),            // Map vide des serveurs
 		stopChans:  make(map[string]chan bool),         // Map vide des canaux d'arrêt
+		history:    make(map[string][]ServerStatus),    // Map vide des historiques
+		heartbeats: make(map[string]passiveSignal),     // Map vide des signaux reçus
+		triggers:   make(map[string]chan struct{}),     // Map vide des déclencheurs
+		subjects:   make(map[string]*regexp.Regexp),    // Map vide des motifs de sujet
 		statusChan: make(chan ServerStatusUpdate, 100), // Canal avec buffer de 100
 		mutex:      sync.RWMutex{},                     // Mutex initialisé
 	}
@@ -171,16 +330,18 @@ func NewMonitor() *Monitor {
 
 // ===== Méthodes exposées au frontend =====
 
-// GetServers - Récupère la liste de tous les serveurs
-// Retourne une copie sécurisée de la liste des serveurs
-func (a *App) GetServers() []Server {
+// GetServers - Récupère la liste des serveurs correspondant au filtre
+// Retourne une copie sécurisée de la liste des serveurs (filtre vide = tous)
+func (a *App) GetServers(filter ServerFilter) []Server {
 	a.monitor.mutex.RLock()    // Verrouillage en lecture
 	defer a.monitor.mutex.RUnlock()
 
 	// Créer une slice avec la capacité appropriée
 	servers := make([]Server, 0, len(a.monitor.servers))
 	for _, server := range a.monitor.servers {
-		servers = append(servers, *server) // Copie des données
+		if filter.Match(server) {
+			servers = append(servers, *server) // Copie des données
+		}
 	}
 	return servers
 }
@@ -193,8 +354,17 @@ func (a *App) AddServer(server Server) (Server, error) {
 		server.ID = fmt.Sprintf("%d", time.Now().UnixNano())
 	}
 
-	// Valider les données du serveur
-	if err := a.validateServer(&server); err != nil {
+	// Valider les données du serveur et ses dépendances
+	if err := validateServer(&server); err != nil {
+		return server, err
+	}
+	if err := a.checkPushListener(server); err != nil {
+		return server, err
+	}
+	if err := a.checkHeartbeatListener(server); err != nil {
+		return server, err
+	}
+	if err := a.checkServerDependencies(server); err != nil {
 		return server, err
 	}
 
@@ -221,7 +391,16 @@ func (a *App) UpdateServer(server Server) (Server, error) {
 	}
 
 	// Valider les nouvelles données
-	if err := a.validateServer(&server); err != nil {
+	if err := validateServer(&server); err != nil {
+		return server, err
+	}
+	if err := a.checkPushListener(server); err != nil {
+		return server, err
+	}
+	if err := a.checkHeartbeatListener(server); err != nil {
+		return server, err
+	}
+	if err := a.checkServerDependencies(server); err != nil {
 		return server, err
 	}
 
@@ -249,36 +428,207 @@ func (a *App) UpdateServer(server Server) (Server, error) {
 // Arrête le monitoring et supprime toutes les données associées
 func (a *App) DeleteServer(id string) error {
 	a.monitor.mutex.Lock()
-	defer a.monitor.mutex.Unlock()
+	a.monitor.removeServer(id)
+	a.monitor.mutex.Unlock()
+
+	// Sauvegarder les modifications (hors verrou: la sauvegarde relit les serveurs)
+	return a.monitor.SaveServersToFile()
+}
+
+// PauseServer - Suspend le monitoring d'un serveur sans perdre sa configuration
+// Arrête sa boucle de vérification; l'état en pause est sauvegardé
+func (a *App) PauseServer(id string) (Server, error) {
+	return a.setPaused(id, true)
+}
+
+// ResumeServer - Reprend le monitoring d'un serveur en pause
+func (a *App) ResumeServer(id string) (Server, error) {
+	return a.setPaused(id, false)
+}
+
+// setPaused - Met en pause ou reprend un serveur, puis sauvegarde
+// Le statut est effacé: une panne commencée avant ou pendant la pause n'est
+// pas annoncée comme rétablie à la reprise
+func (a *App) setPaused(id string, paused bool) (Server, error) {
+	a.monitor.mutex.Lock()
+	current, exists := a.monitor.servers[id]
+	if !exists {
+		a.monitor.mutex.Unlock()
+		return Server{}, fmt.Errorf("serveur %s introuvable", id)
+	}
+	server := *current
+	if server.Paused == paused {
+		a.monitor.mutex.Unlock()
+		return server, nil
+	}
+	server.Paused = paused
+	server.Status = ServerStatus{}
+	if paused {
+		// Fermer le canal d'arrêt: la boucle de vérification se termine
+		a.monitor.stopMonitoring(id)
+	}
+	a.monitor.servers[id] = &server
+	a.monitor.mutex.Unlock()
 
-	// Arrêter le monitoring du serveur
-	if stopChan, exists := a.monitor.stopChans[id]; exists {
-		close(stopChan)
-		delete(a.monitor.stopChans, id)
+	if paused {
+		log.Printf("⏸️ Monitoring de %s mis en pause", server.Name)
+	} else {
+		// Nouveau canal d'arrêt et reprise des vérifications
+		a.monitor.StartMonitoring(&server)
+		log.Printf("▶️ Monitoring de %s repris", server.Name)
 	}
+	return server, a.monitor.SaveServersToFile()
+}
 
-	// Supprimer le serveur de la map
-	delete(a.monitor.servers, id)
+// checkPushListener - Refuse un check push tant que la réception des pings est
+// désactivée: sans port d'écoute, il passerait DOWN après son délai de grâce
+func (a *App) checkPushListener(server Server) error {
+	if server.Type != "push" || a.pushListenerEnabled() {
+		return nil
+	}
+	return fmt.Errorf("réception des pings désactivée: configurez l'adresse d'écoute des checks push (pushListenAddr) avant d'ajouter un check push")
+}
 
-	// Sauvegarder les modifications
-	a.monitor.SaveServersToFile()
+// pushListenerEnabled - Indique si une adresse d'écoute des pings est configurée
+func (a *App) pushListenerEnabled() bool {
+	a.settingsMu.RLock()
+	defer a.settingsMu.RUnlock()
+	return a.settings.PushListenAddr != ""
+}
 
-	return nil
+// checkHeartbeatListener - Refuse un check email-heartbeat tant que la réception
+// des emails de heartbeat est désactivée: le relais n'écoute alors que sur un
+// port aléatoire de la boucle locale, injoignable par les tâches surveillées
+func (a *App) checkHeartbeatListener(server Server) error {
+	if server.Type != "email-heartbeat" || a.heartbeatListenerEnabled() {
+		return nil
+	}
+	return fmt.Errorf("réception des emails de heartbeat désactivée: configurez l'adresse d'écoute SMTP (heartbeatSmtpAddr) avant d'ajouter un check email-heartbeat")
+}
+
+// heartbeatListenerEnabled - Indique si une adresse d'écoute SMTP des heartbeats est configurée
+func (a *App) heartbeatListenerEnabled() bool {
+	a.settingsMu.RLock()
+	defer a.settingsMu.RUnlock()
+	return a.settings.HeartbeatSMTPAddr != ""
+}
+
+// checkServerDependencies - Vérifie les parents d'un serveur ajouté ou modifié
+// parmi les serveurs existants: parents connus et aucune dépendance circulaire
+func (a *App) checkServerDependencies(server Server) error {
+	if len(server.Parents) == 0 {
+		return nil
+	}
+	servers := []Server{server}
+	for _, existing := range a.GetServers(ServerFilter{}) {
+		if existing.ID != server.ID {
+			servers = append(servers, existing)
+		}
+	}
+	_, err := checkDependencies(servers)
+	return err
+}
+
+// checkDependencies - Vérifie les dépendances d'un ensemble de serveurs
+// Retourne l'identifiant du serveur en cause avec l'erreur (parent inconnu
+// ou dépendance circulaire)
+func checkDependencies(servers []Server) (string, error) {
+	graph := make(backend.DependencyGraph, len(servers))
+	names := make(map[string]string, len(servers))
+	for _, server := range servers {
+		graph[server.ID] = server.Parents
+		names[server.ID] = server.Name
+	}
+	for _, server := range servers {
+		for _, parent := range server.Parents {
+			if _, ok := names[parent]; !ok {
+				return server.ID, fmt.Errorf("serveur parent introuvable: %s", parent)
+			}
+		}
+	}
+	if cycle := graph.Cycle(); cycle != nil {
+		labels := make([]string, len(cycle))
+		for i, id := range cycle {
+			labels[i] = names[id]
+		}
+		return cycle[0], fmt.Errorf("dépendance circulaire: %s", strings.Join(labels, " → "))
+	}
+	return "", nil
 }
 
 // validateServer - Valide les données d'un serveur
 // Vérifie que tous les champs requis sont présents et valides
-func (a *App) validateServer(server *Server) error {
+func validateServer(server *Server) error {
 	if server.Name == "" {
 		return fmt.Errorf("nom du serveur requis")
 	}
+	server.Group = backend.NormalizeGroup(server.Group)
+	server.Tags = backend.NormalizeTags(server.Tags)
+	server.Parents = backend.NormalizeParents(server.Parents)
+	if server.QuietHours != nil {
+		if err := server.QuietHours.Validate(); err != nil {
+			return err
+		}
+	}
+	// Les checks push reçoivent une URL de ping générée
+	if server.Type == "push" {
+		if server.PushToken == "" {
+			token, err := randomToken(16)
+			if err != nil {
+				return fmt.Errorf("impossible de générer l'URL de ping: %s", err)
+			}
+			server.PushToken = token
+		}
+		server.URL = "/push/" + server.PushToken
+	}
 	if server.URL == "" {
 		return fmt.Errorf("URL du serveur requise")
 	}
 	// Vérifier que le type de monitoring est supporté
-	if server.Type != "http" && server.Type != "tcp" && server.Type != "ping" {
+	switch server.Type {
+	case "http", "tcp", "ping":
+	case "email-heartbeat":
+		// L'URL est le motif du destinataire attendu
+		if !strings.Contains(server.URL, "@") {
+			return fmt.Errorf("adresse email attendue pour un check email-heartbeat")
+		}
+		if _, err := path.Match(server.URL, ""); err != nil {
+			return fmt.Errorf("motif de destinataire invalide: %s", server.URL)
+		}
+		if server.SubjectPattern != "" {
+			if _, err := regexp.Compile(server.SubjectPattern); err != nil {
+				return fmt.Errorf("motif de sujet invalide: %s", err)
+			}
+		}
+	case "push":
+	default:
 		return fmt.Errorf("type de serveur invalide")
 	}
+	// Intervalle et timeout facultatifs (valeurs par défaut), mais strictement positifs
+	if server.Interval != "" {
+		if d, err := parseDuration(server.Interval); err != nil || d <= 0 {
+			return fmt.Errorf("intervalle invalide: %s", server.Interval)
+		}
+	}
+	if server.Timeout != "" {
+		if d, err := parseDuration(server.Timeout); err != nil || d <= 0 {
+			return fmt.Errorf("timeout invalide: %s", server.Timeout)
+		}
+	}
+	if server.Grace != "" {
+		if _, err := parseDuration(server.Grace); err != nil {
+			return fmt.Errorf("délai de grâce invalide: %s", server.Grace)
+		}
+	}
+	// Vérifier les surcharges des seuils d'alerte critique
+	if server.CriticalFailures < backend.CriticalDisabled || server.CriticalRepeat < backend.CriticalDisabled {
+		return fmt.Errorf("seuils d'alerte critique invalides")
+	}
+	if server.CriticalAfter != "" && !criticalAfterDisabled(server.CriticalAfter) {
+		if d, err := parseDuration(server.CriticalAfter); err != nil || d < 0 {
+			return fmt.Errorf("durée avant alerte critique invalide: %s", server.CriticalAfter)
+		}
+	}
 	return nil
 }
 
@@ -287,84 +637,183 @@ func (a *App) validateServer(server *Server) error {
 // StartMonitoring - Démarre le monitoring d'un serveur
 // Version améliorée avec gestion intelligente des notifications
 func (m *Monitor) StartMonitoring(server *Server) {
+	// Un serveur en pause garde sa configuration mais n'est pas vérifié
+	if server.Paused {
+		return
+	}
+
 	// Parser l'intervalle de vérification ou utiliser la valeur par défaut
+	// (un intervalle nul ou négatif ferait paniquer le ticker)
 	interval, err := parseDuration(server.Interval)
-	if err != nil {
+	if err != nil || interval <= 0 {
 		interval = 30 * time.Second
 	}
 
 	// Parser le timeout ou utiliser la valeur par défaut
 	timeout, err := parseDuration(server.Timeout)
-	if err != nil {
+	if err != nil || timeout <= 0 {
 		timeout = 10 * time.Second
 	}
 
+	// Motif de sujet des emails de heartbeat, compilé une fois par démarrage
+	// (déjà vérifié par validateServer)
+	var subject *regexp.Regexp
+	if server.Type == "email-heartbeat" && server.SubjectPattern != "" {
+		subject, _ = regexp.Compile(server.SubjectPattern)
+	}
+
 	// Créer le canal d'arrêt pour ce serveur
 	stopChan := make(chan bool)
+	trigger := make(chan struct{}, 1)
 	m.mutex.Lock()
 	m.stopChans[server.ID] = stopChan
+	m.triggers[server.ID] = trigger
+	if subject != nil {
+		m.subjects[server.ID] = subject
+	} else {
+		delete(m.subjects, server.ID)
+	}
+	_, hasHeartbeat := m.heartbeats[server.ID]
 	m.mutex.Unlock()
 
 	// Lancer la goroutine de monitoring
 	go func() {
-		// État initial du serveur
-		prevStatus := server.Status
-		newStatus := m.CheckServer(server, timeout)
-		m.updateServerStatus(server.ID, newStatus)
+		consecutiveFailures := 0 // Échecs consécutifs de la panne en cours
+		var downSince time.Time  // Début de la panne en cours
+		downtimeAlerted := false // Alerte critique de durée déjà envoyée pour cette panne
+		outageSilenced := false  // Panne commencée en maintenance ou injoignable (DOWN non notifié)
+
+		// runCheck - Vérifie le serveur, met à jour les compteurs et notifie
+		// Retourne false si le serveur a été supprimé entre-temps
+		runCheck := func() bool {
+			m.mutex.RLock()
+			current, exists := m.servers[server.ID]
+			if !exists {
+				m.mutex.RUnlock()
+				return false
+			}
+			serverCopy := *current
+			policy := m.criticalPolicyFor(&serverCopy)
+			m.mutex.RUnlock()
+
+			prevStatus := serverCopy.Status
+			// Sans vérification précédente (nouveau serveur, reprise après une
+			// pause), l'état antérieur est supposé UP: seule une panne est annoncée
+			prevUp := prevStatus.IsUp || prevStatus.LastCheck.IsZero()
+			newStatus := m.CheckServer(&serverCopy, timeout)
+
+			// Fenêtre de maintenance: le check est enregistré et marqué,
+			// le NotificationManager suspend les alertes du serveur
+			newStatus.Maintenance = m.maintenanceAt(&serverCopy, newStatus.LastCheck)
+			if m.Notifier != nil {
+				m.Notifier.SetMaintenance(serverCopy.ID, newStatus.Maintenance)
+			}
+
+			// Dépendances: un serveur dont un parent est DOWN est injoignable,
+			// seule la panne du parent (cause racine) est notifiée
+			if !newStatus.IsUp {
+				newStatus.RootCause = m.rootCause(&serverCopy, prevUp)
+				newStatus.Unreachable = newStatus.RootCause != ""
+				if newStatus.Unreachable && !prevStatus.Unreachable {
+					log.Printf("🔗 %s injoignable: %s est DOWN", serverCopy.Name, newStatus.RootCause)
+				}
+			}
+			silencedOutage := outageSilenced
 
-		// Notification pour le changement d'état initial
-		if prevStatus.IsUp != newStatus.IsUp {
-			statusLabel := "DOWN"
+			// Mise à jour des compteurs de panne
+			var downtime time.Duration
+			if !downSince.IsZero() {
+				downtime = newStatus.LastCheck.Sub(downSince)
+			}
 			if newStatus.IsUp {
-				statusLabel = "UP"
+				consecutiveFailures = 0
+				downSince = time.Time{}
+				downtimeAlerted = false
+				outageSilenced = false
+			} else {
+				consecutiveFailures++
+				if downSince.IsZero() {
+					downSince = newStatus.LastCheck
+					outageSilenced = newStatus.Maintenance != "" || newStatus.Unreachable
+				}
+				since := downSince
+				newStatus.DownSince = &since
+			}
+			newStatus.ConsecutiveFailures = consecutiveFailures
+			m.updateServerStatus(server.ID, newStatus)
+			serverCopy.Status = newStatus
+
+			event := AlertEvent{
+				Server:   serverCopy,
+				Downtime: downtime,
+				Time:     newStatus.LastCheck,
+				History:  m.recentHistory(server.ID),
+			}
+
+			// Gestion intelligente des notifications
+			switch {
+			case newStatus.IsUp:
+				// Serveur de nouveau UP (rien à annoncer si la panne, commencée
+				// en maintenance ou injoignable, n'a jamais été notifiée)
+				if !prevUp && !silencedOutage {
+					event.Kind = "UP"
+					m.dispatchAlert(event)
+				}
+			case newStatus.Unreachable:
+				// Parent DOWN: l'alerte de cause racine est celle du parent
+			case silencedOutage && newStatus.Maintenance == "":
+				// Fin de la maintenance ou parent rétabli, panne toujours en cours: elle est notifiée
+				outageSilenced = false
+				event.Kind = "DOWN"
+				if prevStatus.Maintenance != "" {
+					event.Message = fmt.Sprintf("toujours DOWN après la maintenance %s", prevStatus.Maintenance)
+				} else {
+					event.Message = fmt.Sprintf("toujours DOWN après le rétablissement de %s", prevStatus.RootCause)
+				}
+				m.dispatchAlert(event)
+			case policy.ShouldEscalate(consecutiveFailures):
+				// Seuil d'échecs consécutifs atteint (ou répétition périodique)
+				event.Kind = "CRITICAL"
+				event.Message = fmt.Sprintf("DOWN (échecs: %d)", consecutiveFailures)
+				m.dispatchAlert(event)
+			case !downtimeAlerted && policy.DowntimeExceeded(downtime):
+				// Panne plus longue que la durée critique configurée
+				downtimeAlerted = true
+				event.Kind = "CRITICAL"
+				event.Message = fmt.Sprintf("DOWN depuis %s", downtime.Round(time.Second))
+				m.dispatchAlert(event)
+			case prevUp:
+				// Serveur DOWN, avec les serveurs qui en dépendent
+				event.Kind = "DOWN"
+				event.Dependents = m.dependentNames(server.ID)
+				m.dispatchAlert(event)
+			}
+			return true
+		}
+
+		// État initial du serveur
+		// Un check passif sans signal connu attend un intervalle complet
+		if !isPassiveCheck(server.Type) || hasHeartbeat {
+			if !runCheck() {
+				return
 			}
-			m.Notifier.Send(server.Name, statusLabel)
 		}
 
 		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
 
-		consecutiveFailures := 0
-
 		for {
 			select {
 			case <-ticker.C:
 				fmt.Printf("🔄 Tick pour %s à %s (interval=%v)\n", server.Name, time.Now().Format("15:04:05"), interval)
-				m.mutex.RLock()
-				serverCopy := *m.servers[server.ID]
-				m.mutex.RUnlock()
+				if !runCheck() {
+					return
+				}
 
-				prevStatus := serverCopy.Status
-				newStatus := m.CheckServer(&serverCopy, timeout)
-				m.updateServerStatus(server.ID, newStatus)
-
-				// Gestion intelligente des notifications
-				if prevStatus.IsUp != newStatus.IsUp {
-					if newStatus.IsUp {
-						// Serveur de nouveau UP
-						consecutiveFailures = 0
-						m.Notifier.Send(server.Name, "UP")
-					} else {
-						// Serveur DOWN
-						consecutiveFailures++
-
-						// Notification critique après 3 échecs consécutifs
-						if consecutiveFailures >= 3 {
-							m.Notifier.SendCritical(server.Name,
-								fmt.Sprintf("DOWN (échecs: %d)", consecutiveFailures))
-						} else {
-							m.Notifier.Send(server.Name, "DOWN")
-						}
-					}
-				} else if !newStatus.IsUp {
-					// Serveur toujours DOWN, incrémenter le compteur
-					consecutiveFailures++
-
-					// Notification critique périodique pour les pannes persistantes
-					if consecutiveFailures%5 == 0 { // Tous les 5 échecs
-						m.Notifier.SendCritical(server.Name,
-							fmt.Sprintf("TOUJOURS DOWN (échecs: %d)", consecutiveFailures))
-					}
+			case <-trigger:
+				// Signal reçu (email de heartbeat): mettre le statut à jour sans attendre
+				if !runCheck() {
+					return
 				}
 
 			case <-stopChan:
@@ -376,14 +825,229 @@ func (m *Monitor) StartMonitoring(server *Server) {
 	}()
 }
 
+// dispatchAlert - Transmet une alerte au routeur configuré
+// Sans routeur, l'alerte est envoyée en notification desktop
+func (m *Monitor) dispatchAlert(event AlertEvent) {
+	if m.holdForQuietHours(event) {
+		return
+	}
+	if m.OnAlert != nil {
+		m.OnAlert(event)
+		return
+	}
+	m.notifyDesktop(event)
+}
+
+// notifyDesktop - Envoie une alerte sous forme de notification desktop
+// Les alertes restent suspendues pendant une fenêtre de maintenance du serveur
+func (m *Monitor) notifyDesktop(event AlertEvent) {
+	if window := m.Notifier.MaintenanceFor(event.Server.ID); window != "" {
+		log.Printf("🔧 Notification suspendue pour %s (%s): maintenance %s", event.Server.Name, event.Kind, window)
+		return
+	}
+	switch event.Kind {
+	case "CRITICAL":
+		m.Notifier.SendCritical(event.Server.Name, event.Message)
+	case "DOWN":
+		if len(event.Dependents) > 0 {
+			m.Notifier.SendRootCause(event.Server.Name, event.Dependents)
+			return
+		}
+		m.Notifier.Send(event.Server.Name, event.Kind)
+	default:
+		m.Notifier.Send(event.Server.Name, event.Kind)
+	}
+}
+
+// SetCriticalPolicy - Met à jour les seuils globaux d'alerte critique
+func (m *Monitor) SetCriticalPolicy(policy backend.CriticalPolicy) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.policy = policy
+}
+
+// SetMaintenanceWindows - Met à jour les fenêtres de maintenance
+func (m *Monitor) SetMaintenanceWindows(windows []backend.MaintenanceWindow) {
+	schedule := backend.NewMaintenanceSchedule(windows)
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.maintenance = schedule
+}
+
+// SetQuietHours - Met à jour les heures calmes globales et par type de check
+func (m *Monitor) SetQuietHours(policy backend.QuietPolicy) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.quiet = policy
+}
+
+// holdForQuietHours - Retient une alerte émise pendant les heures calmes du serveur
+// En mode critical seules les alertes critiques passent, en mode digest toutes
+// sont gardées pour le résumé envoyé à la fin des heures calmes
+func (m *Monitor) holdForQuietHours(event AlertEvent) bool {
+	// Les alertes d'un serveur en maintenance sont déjà suspendues
+	if m.Notifier == nil || event.Server.Status.Maintenance != "" {
+		return false
+	}
+	m.mutex.RLock()
+	quiet := m.quiet.For(event.Server.ID, event.Server.Type, event.Server.QuietHours)
+	m.mutex.RUnlock()
+	if !quiet.ActiveAt(event.Time) {
+		return false
+	}
+
+	if quiet.DigestMode() {
+		m.Notifier.Defer(backend.DeferredAlert{
+			Server:  event.Server.Name,
+			Kind:    event.Kind,
+			Message: event.Message,
+			Time:    event.Time,
+			Release: quiet.EndAfter(event.Time),
+		})
+		log.Printf("🌙 Alerte %s de %s reportée au résumé des heures calmes", event.Kind, event.Server.Name)
+		return true
+	}
+	if event.Kind == "CRITICAL" {
+		return false
+	}
+	log.Printf("🌙 Alerte %s de %s retenue: heures calmes, alertes critiques seules", event.Kind, event.Server.Name)
+	return true
+}
+
+// maintenanceAt - Fenêtre de maintenance en cours pour un serveur, vide si aucune
+func (m *Monitor) maintenanceAt(server *Server, t time.Time) string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.maintenance.ActiveFor(server.ID, server.Group, t)
+}
+
+// rootCause - Serveur parent à l'origine de la panne d'un serveur, vide si aucun
+// Un parent déjà injoignable renvoie à sa propre cause racine. Avec probe, un
+// parent vu UP est revérifié: sa panne peut ne pas être encore détectée
+func (m *Monitor) rootCause(server *Server, probe bool) string {
+	var (
+		candidates []Server
+		states     []backend.ParentState
+	)
+	m.mutex.RLock()
+	for _, id := range server.Parents {
+		parent, ok := m.servers[id]
+		if !ok || parent.Paused {
+			continue
+		}
+		candidates = append(candidates, *parent)
+		states = append(states, backend.ParentState{
+			Name:      parent.Name,
+			Checked:   !parent.Status.LastCheck.IsZero(),
+			IsUp:      parent.Status.IsUp,
+			RootCause: parent.Status.RootCause,
+		})
+	}
+	m.mutex.RUnlock()
+
+	if cause := backend.RootCause(states); cause != "" || !probe {
+		return cause
+	}
+	for _, parent := range candidates {
+		if isPassiveCheck(parent.Type) {
+			continue
+		}
+		timeout, err := parseDuration(parent.Timeout)
+		if err != nil {
+			timeout = 10 * time.Second
+		}
+		if m.CheckServer(&parent, timeout).IsUp {
+			continue
+		}
+		// Panne du parent pas encore enregistrée: sa vérification est avancée
+		m.mutex.RLock()
+		trigger := m.triggers[parent.ID]
+		m.mutex.RUnlock()
+		triggerChecks([]chan struct{}{trigger})
+		return parent.Name
+	}
+	return ""
+}
+
+// dependentNames - Noms des serveurs actifs qui dépendent d'un serveur, directement ou non
+func (m *Monitor) dependentNames(id string) []string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	graph := make(backend.DependencyGraph, len(m.servers))
+	for serverID, server := range m.servers {
+		graph[serverID] = server.Parents
+	}
+	var names []string
+	for _, dependent := range graph.Dependents(id) {
+		if server := m.servers[dependent]; !server.Paused {
+			names = append(names, server.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// criticalPolicyFor - Politique d'alerte critique effective pour un serveur
+// Les surcharges du serveur priment sur les seuils globaux (appelant verrouillé)
+func (m *Monitor) criticalPolicyFor(server *Server) backend.CriticalPolicy {
+	override := backend.CriticalPolicy{
+		AfterFailures: server.CriticalFailures,
+		RepeatEvery:   server.CriticalRepeat,
+	}
+	if criticalAfterDisabled(server.CriticalAfter) {
+		override.AfterDowntime = backend.CriticalDisabled
+	} else if server.CriticalAfter != "" {
+		if d, err := parseDuration(server.CriticalAfter); err == nil {
+			override.AfterDowntime = d
+		}
+	}
+	return m.policy.Merge(override)
+}
+
+// criticalAfterDisabled - Indique si la surcharge critical_after d'un serveur
+// désactive l'alerte sur durée de panne ("off" ou "-1")
+func criticalAfterDisabled(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "off", "-1":
+		return true
+	}
+	return false
+}
+
 func (m *Monitor) updateServerStatus(serverID string, status ServerStatus) {
 	m.mutex.Lock()
 	if server, exists := m.servers[serverID]; exists {
 		server.Status = status
+
+		// Conserver les dernières vérifications (plus récente en premier)
+		history := append([]ServerStatus{status}, m.history[serverID]...)
+		if len(history) > maxHistory {
+			history = history[:maxHistory]
+		}
+		m.history[serverID] = history
 	}
 	m.mutex.Unlock()
 }
 
+// setHistory - Remplace l'historique récent d'un serveur (import)
+func (m *Monitor) setHistory(serverID string, history []ServerStatus) {
+	if len(history) > maxHistory {
+		history = history[:maxHistory]
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if _, exists := m.servers[serverID]; exists {
+		m.history[serverID] = append([]ServerStatus(nil), history...)
+	}
+}
+
+// recentHistory - Copie de l'historique récent d'un serveur
+func (m *Monitor) recentHistory(serverID string) []ServerStatus {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return append([]ServerStatus(nil), m.history[serverID]...)
+}
+
 func (m *Monitor) CheckServer(server *Server, timeout time.Duration) ServerStatus {
 	start := time.Now()
 
@@ -394,6 +1058,8 @@ func (m *Monitor) CheckServer(server *Server, timeout time.Duration) ServerStatu
 		return m.checkTCP(server, start, timeout)
 	case "ping":
 		return m.checkPing(server, start, timeout)
+	case "email-heartbeat", "push":
+		return m.checkPassive(server)
 	}
 
 	return ServerStatus{
@@ -489,49 +1155,1101 @@ func (m *Monitor) checkPing(server *Server, start time.Time, timeout time.Durati
 	}
 }
 
-// Persistance des données
-func (m *Monitor) SaveServersToFile() error {
+// isPassiveCheck - Indique si le serveur est vérifié à partir des signaux qu'il envoie
+func isPassiveCheck(serverType string) bool {
+	return serverType == "email-heartbeat" || serverType == "push"
+}
+
+// checkPassive - Vérifie qu'un signal (email de heartbeat, ping) est arrivé à temps
+// Le serveur est UP si le dernier signal date de moins d'un intervalle (plus le
+// délai de grâce) et qu'il ne signale pas lui-même un échec
+func (m *Monitor) checkPassive(server *Server) ServerStatus {
+	interval, err := parseDuration(server.Interval)
+	if err != nil || interval <= 0 {
+		interval = 30 * time.Second
+	}
+	var grace time.Duration
+	if server.Grace != "" {
+		grace, _ = parseDuration(server.Grace)
+	}
+	signalName := "ping"
+	if server.Type == "email-heartbeat" {
+		signalName = "email"
+	}
+
 	m.mutex.RLock()
-	defer m.mutex.RUnlock()
+	signal, received := m.heartbeats[server.ID]
+	m.mutex.RUnlock()
 
-	servers := make([]Server, 0, len(m.servers))
-	for _, server := range m.servers {
-		servers = append(servers, *server)
+	now := time.Now()
+	if !received {
+		return ServerStatus{
+			IsUp:      false,
+			LastCheck: now,
+			LastError: fmt.Sprintf("Aucun %s reçu", signalName),
+		}
 	}
 
-	data, err := json.MarshalIndent(servers, "", "  ")
-	if err != nil {
-		return err
+	last := signal.Time
+	status := ServerStatus{
+		IsUp:          signal.IsUp,
+		LastCheck:     now,
+		LastHeartbeat: &last,
+		LastMessage:   signal.Message,
+	}
+	if age := now.Sub(last); age > interval+grace {
+		status.IsUp = false
+		status.LastError = fmt.Sprintf("Aucun %s depuis %s", signalName, age.Round(time.Second))
+	} else if !signal.IsUp {
+		status.LastError = signal.Message
+		if status.LastError == "" {
+			status.LastError = "Échec signalé par le ping"
+		}
 	}
+	return status
+}
 
-	return os.WriteFile("servers.json", data, 0644)
+// recordSignal - Enregistre le signal d'un check passif (appelant verrouillé)
+// Retourne le déclencheur de vérification immédiate du serveur, s'il existe
+func (m *Monitor) recordSignal(serverID string, signal passiveSignal) chan struct{} {
+	m.heartbeats[serverID] = signal
+	return m.triggers[serverID]
 }
 
-func (m *Monitor) LoadServersFromFile() error {
-	data, err := os.ReadFile("servers.json")
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil // Fichier n'existe pas encore, c'est normal
+// triggerChecks - Demande une vérification immédiate sans bloquer
+func triggerChecks(triggers []chan struct{}) {
+	for _, trigger := range triggers {
+		if trigger == nil {
+			continue
+		}
+		select {
+		case trigger <- struct{}{}:
+		default:
 		}
-		return err
 	}
+}
 
-	var servers []Server
-	if err := json.Unmarshal(data, &servers); err != nil {
-		return err
-	}
+// RecordPush - Enregistre un ping reçu sur l'URL d'un check push
+// Retourne false si aucun serveur push ne correspond au jeton
+func (m *Monitor) RecordPush(token string, isUp bool, message string) bool {
+	var trigger chan struct{}
+	found := false
 
 	m.mutex.Lock()
-	defer m.mutex.Unlock()
-
-	for _, server := range servers {
-		m.servers[server.ID] = &server
+	for id, server := range m.servers {
+		if server.Type != "push" || server.PushToken == "" {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(server.PushToken), []byte(token)) != 1 {
+			continue
+		}
+		found = true
+		trigger = m.recordSignal(id, passiveSignal{Time: time.Now(), IsUp: isUp, Message: message})
+		log.Printf("📍 Ping reçu pour %s (UP: %t)", server.Name, isUp)
+		break
 	}
+	m.mutex.Unlock()
 
-	return nil
+	triggerChecks([]chan struct{}{trigger})
+	return found
 }
 
-// Utilitaires
+// heartbeatRecipientMatch - Compare une adresse au motif d'un check email-heartbeat
+// Le motif accepte les jokers * et ? (ex: "backup-*@monitor.local")
+func heartbeatRecipientMatch(pattern, addr string) bool {
+	ok, err := path.Match(strings.ToLower(strings.TrimSpace(pattern)), strings.ToLower(strings.TrimSpace(addr)))
+	return err == nil && ok
+}
+
+// IsHeartbeatRecipient - Indique si une adresse est surveillée par un check email-heartbeat
+func (m *Monitor) IsHeartbeatRecipient(addr string) bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	for _, server := range m.servers {
+		if server.Type == "email-heartbeat" && heartbeatRecipientMatch(server.URL, addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// RecordHeartbeatEmail - Enregistre un email reçu pour les checks email-heartbeat
+// Les serveurs dont le destinataire et le sujet correspondent sont revérifiés
+// Les motifs de sujet (compilés au démarrage du monitoring) sont évalués hors
+// du verrou des serveurs; un serveur en pause ignore les emails reçus
+func (m *Monitor) RecordHeartbeatEmail(recipients []string, subject string) {
+	type candidate struct {
+		id, name string
+		pattern  *regexp.Regexp // nil: aucun motif de sujet
+	}
+	var candidates []candidate
+
+	m.mutex.RLock()
+	for id, server := range m.servers {
+		if server.Type != "email-heartbeat" || server.Paused {
+			continue
+		}
+		for _, rcpt := range recipients {
+			if heartbeatRecipientMatch(server.URL, rcpt) {
+				candidates = append(candidates, candidate{id, server.Name, m.subjects[id]})
+				break
+			}
+		}
+	}
+	m.mutex.RUnlock()
+
+	var accepted []string
+	for _, c := range candidates {
+		if c.pattern != nil && !c.pattern.MatchString(subject) {
+			log.Printf("📭 Email de heartbeat ignoré pour %s: sujet %q non conforme", c.name, subject)
+			continue
+		}
+		log.Printf("💓 Email de heartbeat reçu pour %s", c.name)
+		accepted = append(accepted, c.id)
+	}
+	if len(accepted) == 0 {
+		return
+	}
+
+	now := time.Now()
+	var matched []chan struct{}
+	m.mutex.Lock()
+	for _, id := range accepted {
+		if _, exists := m.servers[id]; !exists {
+			continue // Serveur supprimé entre-temps
+		}
+		matched = append(matched, m.recordSignal(id, passiveSignal{Time: now, IsUp: true}))
+	}
+	m.mutex.Unlock()
+
+	triggerChecks(matched)
+}
+
+// Persistance des données
+func (m *Monitor) SaveServersToFile() error {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	servers := make([]Server, 0, len(m.servers))
+	for _, server := range m.servers {
+		servers = append(servers, *server)
+	}
+
+	data, err := backend.EncodeConfig(backend.ConfigKindServers, servers)
+	if err != nil {
+		return err
+	}
+
+	path, err := backend.ConfigFilePath("servers.json")
+	if err != nil {
+		return err
+	}
+	// Écriture atomique avec sauvegardes (servers.json.1 … .N)
+	return backend.WriteFileWithBackups(path, data, 0o600, backend.ConfigBackups)
+}
+
+func (m *Monitor) LoadServersFromFile() error {
+	// Fichier corrompu: reprendre la sauvegarde valide la plus récente
+	servers, err := readServersFile(backend.ConfigBackups)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // Fichier n'existe pas encore, c'est normal
+		}
+		return err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, server := range servers {
+		m.servers[server.ID] = &server
+		m.restoreSignal(&server)
+	}
+
+	return nil
+}
+
+// readServersFile - Lit servers.json (ou l'une de ses keep sauvegardes)
+// Un fichier d'ancien schéma est mis à niveau
+func readServersFile(keep int) ([]Server, error) {
+	path, err := backend.ConfigFilePath("servers.json")
+	if err != nil {
+		return nil, err
+	}
+	var servers []Server
+	err = backend.LoadConfigFile(path, backend.ConfigKindServers, keep, func(data json.RawMessage) error {
+		servers = nil
+		return json.Unmarshal(data, &servers)
+	})
+	return servers, err
+}
+
+// restoreSignal - Reprend le dernier signal connu d'un check passif (appelant verrouillé)
+func (m *Monitor) restoreSignal(server *Server) {
+	if server.Status.LastHeartbeat != nil {
+		m.heartbeats[server.ID] = passiveSignal{
+			Time:    *server.Status.LastHeartbeat,
+			IsUp:    server.Status.IsUp,
+			Message: server.Status.LastMessage,
+		}
+	}
+}
+
+// ApplyServers - Remplace la liste des serveurs surveillés par servers
+// Seuls les serveurs ajoutés, supprimés ou dont la configuration a changé
+// voient leur goroutine de monitoring démarrée, arrêtée ou relancée; le
+// statut en mémoire des serveurs conservés est plus récent que celui du fichier
+func (m *Monitor) ApplyServers(servers []Server) (added, updated, removed []string) {
+	incoming := make(map[string]bool, len(servers))
+	var toStart []*Server
+
+	m.mutex.Lock()
+	for i := range servers {
+		server := servers[i]
+		incoming[server.ID] = true
+		current, exists := m.servers[server.ID]
+		if !exists {
+			m.servers[server.ID] = &server
+			m.restoreSignal(&server)
+			toStart = append(toStart, &server)
+			added = append(added, server.Name)
+			continue
+		}
+		if sameServerConfig(*current, server) {
+			continue
+		}
+		server.Status = current.Status
+		if stopChan, ok := m.stopChans[server.ID]; ok {
+			close(stopChan)
+			delete(m.stopChans, server.ID)
+		}
+		m.servers[server.ID] = &server
+		toStart = append(toStart, &server)
+		updated = append(updated, server.Name)
+	}
+	for id, server := range m.servers {
+		if incoming[id] {
+			continue
+		}
+		// Retiré aussi des parents des serveurs conservés
+		m.removeServer(id)
+		removed = append(removed, server.Name)
+	}
+	m.mutex.Unlock()
+
+	for _, server := range toStart {
+		m.StartMonitoring(server)
+	}
+	return added, updated, removed
+}
+
+// replaceServers - Remplace les serveurs avant le démarrage du monitoring
+func (m *Monitor) replaceServers(servers []Server) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.servers = make(map[string]*Server, len(servers))
+	for i := range servers {
+		server := servers[i]
+		m.servers[server.ID] = &server
+		m.restoreSignal(&server)
+	}
+	for id := range m.heartbeats {
+		if _, ok := m.servers[id]; !ok {
+			delete(m.heartbeats, id)
+		}
+	}
+}
+
+// sameServerConfig - Compare la configuration de deux serveurs, statut exclu
+func sameServerConfig(a, b Server) bool {
+	a.Status, b.Status = ServerStatus{}, ServerStatus{}
+	return reflect.DeepEqual(a, b)
+}
+
+// stopMonitoring - Arrête la goroutine de monitoring d'un serveur (appelant verrouillé)
+func (m *Monitor) stopMonitoring(id string) {
+	if stopChan, ok := m.stopChans[id]; ok {
+		close(stopChan)
+		delete(m.stopChans, id)
+	}
+	delete(m.triggers, id)
+	delete(m.subjects, id)
+}
+
+// removeServer - Arrête et supprime un serveur et ses données (appelant verrouillé)
+// Le serveur est retiré des parents des serveurs qui en dépendaient
+func (m *Monitor) removeServer(id string) {
+	m.stopMonitoring(id)
+	delete(m.servers, id)
+	delete(m.history, id)
+	delete(m.heartbeats, id)
+	m.quiet.Forget(id)
+	for _, server := range m.servers {
+		// Nouvelle slice: des copies du serveur partagent l'ancienne
+		var parents []string
+		for _, parent := range server.Parents {
+			if parent != id {
+				parents = append(parents, parent)
+			}
+		}
+		if len(parents) != len(server.Parents) {
+			server.Parents = parents
+		}
+	}
+}
+
+// ===== Groupes, tags et actions groupées =====
+
+// Actions groupées sur les serveurs
+const (
+	bulkPause    = "pause"
+	bulkResume   = "resume"
+	bulkDelete   = "delete"
+	bulkInterval = "interval"
+)
+
+// ServerFilter - Sélection de serveurs (critères cumulés, vides = tous)
+type ServerFilter struct {
+	Group  string   `json:"group"`  // Groupe, sous-groupes compris
+	Tags   []string `json:"tags"`   // Tags tous présents sur le serveur
+	Status string   `json:"status"` // "up" | "down" | "paused"
+	Search string   `json:"search"` // Texte contenu dans le nom ou l'URL
+}
+
+// Match - Indique si un serveur correspond au filtre
+func (f ServerFilter) Match(server *Server) bool {
+	if !backend.InGroup(server.Group, f.Group) || !backend.HasTags(server.Tags, f.Tags) {
+		return false
+	}
+	switch f.Status {
+	case "up":
+		if server.Paused || !server.Status.IsUp {
+			return false
+		}
+	case "down":
+		if server.Paused || server.Status.IsUp || server.Status.LastCheck.IsZero() {
+			return false
+		}
+	case "paused":
+		if !server.Paused {
+			return false
+		}
+	}
+	search := strings.ToLower(strings.TrimSpace(f.Search))
+	return search == "" ||
+		strings.Contains(strings.ToLower(server.Name), search) ||
+		strings.Contains(strings.ToLower(server.URL), search)
+}
+
+// GroupStatus - État agrégé d'un groupe, sous-groupes compris
+type GroupStatus struct {
+	Path    string `json:"path"`    // Chemin complet (ex: "prod/eu")
+	Name    string `json:"name"`    // Dernier niveau (ex: "eu")
+	Parent  string `json:"parent"`  // Groupe parent, vide au premier niveau
+	Depth   int    `json:"depth"`   // 0 au premier niveau
+	Total   int    `json:"total"`   // Serveurs du groupe
+	Up      int    `json:"up"`      // Serveurs actifs UP
+	Down    int    `json:"down"`    // Serveurs actifs DOWN
+	Paused  int    `json:"paused"`  // Serveurs en pause
+	Pending int    `json:"pending"` // Serveurs actifs pas encore vérifiés
+	Status  string `json:"status"`  // "up" (tous UP) | "partial" | "down" (tous DOWN) | "paused" | "unknown"
+}
+
+// GetGroups - Groupes (et groupes ancêtres) avec leur état agrégé, triés par chemin
+// Les serveurs en pause ne comptent pas dans l'état du groupe
+func (a *App) GetGroups() []GroupStatus {
+	a.monitor.mutex.RLock()
+	groups := make(map[string]*GroupStatus)
+	for _, server := range a.monitor.servers {
+		for depth, path := range backend.GroupAncestors(server.Group) {
+			group, ok := groups[path]
+			if !ok {
+				group = &GroupStatus{Path: path, Name: path, Depth: depth}
+				if i := strings.LastIndex(path, backend.GroupSeparator); i >= 0 {
+					group.Parent, group.Name = path[:i], path[i+1:]
+				}
+				groups[path] = group
+			}
+			group.Total++
+			switch {
+			case server.Paused:
+				group.Paused++
+			case server.Status.LastCheck.IsZero():
+				group.Pending++
+			case server.Status.IsUp:
+				group.Up++
+			default:
+				group.Down++
+			}
+		}
+	}
+	a.monitor.mutex.RUnlock()
+
+	result := make([]GroupStatus, 0, len(groups))
+	for _, group := range groups {
+		switch {
+		case group.Paused == group.Total:
+			group.Status = "paused"
+		case group.Up+group.Down == 0:
+			group.Status = "unknown"
+		case group.Down == 0:
+			group.Status = "up"
+		case group.Up == 0:
+			group.Status = "down"
+		default:
+			group.Status = "partial"
+		}
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Path < result[j].Path })
+	return result
+}
+
+// TagCount - Tag et nombre de serveurs qui le portent
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// GetTags - Tags utilisés par les serveurs, triés par nom
+// Les tags ne différant que par la casse sont regroupés
+func (a *App) GetTags() []TagCount {
+	a.monitor.mutex.RLock()
+	counts := make(map[string]*TagCount)
+	for _, server := range a.monitor.servers {
+		for _, tag := range server.Tags {
+			key := strings.ToLower(tag)
+			if counts[key] == nil {
+				counts[key] = &TagCount{Tag: tag}
+			}
+			counts[key].Count++
+		}
+	}
+	a.monitor.mutex.RUnlock()
+
+	tags := make([]TagCount, 0, len(counts))
+	for _, count := range counts {
+		tags = append(tags, *count)
+	}
+	sort.Slice(tags, func(i, j int) bool { return strings.ToLower(tags[i].Tag) < strings.ToLower(tags[j].Tag) })
+	return tags
+}
+
+// BulkAction - Action appliquée à tous les serveurs d'un groupe ou d'un tag
+type BulkAction struct {
+	Action   string       `json:"action"`   // "pause" | "resume" | "delete" | "interval"
+	Filter   ServerFilter `json:"filter"`   // Sélection: groupe et/ou tags requis
+	Interval string       `json:"interval"` // Nouvel intervalle (action "interval")
+}
+
+// BulkUpdateServers - Met en pause, reprend, supprime ou change l'intervalle
+// de tous les serveurs sélectionnés; renvoie le nom des serveurs modifiés
+func (a *App) BulkUpdateServers(action BulkAction) ([]string, error) {
+	if action.Filter.Group == "" && len(backend.NormalizeTags(action.Filter.Tags)) == 0 {
+		return nil, fmt.Errorf("sélection par groupe ou par tag requise")
+	}
+	switch action.Action {
+	case bulkPause, bulkResume, bulkDelete:
+	case bulkInterval:
+		if d, err := parseDuration(action.Interval); err != nil || d <= 0 {
+			return nil, fmt.Errorf("intervalle invalide: %s", action.Interval)
+		}
+	default:
+		return nil, fmt.Errorf("action groupée inconnue: %s", action.Action)
+	}
+
+	var (
+		affected []string
+		toStart  []*Server
+	)
+	a.monitor.mutex.Lock()
+	for id, current := range a.monitor.servers {
+		if !action.Filter.Match(current) {
+			continue
+		}
+		server := *current
+		switch action.Action {
+		case bulkPause:
+			if server.Paused {
+				continue
+			}
+			server.Paused = true
+			a.monitor.stopMonitoring(id)
+		case bulkResume:
+			if !server.Paused {
+				continue
+			}
+			server.Paused = false
+			toStart = append(toStart, &server)
+		case bulkDelete:
+			a.monitor.removeServer(id)
+			affected = append(affected, server.Name)
+			continue
+		case bulkInterval:
+			if server.Interval == action.Interval {
+				continue
+			}
+			server.Interval = action.Interval
+			// Relancer le monitoring pour prendre en compte le nouvel intervalle
+			a.monitor.stopMonitoring(id)
+			toStart = append(toStart, &server)
+		}
+		a.monitor.servers[id] = &server
+		affected = append(affected, server.Name)
+	}
+	a.monitor.mutex.Unlock()
+
+	for _, server := range toStart {
+		a.monitor.StartMonitoring(server)
+	}
+	sort.Strings(affected)
+	if len(affected) > 0 {
+		log.Printf("🗂️ Action groupée %s: %d serveur(s)", action.Action, len(affected))
+	}
+	return affected, a.monitor.SaveServersToFile()
+}
+
+// ===== Fenêtres de maintenance =====
+
+// GetMaintenanceWindows - Fenêtres de maintenance avec leur état actuel
+// (en cours, prochain début)
+func (a *App) GetMaintenanceWindows() []backend.MaintenanceWindowStatus {
+	a.settingsMu.RLock()
+	windows := a.settings.MaintenanceWindows
+	a.settingsMu.RUnlock()
+	return backend.NewMaintenanceSchedule(windows).Status(time.Now())
+}
+
+// ValidateMaintenanceWindows - Erreurs par champ des fenêtres, sans rien enregistrer
+func (a *App) ValidateMaintenanceWindows(windows []backend.MaintenanceWindow) []backend.FieldError {
+	return backend.ValidateMaintenanceWindows(windows)
+}
+
+// SaveMaintenanceWindows - Remplace les fenêtres de maintenance et les enregistre
+// Elles s'appliquent à chaque serveur dès sa prochaine vérification
+func (a *App) SaveMaintenanceWindows(windows []backend.MaintenanceWindow) error {
+	a.settingsMu.RLock()
+	s := a.settings
+	a.settingsMu.RUnlock()
+	s.MaintenanceWindows = windows
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	if err := a.applySettings(s, true); err != nil {
+		return err
+	}
+	log.Printf("🔧 %d fenêtre(s) de maintenance enregistrée(s)", len(windows))
+	return nil
+}
+
+// ===== Heures calmes =====
+
+// quietDigestInterval - Fréquence de vérification des résumés à envoyer
+const quietDigestInterval = time.Minute
+
+// quietDigestLoop - Envoie les résumés des heures calmes terminées jusqu'à l'arrêt
+func (a *App) quietDigestLoop(stop chan struct{}) {
+	ticker := time.NewTicker(quietDigestInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			a.flushQuietDigest(now)
+		}
+	}
+}
+
+// flushQuietDigest - Envoie le résumé des alertes dont les heures calmes sont terminées
+// Le résumé suit le mode de notification: email, notification desktop ou rien
+func (a *App) flushQuietDigest(now time.Time) {
+	alerts := a.notifier.TakeDigest(now)
+	if len(alerts) == 0 {
+		return
+	}
+	a.settingsMu.RLock()
+	mode := a.settings.NotificationMode
+	a.settingsMu.RUnlock()
+
+	log.Printf("🌅 Fin des heures calmes: résumé de %d alerte(s)", len(alerts))
+	switch mode {
+	case "none":
+		return
+	case "email":
+		if err := a.sendDigestEmail(alerts); err != nil {
+			log.Printf("❌ Erreur résumé des heures calmes: %s", err)
+		}
+	default:
+		a.notifier.SendDigest(alerts)
+	}
+}
+
+// GetPendingDigest - Alertes retenues en attente du résumé des heures calmes
+func (a *App) GetPendingDigest() []backend.DeferredAlert {
+	return a.notifier.PendingDigest()
+}
+
+// ===== Import / export des serveurs =====
+
+// exportKind - Type des documents d'export JSON et YAML
+const exportKind = "monitoring_serv/export"
+
+// Modes et résolutions de conflit de l'import
+const (
+	importMerge       = "merge"     // Serveurs absents de l'import conservés
+	importReplace     = "replace"   // Serveurs absents de l'import supprimés
+	conflictSkip      = "skip"      // Serveur existant conservé tel quel
+	conflictOverwrite = "overwrite" // Serveur existant remplacé (identifiant et statut conservés)
+	conflictDuplicate = "duplicate" // Serveur importé ajouté en plus, sous un nouvel identifiant
+)
+
+// ExportOptions - Format et contenu d'un export
+type ExportOptions struct {
+	Format            string `json:"format"`              // "json" (défaut) | "yaml" | "csv"
+	IncludeHistory    bool   `json:"include_history"`     // Dernières vérifications de chaque serveur
+	IncludeSettings   bool   `json:"include_settings"`    // Settings, sans les secrets
+	IncludePushTokens bool   `json:"include_push_tokens"` // URLs de ping (secrètes) des checks push
+}
+
+// ImportOptions - Format et stratégie d'un import
+type ImportOptions struct {
+	Format   string `json:"format"`   // "json" | "yaml" | "csv" | "uptimekuma" | "nagios" | "blackbox" | "" (détection automatique)
+	Mode     string `json:"mode"`     // "merge" (défaut) | "replace"
+	MatchBy  string `json:"match_by"` // "id", "url" ou "id_url" (défaut): reconnaissance d'un serveur déjà présent
+	Conflict string `json:"conflict"` // "skip" (défaut) | "overwrite" | "duplicate"
+	Settings bool   `json:"settings"` // Importer aussi les settings du fichier (secrets actuels conservés)
+	DryRun   bool   `json:"dry_run"`  // Prévisualiser sans rien modifier
+}
+
+// ImportItem - Sort d'un serveur du fichier importé
+type ImportItem struct {
+	Row       int    `json:"row"` // Ligne (CSV, Nagios, blackbox) ou rang (JSON, YAML, Uptime Kuma) dans le fichier
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	URL       string `json:"url"`
+	Action    string `json:"action"`               // "add" | "update" | "unchanged" | "skip" | "duplicate" | "error"
+	MatchedID string `json:"matched_id,omitempty"` // Serveur existant reconnu
+	Reason    string `json:"reason,omitempty"`
+}
+
+// ImportReport - Résultat (ou prévisualisation) d'un import
+type ImportReport struct {
+	DryRun   bool         `json:"dry_run"`
+	Applied  bool         `json:"applied"` // Modifications enregistrées
+	Format   string       `json:"format"`
+	Items    []ImportItem `json:"items"`
+	Removed  []string     `json:"removed"`  // Serveurs supprimés (mode replace)
+	Settings bool         `json:"settings"` // Settings importés
+	Warnings []string     `json:"warnings"` // Éléments d'un autre outil ignorés ou traduits approximativement
+	Errors   []string     `json:"errors"`   // Import refusé si non vide
+}
+
+// serversExport - Document d'export JSON ou YAML
+type serversExport struct {
+	Kind       string            `json:"kind"`
+	Version    int               `json:"version"`
+	ExportedAt time.Time         `json:"exported_at"`
+	Servers    []exportedServer  `json:"servers"`
+	Settings   *backend.Settings `json:"settings,omitempty"`
+}
+
+// exportedServer - Serveur exporté, avec son historique récent éventuel
+type exportedServer struct {
+	Server
+	History []ServerStatus `json:"history,omitempty"`
+}
+
+// parsedImport - Contenu d'un fichier importé
+type parsedImport struct {
+	servers  []exportedServer
+	rows     []int // Rang (ou ligne) de chaque serveur dans le fichier
+	settings *backend.Settings
+	warnings []string
+	byLine   bool // rows contient des numéros de ligne
+}
+
+// ExportServers - Exporte la liste des serveurs en JSON, YAML ou CSV
+// L'historique récent et les settings (sans leurs secrets) ne sont disponibles
+// qu'en JSON et YAML. Les URLs de ping des checks push ne sont exportées que sur
+// demande: sans elles, l'import génère de nouvelles URLs
+func (a *App) ExportServers(options ExportOptions) (string, error) {
+	servers := a.GetServers(ServerFilter{})
+	if !options.IncludePushTokens {
+		for i := range servers {
+			if servers[i].PushToken != "" {
+				servers[i].PushToken, servers[i].URL = "", ""
+			}
+		}
+	}
+	sort.Slice(servers, func(i, j int) bool {
+		if servers[i].Name != servers[j].Name {
+			return servers[i].Name < servers[j].Name
+		}
+		return servers[i].ID < servers[j].ID
+	})
+
+	format := strings.ToLower(options.Format)
+	if format == "" {
+		format = backend.TransferJSON
+	}
+	if format == backend.TransferCSV {
+		if options.IncludeHistory || options.IncludeSettings {
+			return "", fmt.Errorf("l'export CSV ne contient que les serveurs: choisissez JSON ou YAML pour l'historique et les settings")
+		}
+		data, err := backend.MarshalCSV(servers)
+		return string(data), err
+	}
+	if format != backend.TransferJSON && format != backend.TransferYAML {
+		return "", fmt.Errorf("format d'export inconnu: %s", options.Format)
+	}
+
+	doc := serversExport{Kind: exportKind, Version: 1, ExportedAt: time.Now(), Servers: make([]exportedServer, 0, len(servers))}
+	for _, server := range servers {
+		item := exportedServer{Server: server}
+		if options.IncludeHistory {
+			item.History = a.monitor.recentHistory(server.ID)
+		}
+		doc.Servers = append(doc.Servers, item)
+	}
+	if options.IncludeSettings {
+		s, _ := a.GetSettings()
+		s = s.WithoutSecrets()
+		doc.Settings = &s
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil || format == backend.TransferJSON {
+		return string(data), err
+	}
+	data, err = backend.JSONToYAML(data)
+	return string(data), err
+}
+
+// ImportServers - Importe des serveurs depuis un contenu JSON, YAML ou CSV,
+// ou depuis la configuration d'un autre outil (Uptime Kuma, Nagios, blackbox)
+// Les serveurs déjà présents sont reconnus par identifiant et/ou URL puis
+// conservés, remplacés ou dupliqués; en mode replace, les serveurs absents du
+// fichier sont supprimés. Avec DryRun, rien n'est modifié: le compte rendu
+// décrit ce que l'import ferait. Un serveur invalide fait refuser tout l'import
+func (a *App) ImportServers(content string, options ImportOptions) (ImportReport, error) {
+	if options.Mode == "" {
+		options.Mode = importMerge
+	}
+	if options.MatchBy == "" {
+		options.MatchBy = "id_url"
+	}
+	if options.Conflict == "" {
+		options.Conflict = conflictSkip
+	}
+	if options.Mode != importMerge && options.Mode != importReplace {
+		return ImportReport{}, fmt.Errorf("mode d'import inconnu: %s", options.Mode)
+	}
+	if options.MatchBy != "id" && options.MatchBy != "url" && options.MatchBy != "id_url" {
+		return ImportReport{}, fmt.Errorf("critère de correspondance inconnu: %s", options.MatchBy)
+	}
+	if options.Conflict != conflictSkip && options.Conflict != conflictOverwrite && options.Conflict != conflictDuplicate {
+		return ImportReport{}, fmt.Errorf("résolution de conflit inconnue: %s", options.Conflict)
+	}
+
+	format := strings.ToLower(options.Format)
+	if format == "" {
+		format = backend.DetectTransferFormat([]byte(content))
+	}
+	parsed, err := parseImport([]byte(content), format)
+	if err != nil {
+		return ImportReport{}, err
+	}
+	report := ImportReport{DryRun: options.DryRun, Format: format, Items: []ImportItem{}, Removed: []string{}, Warnings: []string{}, Errors: []string{}}
+	report.Warnings = append(report.Warnings, parsed.warnings...)
+	position := "élément"
+	if parsed.byLine {
+		position = "ligne"
+	}
+
+	existing := a.GetServers(ServerFilter{})
+	byID := make(map[string]*Server, len(existing))
+	byURL := make(map[string]*Server, len(existing))
+	taken := make(map[string]bool, len(existing))
+	final := make(map[string]Server, len(existing))
+	for i := range existing {
+		server := &existing[i]
+		byID[server.ID] = server
+		if _, ok := byURL[server.URL]; !ok {
+			byURL[server.URL] = server
+		}
+		taken[server.ID] = true
+		if options.Mode == importMerge {
+			final[server.ID] = *server
+		}
+	}
+
+	// Jetons de ping déjà attribués (un jeton ne désigne qu'un seul serveur)
+	tokens := make(map[string]string, len(final))
+	for id, server := range final {
+		if server.PushToken != "" {
+			tokens[server.PushToken] = id
+		}
+	}
+
+	matched := make(map[string]int) // Serveur existant → rang de l'élément qui l'a reconnu
+	seen := make(map[string]int)    // Identifiant explicite → rang de l'élément
+	histories := make(map[string][]ServerStatus)
+	refs := make(map[string]string) // Identifiant (ou nom) dans le fichier → identifiant final
+	imported := make(map[string]bool)
+	for i, item := range parsed.servers {
+		server := item.Server
+		entry := ImportItem{Row: parsed.rows[i], ID: server.ID, Name: server.Name, URL: server.URL}
+		ref := server.ID
+		if ref == "" {
+			ref = server.Name
+		}
+		fail := func(format string, args ...any) {
+			entry.Action, entry.Reason = "error", fmt.Sprintf(format, args...)
+			report.Errors = append(report.Errors, fmt.Sprintf("%s %d (%s): %s", position, entry.Row, entry.Name, entry.Reason))
+			report.Items = append(report.Items, entry)
+		}
+
+		if server.ID != "" {
+			if row, dup := seen[server.ID]; dup {
+				fail("identifiant %q déjà utilisé à l'%s %d", server.ID, position, row)
+				continue
+			}
+			seen[server.ID] = entry.Row
+		}
+		var match *Server
+		if options.MatchBy != "url" {
+			match = byID[server.ID]
+		}
+		if match == nil && options.MatchBy != "id" && server.URL != "" {
+			match = byURL[server.URL]
+		}
+		if match != nil {
+			if row, dup := matched[match.ID]; dup {
+				fail("correspond au même serveur existant que l'%s %d", position, row)
+				continue
+			}
+			matched[match.ID] = entry.Row
+			entry.MatchedID = match.ID
+		}
+
+		switch {
+		case match != nil && options.Conflict == conflictSkip:
+			entry.Action, entry.Reason = "skip", fmt.Sprintf("déjà présent: %s", match.Name)
+			final[match.ID] = *match
+			if match.PushToken != "" {
+				tokens[match.PushToken] = match.ID
+			}
+			refs[ref] = match.ID
+			report.Items = append(report.Items, entry)
+			continue
+		case match != nil && options.Conflict == conflictOverwrite:
+			server.ID, server.Status = match.ID, match.Status
+			if owner, ok := tokens[server.PushToken]; server.PushToken == "" || ok && owner != match.ID {
+				server.PushToken = match.PushToken
+			}
+			entry.Action = "update"
+			if sameServerConfig(*match, server) {
+				entry.Action = "unchanged"
+			}
+		default:
+			entry.Action = "add"
+			if match != nil {
+				// La copie reçoit sa propre URL de ping
+				entry.Action = "duplicate"
+				server.Status, server.PushToken = ServerStatus{}, ""
+			}
+			if server.ID == "" || taken[server.ID] {
+				server.ID = newServerID(taken)
+			}
+		}
+		if owner, ok := tokens[server.PushToken]; ok && server.PushToken != "" && owner != server.ID {
+			// Jeton déjà attribué à un autre serveur: une nouvelle URL est générée
+			server.PushToken = ""
+			report.Warnings = append(report.Warnings, fmt.Sprintf("%s: URL de ping déjà utilisée par un autre serveur, une nouvelle URL est générée", server.Name))
+		}
+		if err := validateServer(&server); err != nil {
+			fail("%s", err)
+			continue
+		}
+		if server.Type == "push" && entry.Action != "unchanged" && !a.pushListenerEnabled() {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("%s: réception des pings désactivée, ce check push passera DOWN tant que pushListenAddr n'est pas configurée", server.Name))
+		}
+		if server.Type == "email-heartbeat" && entry.Action != "unchanged" && !a.heartbeatListenerEnabled() {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("%s: réception des emails de heartbeat désactivée, ce check passera DOWN tant que heartbeatSmtpAddr n'est pas configurée", server.Name))
+		}
+		entry.ID = server.ID
+		taken[server.ID] = true
+		final[server.ID] = server
+		if server.PushToken != "" {
+			tokens[server.PushToken] = server.ID
+		}
+		refs[ref] = server.ID
+		imported[server.ID] = true
+		if len(item.History) > 0 {
+			histories[server.ID] = item.History
+		}
+		report.Items = append(report.Items, entry)
+	}
+	for _, server := range existing {
+		if _, ok := final[server.ID]; !ok {
+			report.Removed = append(report.Removed, server.Name)
+		}
+	}
+
+	// Les parents d'un serveur importé désignent un serveur du fichier (par
+	// identifiant, ou par nom sans identifiant), sinon un serveur existant
+	for id := range imported {
+		server := final[id]
+		if len(server.Parents) == 0 {
+			continue
+		}
+		parents := make([]string, len(server.Parents))
+		for i, parent := range server.Parents {
+			parents[i] = parent
+			if target, ok := refs[parent]; ok {
+				parents[i] = target
+			}
+		}
+		server.Parents = backend.NormalizeParents(parents)
+		final[id] = server
+	}
+	if len(report.Errors) == 0 {
+		servers := make([]Server, 0, len(final))
+		for _, server := range final {
+			servers = append(servers, server)
+		}
+		if id, err := checkDependencies(servers); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %s", final[id].Name, err))
+		}
+	}
+
+	var merged backend.Settings
+	if options.Settings && parsed.settings != nil {
+		current, _ := a.GetSettings()
+		merged = parsed.settings.KeepSecrets(current)
+		if err := merged.Validate(); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("settings: %s", err))
+		} else {
+			report.Settings = true
+		}
+	}
+
+	if options.DryRun || len(report.Errors) > 0 {
+		return report, nil
+	}
+	servers := make([]Server, 0, len(final))
+	for _, server := range final {
+		servers = append(servers, server)
+	}
+	added, updated, removed := a.monitor.ApplyServers(servers)
+	for id, history := range histories {
+		a.monitor.setHistory(id, history)
+	}
+	if err := a.monitor.SaveServersToFile(); err != nil {
+		return report, err
+	}
+	if report.Settings {
+		if err := a.applySettings(merged, true); err != nil {
+			return report, err
+		}
+	}
+	report.Applied = true
+	log.Printf("📥 Import %s: %d ajouté(s), %d modifié(s), %d supprimé(s)", format, len(added), len(updated), len(removed))
+	return report, nil
+}
+
+// parseImport - Serveurs, rang (ou ligne) de chacun et settings d'un contenu importé
+// JSON et YAML acceptent un document d'export, un servers.json (toutes
+// versions du schéma) ou une simple liste de serveurs; les configurations
+// des autres outils sont traduites par leur importeur
+func parseImport(content []byte, format string) (*parsedImport, error) {
+	parsed := &parsedImport{}
+	if importer, ok := backend.ForeignImporters[format]; ok {
+		foreign, err := importer(content)
+		if err != nil {
+			return nil, err
+		}
+		parsed.warnings, parsed.byLine = foreign.Warnings, foreign.ByLine
+		for _, f := range foreign.Servers {
+			server := Server{Name: f.Name, URL: f.URL, Type: f.Type, Interval: f.Interval, Timeout: f.Timeout,
+				PushToken: f.PushToken, Group: f.Group, Tags: f.Tags, Paused: f.Paused, Parents: f.Parents}
+			parsed.servers = append(parsed.servers, exportedServer{Server: server})
+			parsed.rows = append(parsed.rows, f.Line)
+		}
+		return parsed, nil
+	}
+
+	switch format {
+	case backend.TransferCSV:
+		var list []Server
+		lines, err := backend.UnmarshalCSV(content, &list)
+		if err != nil {
+			return nil, err
+		}
+		for _, server := range list {
+			parsed.servers = append(parsed.servers, exportedServer{Server: server})
+		}
+		parsed.rows, parsed.byLine = lines, true
+		return parsed, nil
+	case backend.TransferYAML:
+		data, err := backend.YAMLToJSON(content)
+		if err != nil {
+			return nil, err
+		}
+		content = data
+	case backend.TransferJSON:
+	default:
+		return nil, fmt.Errorf("format d'import inconnu: %s", format)
+	}
+
+	var probe map[string]json.RawMessage
+	trimmed := bytes.TrimSpace(content)
+	switch {
+	case len(trimmed) > 0 && trimmed[0] == '[':
+		if err := json.Unmarshal(content, &parsed.servers); err != nil {
+			return nil, backend.LocateJSONError(content, err)
+		}
+	case json.Unmarshal(content, &probe) != nil:
+		var v any
+		return nil, backend.LocateJSONError(content, json.Unmarshal(content, &v))
+	case probe["schema_version"] != nil:
+		data, err := backend.MigrateConfigData(backend.ConfigKindServers, content)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &parsed.servers); err != nil {
+			return nil, err
+		}
+	case probe["servers"] != nil:
+		var doc serversExport
+		if err := json.Unmarshal(content, &doc); err != nil {
+			return nil, backend.LocateJSONError(content, err)
+		}
+		if doc.Kind != "" && doc.Kind != exportKind {
+			return nil, fmt.Errorf("document de type %q, export de serveurs attendu", doc.Kind)
+		}
+		parsed.servers, parsed.settings = doc.Servers, doc.Settings
+	default:
+		return nil, fmt.Errorf("document non reconnu: liste de serveurs ou export attendu")
+	}
+	for i := range parsed.servers {
+		parsed.rows = append(parsed.rows, i+1)
+	}
+	return parsed, nil
+}
+
+// newServerID - Identifiant inédit (même forme que ceux d'AddServer)
+func newServerID(taken map[string]bool) string {
+	n := time.Now().UnixNano()
+	for taken[strconv.FormatInt(n, 10)] {
+		n++
+	}
+	return strconv.FormatInt(n, 10)
+}
+
+// Utilitaires
 func parseDuration(s string) (time.Duration, error) {
 	// Convertir les formats comme "30s", "1m", "5m" en time.Duration
 	if s == "" {
@@ -571,18 +2289,44 @@ func (a *App) ClearNotificationCooldowns() {
 // Méthode pour envoyer un résumé des serveurs en panne
 func (a *App) SendDownServersSummary() {
 	a.monitor.mutex.RLock()
-	defer a.monitor.mutex.RUnlock()
-
 	var downServers []string
+	var downDetails []Server
 	for _, server := range a.monitor.servers {
-		if !server.Status.IsUp {
+		// Les serveurs en maintenance ne sont pas signalés, ni les serveurs
+		// injoignables (leur parent en panne l'est déjà)
+		if !server.Status.IsUp && server.Status.Maintenance == "" && !server.Status.Unreachable {
 			downServers = append(downServers, server.Name)
+			downDetails = append(downDetails, *server)
 		}
 	}
+	a.monitor.mutex.RUnlock()
+
+	if len(downServers) == 0 {
+		return
+	}
+
+	a.settingsMu.RLock()
+	mode := a.settings.NotificationMode
+	a.settingsMu.RUnlock()
 
-	if len(downServers) > 0 {
-		a.monitor.Notifier.SendSummary(downServers)
+	// En mode email, le résumé est envoyé avec le modèle SUMMARY
+	if mode == "email" {
+		if !a.notifier.ShouldNotify("SUMMARY", "DOWN_SUMMARY") {
+			return
+		}
+		go func() {
+			if err := a.sendSummaryEmail(downDetails); err != nil {
+				log.Printf("❌ Erreur résumé email: %s", err)
+			}
+		}()
+		return
 	}
+	a.monitor.Notifier.SendSummary(downServers)
+}
+
+// GetServerHistory - Récupère les dernières vérifications d'un serveur
+func (a *App) GetServerHistory(id string) []ServerStatus {
+	return a.monitor.recentHistory(id)
 }
 
 func (a *App) ManualCheck(server Server) ServerStatus {
@@ -619,10 +2363,32 @@ func (a *App) GetSettings() (backend.Settings, error) {
 
 // SaveSettings reçoit une struct Settings depuis le frontend et la persiste
 func (a *App) SaveSettings(s backend.Settings) error {
+	// Refuser une configuration invalide avant toute modification
+	// (NotificationManager, monitoring, fichier)
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	return a.applySettings(s, true)
+}
+
+// applySettings - Applique des settings déjà validés: notifications, seuils
+// critiques, écoutes des checks passifs; persist les écrit dans settings.json
+func (a *App) applySettings(s backend.Settings, persist bool) error {
 	a.settingsMu.Lock()
 	defer a.settingsMu.Unlock()
+	heartbeatChanged := a.settings.HeartbeatSMTPAddr != s.HeartbeatSMTPAddr
+	pushChanged := a.settings.PushListenAddr != s.PushListenAddr
+
+	// 1. Écrire dans le fichier settings.json avant toute modification en
+	// mémoire: un échec laisse la configuration en cours intacte
+	if persist {
+		if err := backend.SaveSettings(s); err != nil {
+			return err
+		}
+	}
 
-	// 1. Mettre à jour le NotificationManager
+	// 2. Mettre à jour le NotificationManager
 	switch s.NotificationMode {
 	case "inapp":
 		a.notifier.SetEnabled(true)
@@ -634,128 +2400,440 @@ func (a *App) SaveSettings(s backend.Settings) error {
 		a.notifier.SetEnabled(true)
 	}
 	a.notifier.SetCooldown(s.NotificationCooldown)
+	a.monitor.SetCriticalPolicy(s.CriticalPolicy())
+	a.monitor.SetMaintenanceWindows(s.MaintenanceWindows)
+	a.monitor.SetQuietHours(s.QuietPolicy())
 
-	// 2. Mettre à jour la valeur en mémoire
+	// 3. Mettre à jour la valeur en mémoire
 	a.settings = s
 
-	// 3. Écrire dans le fichier settings.json
-	if err := backend.SaveSettings(a.settings); err != nil {
-		return err
+	// 4. Rouvrir l'écoute des emails de heartbeat et des pings si leur adresse a changé
+	if heartbeatChanged {
+		go func() {
+			if err := a.RestartEmbeddedSMTP(); err != nil {
+				log.Printf("❌ Erreur redémarrage SMTP: %s", err)
+			}
+		}()
+	}
+	if pushChanged {
+		go func() {
+			if err := a.RestartPushServer(); err != nil {
+				log.Printf("❌ Erreur redémarrage du serveur de pings: %s", err)
+			}
+		}()
 	}
 
 	return nil
 }
 
+// reloadSettings - Applique settings.json modifié hors de l'application
+// Un fichier invalide est ignoré: la configuration en cours est conservée
+func (a *App) reloadSettings() {
+	s, err := backend.ReloadSettings()
+	if err == nil {
+		err = s.Validate()
+	}
+	if err != nil {
+		log.Printf("❌ settings.json ignoré, configuration en cours conservée: %s", err)
+		a.emitConfigReloaded("settings", err)
+		return
+	}
+	if err := a.applySettings(s, false); err != nil {
+		log.Printf("❌ Application de settings.json impossible: %s", err)
+		a.emitConfigReloaded("settings", err)
+		return
+	}
+	log.Printf("✅ settings.json rechargé")
+	a.emitConfigReloaded("settings", nil)
+}
+
+// reloadServers - Applique servers.json modifié hors de l'application
+// Seuls les serveurs ajoutés, supprimés ou modifiés sont (re)démarrés; un
+// fichier ou un serveur invalide laisse la surveillance en cours inchangée
+func (a *App) reloadServers() {
+	servers, err := readServersFile(0)
+	if err == nil {
+		seen := make(map[string]bool, len(servers))
+		for i := range servers {
+			server := &servers[i]
+			if server.ID == "" || seen[server.ID] {
+				err = fmt.Errorf("serveur %q: identifiant absent ou en double", server.Name)
+				break
+			}
+			seen[server.ID] = true
+			if err = validateServer(server); err != nil {
+				err = fmt.Errorf("serveur %q: %s", server.Name, err)
+				break
+			}
+		}
+	}
+	// Mêmes contrôles des dépendances que l'interface, l'import et la configuration déclarative
+	if err == nil {
+		if id, derr := checkDependencies(servers); derr != nil {
+			for _, server := range servers {
+				if server.ID == id {
+					err = fmt.Errorf("serveur %q: %s", server.Name, derr)
+				}
+			}
+		}
+	}
+	if err != nil {
+		log.Printf("❌ servers.json ignoré, surveillance en cours conservée: %s", err)
+		a.emitConfigReloaded("servers", err)
+		return
+	}
+
+	added, updated, removed := a.monitor.ApplyServers(servers)
+	log.Printf("✅ servers.json rechargé: %d ajouté(s), %d modifié(s), %d supprimé(s)",
+		len(added), len(updated), len(removed))
+	a.emitConfigReloaded("servers", nil)
+}
+
+// loadDeclarativeConfig - Serveurs et settings d'un fichier déclaratif YAML ou TOML
+// Les settings du fichier s'appliquent sur base (nil si le fichier n'en déclare
+// pas); known fournit les serveurs déjà connus par identifiant, dont le statut
+// et le jeton de ping sont repris. Toutes les erreurs sont localisées par
+// fichier et ligne
+func loadDeclarativeConfig(path string, base backend.Settings, known map[string]Server) ([]Server, *backend.Settings, backend.ConfigErrors) {
+	config, errs := backend.LoadDeclarativeConfig(path)
+	var settings *backend.Settings
+	if config.HasSettings() {
+		applied, settingsErrs := config.ApplySettings(base)
+		errs = append(errs, settingsErrs...)
+		settings = &applied
+	}
+
+	servers := make([]Server, 0, len(config.Servers))
+	seen := make(map[string]bool, len(config.Servers))
+	declarations := make(map[string]backend.DeclaredServer, len(config.Servers))
+	for _, declared := range config.Servers {
+		server, cerr := decodeDeclaredServer(declared)
+		if cerr != nil {
+			errs = append(errs, *cerr)
+			continue
+		}
+		// Sans identifiant explicite, le nom sert d'identifiant (stable d'un démarrage à l'autre)
+		if server.ID == "" {
+			server.ID = server.Name
+		}
+		if seen[server.ID] {
+			errs = append(errs, declared.Error("id", "identifiant %q en double", server.ID))
+			continue
+		}
+		seen[server.ID] = true
+		// Sans groupe explicite, le serveur prend le groupe où il est déclaré
+		if server.Group == "" {
+			server.Group = declared.Group
+		}
+		if previous, ok := known[server.ID]; ok {
+			server.Status = previous.Status
+			if server.PushToken == "" {
+				server.PushToken = previous.PushToken
+			}
+			// Une pause décidée dans l'application survit aux rechargements
+			if _, ok := declared.Data["paused"]; !ok {
+				server.Paused = previous.Paused
+			}
+		}
+		if err := validateServer(&server); err != nil {
+			errs = append(errs, declared.Error("", "%s", err))
+			continue
+		}
+		declarations[server.ID] = declared
+		servers = append(servers, server)
+	}
+	// Les parents désignent des serveurs déclarés (par identifiant, ou par nom sans identifiant)
+	if id, err := checkDependencies(servers); err != nil {
+		errs = append(errs, declarations[id].Error("parents", "%s", err))
+	}
+	return servers, settings, errs.Sorted()
+}
+
+// decodeDeclaredServer - Server à partir des champs d'un serveur déclaré
+// Les durées peuvent être écrites en nombre de secondes (interval: 30)
+func decodeDeclaredServer(declared backend.DeclaredServer) (Server, *backend.ConfigError) {
+	var server Server
+	fail := func(field, format string, args ...any) (Server, *backend.ConfigError) {
+		err := declared.Error(field, format, args...)
+		return server, &err
+	}
+	if _, ok := declared.Data["status"]; ok {
+		return fail("status", "le statut n'est pas configurable")
+	}
+	fields := make(map[string]any, len(declared.Data))
+	for key, value := range declared.Data {
+		switch value.(type) {
+		case int, float64:
+			if key == "interval" || key == "timeout" || key == "critical_after" || key == "grace" {
+				value = fmt.Sprint(value)
+			}
+		}
+		fields[key] = value
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return fail("", "%s", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&server); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return fail(typeErr.Field, "%s: type %s attendu", typeErr.Field, typeErr.Type)
+		}
+		if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			field, _ = strconv.Unquote(field)
+			return fail(field, "champ inconnu: %s", field)
+		}
+		return fail("", "%s", err)
+	}
+	return server, nil
+}
+
+// applyDeclarativeConfig - Remplace serveurs et settings par ceux du fichier
+// déclaratif; running indique que le monitoring tourne déjà (rechargement à
+// chaud). Un fichier invalide ne modifie rien
+func (a *App) applyDeclarativeConfig(running bool) error {
+	a.monitor.mutex.RLock()
+	known := make(map[string]Server, len(a.monitor.servers))
+	for id, server := range a.monitor.servers {
+		known[id] = *server
+	}
+	a.monitor.mutex.RUnlock()
+	current, _ := a.GetSettings()
+
+	servers, settings, errs := loadDeclarativeConfig(a.configFile, current, known)
+	if len(errs) > 0 {
+		return errs
+	}
+
+	// Sans settings dans le fichier, ceux de l'application restent en place
+	if settings != nil {
+		if err := a.applySettings(*settings, true); err != nil {
+			return err
+		}
+	}
+	if running {
+		added, updated, removed := a.monitor.ApplyServers(servers)
+		log.Printf("📄 %s appliqué: %d ajouté(s), %d modifié(s), %d supprimé(s)",
+			a.configFile, len(added), len(updated), len(removed))
+	} else {
+		a.monitor.replaceServers(servers)
+		log.Printf("📄 %s appliqué: %d serveur(s)", a.configFile, len(servers))
+	}
+	// servers.json reste le reflet de la configuration en cours (statuts compris)
+	return a.monitor.SaveServersToFile()
+}
+
+// reloadDeclarativeConfig - Applique le fichier déclaratif modifié sur le disque
+func (a *App) reloadDeclarativeConfig() {
+	if err := a.applyDeclarativeConfig(true); err != nil {
+		log.Printf("❌ %s ignoré, configuration en cours conservée:\n%s", a.configFile, err)
+		a.emitConfigReloaded("config", err)
+		return
+	}
+	a.emitConfigReloaded("config", nil)
+}
+
+// ConfigReloadEvent - Résultat d'un rechargement à chaud envoyé au frontend
+type ConfigReloadEvent struct {
+	File  string `json:"file"`            // "settings", "servers" ou "config" (fichier déclaratif)
+	Error string `json:"error,omitempty"` // Raison du refus (configuration en cours conservée)
+}
+
+// emitConfigReloaded - Prévient le frontend qu'un fichier a été rechargé (ou refusé)
+func (a *App) emitConfigReloaded(file string, err error) {
+	if a.ctx == nil {
+		return
+	}
+	event := ConfigReloadEvent{File: file}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	wailsruntime.EventsEmit(a.ctx, "config:reloaded", event)
+}
+
 // Implémentation du backend SMTP
 func (b *EmbeddedSMTP) NewSession(c *smtp.Conn) (smtp.Session, error) {
 	return &SMTPSession{backend: b}, nil
 }
 
 type SMTPSession struct {
-	backend *EmbeddedSMTP
-	from    string
-	to      []string
+	backend       *EmbeddedSMTP
+	from          string
+	to            []string
+	heartbeat     []string // Destinataires surveillés par un check email-heartbeat (non relayés)
+	authenticated bool     // Le client s'est authentifié avec les identifiants générés
 }
 
-func (s *SMTPSession) AuthPlain(username, password string) error {
-	// Pas d'auth nécessaire pour notre usage interne
-	return nil
+// errRecipientNotAllowed - Refus d'un destinataire hors liste blanche
+var errRecipientNotAllowed = &smtp.SMTPError{
+	Code:         550,
+	EnhancedCode: smtp.EnhancedCode{5, 7, 1},
+	Message:      "Destinataire non autorisé par le relais",
+}
+
+// AuthMechanisms - Mécanismes d'authentification proposés (PLAIN uniquement)
+func (s *SMTPSession) AuthMechanisms() []string {
+	return []string{sasl.Plain}
 }
 
+// Auth - Vérifie les identifiants générés au démarrage du serveur
+func (s *SMTPSession) Auth(mech string) (sasl.Server, error) {
+	return sasl.NewPlainServer(func(identity, username, password string) error {
+		userOK := subtle.ConstantTimeCompare([]byte(username), []byte(s.backend.username)) == 1
+		passOK := subtle.ConstantTimeCompare([]byte(password), []byte(s.backend.password)) == 1
+		if !userOK || !passOK {
+			return smtp.ErrAuthFailed
+		}
+		s.authenticated = true
+		return nil
+	}), nil
+}
+
+// Mail - Accepté sans authentification: seuls les emails de heartbeat
+// peuvent ensuite être déposés par un client anonyme (voir Rcpt)
 func (s *SMTPSession) Mail(from string, opts *smtp.MailOptions) error {
 	s.from = from
 	return nil
 }
 
 func (s *SMTPSession) Rcpt(to string, opts *smtp.RcptOptions) error {
+	// Les emails de heartbeat sont consommés localement, sans authentification
+	if s.backend.isHeartbeat != nil && s.backend.isHeartbeat(to) {
+		s.heartbeat = append(s.heartbeat, to)
+		return nil
+	}
+	if !s.authenticated {
+		return smtp.ErrAuthRequired
+	}
+	// Empêcher l'utilisation du relais vers des adresses arbitraires
+	if s.backend.allowRecipient != nil && !s.backend.allowRecipient(to) {
+		log.Printf("🚫 Destinataire refusé par le relais: %s", to)
+		return errRecipientNotAllowed
+	}
 	s.to = append(s.to, to)
 	return nil
 }
 
 func (s *SMTPSession) Data(r io.Reader) error {
+	if !s.authenticated && len(s.heartbeat) == 0 {
+		return smtp.ErrAuthRequired
+	}
+
 	// Lire le contenu de l'email
 	data, err := io.ReadAll(r)
 	if err != nil {
 		return err
 	}
 
-	// Parser le contenu basique
-	content := string(data)
-	lines := strings.Split(content, "\n")
-
-	var subject, body string
-	var inHeaders = true
-
-	for _, line := range lines {
-		if inHeaders {
-			if strings.HasPrefix(line, "Subject: ") {
-				subject = strings.TrimPrefix(line, "Subject: ")
-			}
-			if line == "" {
-				inHeaders = false
-			}
-		} else {
-			body += line + "\n"
-		}
+	// Décoder le message MIME (corps texte/HTML, sujet, Reply-To, pièces jointes)
+	parsed, err := backend.ParseEmail(data)
+	if errors.Is(err, backend.ErrEmailTooLarge) {
+		return smtp.ErrDataTooLarge
 	}
-
-	// Stocker l'email (ici on pourrait l'envoyer vraiment)
-	email := EmailMessage{
-		From:    s.from,
-		To:      s.to,
-		Subject: subject,
-		Body:    strings.TrimSpace(body),
-		Time:    time.Now(),
+	if err != nil {
+		log.Printf("❌ Email reçu illisible: %s", err)
+		return &smtp.SMTPError{
+			Code:         554,
+			EnhancedCode: smtp.EnhancedCode{5, 6, 0},
+			Message:      "Message MIME invalide",
+		}
 	}
 
-	s.backend.emails = append(s.backend.emails, email)
-
-	// ICI: Envoyer l'email vers la vraie destination
-	if s.backend.smtpConfig.Host != "" {
-		go s.forwardToRealEmail(email, s.backend.smtpConfig)
-	} else {
-		log.Printf("⚠️ Pas de configuration SMTP, email stocké localement uniquement")
+	// Signaler les emails de heartbeat aux checks concernés
+	if len(s.heartbeat) > 0 && s.backend.onHeartbeat != nil {
+		s.backend.onHeartbeat(s.heartbeat, parsed.Subject)
+	}
+	if len(s.to) == 0 {
+		return nil // Rien à relayer
+	}
+
+	// Placer l'email dans la file persistante: le worker le relaie vers la
+	// vraie destination et réessaie en cas d'indisponibilité du relais
+	to, cc, bcc := backend.SplitRecipients(parsed.Header, s.to)
+	queued, err := s.backend.queue.Enqueue(backend.QueuedEmail{
+		From:        s.from,
+		To:          to,
+		Cc:          cc,
+		Bcc:         bcc,
+		ReplyTo:     parsed.ReplyTo,
+		Subject:     parsed.Subject,
+		Body:        parsed.Text,
+		HTML:        parsed.HTML,
+		Attachments: parsed.Attachments,
+	})
+	if err != nil {
+		log.Printf("❌ Email refusé par le relais: %s", err)
+		return &smtp.SMTPError{
+			Code:         451,
+			EnhancedCode: smtp.EnhancedCode{4, 3, 5},
+			Message:      "Relais SMTP non configuré",
+		}
 	}
+	log.Printf("📥 Email %s mis en file pour %v (%d pièce(s) jointe(s))", queued.ID, queued.To, len(queued.Attachments))
 
 	return nil
 }
 
-func (s *SMTPSession) Reset()        {}
+// Reset - Réinitialise l'enveloppe entre deux messages (l'authentification est conservée)
+func (s *SMTPSession) Reset() {
+	s.from = ""
+	s.to = nil
+	s.heartbeat = nil
+}
+
 func (s *SMTPSession) Logout() error { return nil }
 
-// Forwarding vers le vrai destinataire
-// Dans votre main.go, remplacez la fonction forwardToRealEmail par :
-func (s *SMTPSession) forwardToRealEmail(email EmailMessage, smtpConfig backend.SMTPConfig) {
-	if smtpConfig.Host == "" || smtpConfig.Username == "" {
-		log.Printf("❌ Configuration SMTP incomplète, email non envoyé")
+// relayQueuedEmail - Fonction d'envoi de la file d'emails
+// Utilise la configuration SMTP courante à chaque tentative
+func (a *App) relayQueuedEmail(email backend.QueuedEmail) error {
+	return forwardToRealEmail(email, a.GetSMTPConfig())
+}
+
+// persistRefreshToken - Enregistre (via le stockage des secrets) le refresh
+// token OAuth2 remplacé par le fournisseur, si la configuration SMTP en cours
+// est bien celle qui l'a utilisé (pas une configuration en test)
+func (a *App) persistRefreshToken(previous backend.OAuth2Config, refreshToken string) {
+	a.settingsMu.Lock()
+	defer a.settingsMu.Unlock()
+
+	current := a.settings.SMTPConfig.OAuth2
+	if current == nil || current.TokenURL != previous.TokenURL ||
+		current.ClientID != previous.ClientID || current.RefreshToken != previous.RefreshToken {
 		return
 	}
+	s := a.settings
+	oauth := *current
+	oauth.RefreshToken = refreshToken
+	s.SMTPConfig.OAuth2 = &oauth
+	if err := backend.SaveSettings(s); err != nil {
+		log.Printf("❌ Refresh token OAuth2 renouvelé mais non enregistré: %s", err)
+		return
+	}
+	a.settings = s
+	log.Printf("🔐 Refresh token OAuth2 renouvelé par le fournisseur, enregistré")
+}
 
-	log.Printf("📧 Envoi email via %s:%d vers %v", smtpConfig.Host, smtpConfig.Port, email.To)
-
-	// Nettoyer le mot de passe pour Gmail
-	cleanPassword := cleanAppPassword(smtpConfig.Password)
+// relayReady - Refuse la mise en file tant que la configuration SMTP ne
+// permet pas de relayer (l'email serait abandonné après toutes ses tentatives)
+func (a *App) relayReady() error {
+	return a.GetSMTPConfig().CheckRelay()
+}
 
-	// Créer le client avec timeout plus long
-	c, err := mail.NewClient(smtpConfig.Host,
-		mail.WithPort(smtpConfig.Port),
-		mail.WithTimeout(30*time.Second),
-	)
-	if err != nil {
-		log.Printf("❌ Erreur création client SMTP: %s", err)
-		return
+// Forwarding vers le vrai destinataire
+// Retourne une erreur pour que la file planifie une nouvelle tentative
+func forwardToRealEmail(email backend.QueuedEmail, smtpConfig backend.SMTPConfig) error {
+	if err := smtpConfig.CheckRelay(); err != nil {
+		return err
 	}
 
-	// Configuration de l'authentification
-	c.SetSMTPAuth(mail.SMTPAuthPlain)
-	c.SetUsername(smtpConfig.Username)
-	c.SetPassword(cleanPassword)
+	log.Printf("📧 Envoi email via %s:%d vers %v", smtpConfig.Host, smtpConfig.Port, email.To)
 
-	// Configuration TLS
-	if smtpConfig.TLS {
-		c.SetTLSPolicy(mail.TLSMandatory)
-	} else {
-		c.SetTLSPolicy(mail.NoTLS)
+	// Créer le client avec timeout plus long
+	c, err := newSMTPClient(smtpConfig, 30*time.Second)
+	if err != nil {
+		return err
 	}
 
 	// Créer le message
@@ -768,54 +2846,129 @@ func (s *SMTPSession) forwardToRealEmail(email EmailMessage, smtpConfig backend.
 	}
 
 	if err := m.From(fromAddr); err != nil {
-		log.Printf("❌ Erreur adresse From: %s", err)
-		return
+		return fmt.Errorf("erreur adresse From: %s", err)
 	}
 
-	// Envoyer à tous les destinataires
+	// Envoyer à tous les destinataires (To, CC, BCC)
 	for _, to := range email.To {
-		if err := m.To(to); err != nil {
+		if err := m.AddTo(to); err != nil {
 			log.Printf("❌ Erreur adresse To: %s", err)
 			continue
 		}
 	}
+	for _, cc := range email.Cc {
+		if err := m.AddCc(cc); err != nil {
+			log.Printf("❌ Erreur adresse CC: %s", err)
+			continue
+		}
+	}
+	for _, bcc := range email.Bcc {
+		if err := m.AddBcc(bcc); err != nil {
+			log.Printf("❌ Erreur adresse BCC: %s", err)
+			continue
+		}
+	}
+	// Sans destinataire direct, ne jamais exposer les copies cachées
+	if len(m.GetToString()) == 0 {
+		m.SetGenHeaderPreformatted(mail.Header(mail.HeaderTo), undisclosedRecipients)
+	}
+
+	// Conserver l'adresse de réponse de l'email d'origine
+	if len(email.ReplyTo) > 0 {
+		replyTo := make([]string, 0, len(email.ReplyTo))
+		for _, addr := range email.ReplyTo {
+			parsed, err := netmail.ParseAddress(addr)
+			if err != nil {
+				log.Printf("❌ Erreur adresse Reply-To: %s", err)
+				continue
+			}
+			replyTo = append(replyTo, parsed.String())
+		}
+		if len(replyTo) > 0 {
+			m.SetGenHeaderPreformatted(mail.HeaderReplyTo, strings.Join(replyTo, ", "))
+		}
+	}
 
 	m.Subject(email.Subject)
 	m.SetBodyString(mail.TypeTextPlain, email.Body)
+	if email.HTML != "" {
+		m.AddAlternativeString(mail.TypeTextHTML, email.HTML)
+	}
+
+	// Pièces jointes et images intégrées (référencées par cid: dans le HTML)
+	for _, attachment := range email.Attachments {
+		opts := []mail.FileOption{mail.WithFileContentType(mail.ContentType(attachment.ContentType))}
+		var err error
+		if attachment.Inline {
+			opts = append(opts, mail.WithFileContentID("<"+attachment.ContentID+">"))
+			err = m.EmbedReader(attachment.Filename, bytes.NewReader(attachment.Data), opts...)
+		} else {
+			err = m.AttachReader(attachment.Filename, bytes.NewReader(attachment.Data), opts...)
+		}
+		if err != nil {
+			return fmt.Errorf("erreur pièce jointe %s: %s", attachment.Filename, err)
+		}
+	}
 
 	// Envoyer l'email
 	if err := c.DialAndSend(m); err != nil {
-		log.Printf("❌ Erreur envoi email: %s", err)
-		return
+		return fmt.Errorf("erreur envoi email: %s", err)
 	}
 
 	log.Printf("✅ Email envoyé avec succès vers %v", email.To)
+	return nil
 }
 
+// StartEmbeddedSMTP - Démarre le serveur SMTP embarqué (remplace celui en cours)
 func (a *App) StartEmbeddedSMTP() error {
+	a.smtpMu.Lock()
+	defer a.smtpMu.Unlock()
+	return a.startEmbeddedSMTPLocked()
+}
+
+// startEmbeddedSMTPLocked - Démarre le serveur SMTP embarqué (appelant verrouillé sur smtpMu)
+func (a *App) startEmbeddedSMTPLocked() error {
+	if a.smtpServer != nil {
+		a.smtpServer.Close()
+		a.smtpServer = nil
+	}
+
 	// IMPORTANT: Récupérer la config SMTP actuelle
 	a.settingsMu.RLock()
 	currentSMTPConfig := a.settings.SMTPConfig
 	a.settingsMu.RUnlock()
 
-	backend := &EmbeddedSMTP{
-		smtpConfig: currentSMTPConfig, // Utiliser la vraie config
+	// Identifiants aléatoires, connus uniquement du client interne
+	password, err := randomToken(24)
+	if err != nil {
+		return fmt.Errorf("impossible de générer les identifiants SMTP: %s", err)
+	}
+
+	relay := &EmbeddedSMTP{
+		queue:          a.mailQueue, // Les emails reçus sont relayés via la file
+		username:       "monitoring-app",
+		password:       password,
+		allowRecipient: a.relayAllowed,
+		isHeartbeat:    a.monitor.IsHeartbeatRecipient,
+		onHeartbeat:    a.monitor.RecordHeartbeatEmail,
 	}
 
-	s := smtp.NewServer(backend)
-	s.Addr = ":0" // Port automatique
+	s := smtp.NewServer(relay)
+	s.Addr = net.JoinHostPort(embeddedSMTPHost, "0") // Port automatique, boucle locale
 	s.Domain = "localhost"
+	// Pas de TLS sur la boucle locale: l'authentification en clair ne quitte pas la machine
 	s.AllowInsecureAuth = true
+	s.MaxMessageBytes = backend.MaxEmailSize
 
-	// Trouver un port libre
-	listener, err := net.Listen("tcp", ":0")
+	// Trouver un port libre (boucle locale uniquement, jamais toutes les interfaces)
+	listener, err := net.Listen("tcp", s.Addr)
 	if err != nil {
 		return fmt.Errorf("impossible de créer le serveur SMTP: %s", err)
 	}
 
-	a.smtpPort = listener.Addr().(*net.TCPAddr).Port
-	log.Printf("🚀 Serveur SMTP embarqué démarré sur le port %d", a.smtpPort)
-	log.Printf("📧 Configuration SMTP: %s:%d (TLS: %t)", currentSMTPConfig.Host, currentSMTPConfig.Port, currentSMTPConfig.TLS)
+	port := listener.Addr().(*net.TCPAddr).Port
+	log.Printf("🚀 Serveur SMTP embarqué démarré sur le port %d", port)
+	log.Printf("📧 Configuration SMTP: %s:%d (TLS: %s)", currentSMTPConfig.Host, currentSMTPConfig.Port, currentSMTPConfig.EffectiveTLSMode())
 
 	// Démarrer le serveur en arrière-plan
 	go func() {
@@ -824,97 +2977,211 @@ func (a *App) StartEmbeddedSMTP() error {
 		}
 	}()
 
+	// Écoute supplémentaire pour les emails de heartbeat envoyés par d'autres machines
+	// Sans authentification, ce port n'accepte que les destinataires email-heartbeat
+	a.settingsMu.RLock()
+	heartbeatAddr := a.settings.HeartbeatSMTPAddr
+	a.settingsMu.RUnlock()
+	if heartbeatAddr != "" {
+		heartbeatListener, err := net.Listen("tcp", heartbeatAddr)
+		if err != nil {
+			log.Printf("❌ Écoute SMTP des heartbeats impossible sur %s: %s", heartbeatAddr, err)
+		} else {
+			log.Printf("💓 Réception des emails de heartbeat sur %s", heartbeatAddr)
+			go func() {
+				if err := s.Serve(heartbeatListener); err != nil {
+					log.Printf("❌ Erreur serveur SMTP (heartbeat): %s", err)
+				}
+			}()
+		}
+	}
+
 	a.smtpServer = s
+	a.smtpPort = port
+	a.smtpRelay = relay
 	return nil
 }
 
+// embeddedSMTPClient - Port et identifiants du serveur SMTP embarqué, lus
+// ensemble sous smtpMu (serveur démarré si nécessaire)
+func (a *App) embeddedSMTPClient() (int, *EmbeddedSMTP, error) {
+	a.smtpMu.Lock()
+	defer a.smtpMu.Unlock()
+	if a.smtpServer == nil {
+		log.Printf("🚀 Démarrage du serveur SMTP embarqué...")
+		if err := a.startEmbeddedSMTPLocked(); err != nil {
+			return 0, nil, err
+		}
+	}
+	return a.smtpPort, a.smtpRelay, nil
+}
+
+// relayAllowed - Indique si le relais embarqué peut transmettre à une adresse
+func (a *App) relayAllowed(addr string) bool {
+	a.settingsMu.RLock()
+	defer a.settingsMu.RUnlock()
+	return a.settings.RelayAllowed(addr)
+}
+
+// randomToken - Génère une chaîne aléatoire hexadécimale de n octets
+func randomToken(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
 // Redémarrer le serveur SMTP quand la config change
 func (a *App) RestartEmbeddedSMTP() error {
+	a.smtpMu.Lock()
+	defer a.smtpMu.Unlock()
+
 	// Arrêter l'ancien serveur
 	if a.smtpServer != nil {
 		a.smtpServer.Close()
+		a.smtpServer = nil
 		log.Printf("🛑 Ancien serveur SMTP arrêté")
 	}
 
 	// Redémarrer avec la nouvelle config
-	return a.StartEmbeddedSMTP()
+	return a.startEmbeddedSMTPLocked()
 }
 
+// SaveSetting - Ancienne binding de SaveSettings, conservée pour le frontend
+// La validation et l'application des settings sont celles de SaveSettings
 func (a *App) SaveSetting(s backend.Settings) error {
-	a.settingsMu.Lock()
-	defer a.settingsMu.Unlock()
+	return a.SaveSettings(s)
+}
 
-	// Vérifier si la config SMTP a changé
-	smtpChanged := false
-	if a.settings.SMTPConfig.Host != s.SMTPConfig.Host ||
-		a.settings.SMTPConfig.Port != s.SMTPConfig.Port ||
-		a.settings.SMTPConfig.Username != s.SMTPConfig.Username ||
-		a.settings.SMTPConfig.Password != s.SMTPConfig.Password ||
-		a.settings.SMTPConfig.TLS != s.SMTPConfig.TLS {
-		smtpChanged = true
-	}
+// ===== Configuration des emails =====
 
-	// 1. Mettre à jour le NotificationManager
-	switch s.NotificationMode {
-	case "inapp":
-		a.notifier.SetEnabled(true)
-	case "email":
-		a.notifier.SetEnabled(true)
+// SetUserEmail - Configure l'email de l'utilisateur
+// Email utilisé pour recevoir les notifications d'alerte
+func (a *App) SetUserEmail(email string) {
+	a.settings.UserEmail = email
+	log.Printf("📧 Email configuré: %s", email)
+}
+
+// SendServerAlert - Envoie une alerte email pour un serveur down
+// Version 100% autonome utilisant le serveur SMTP embarqué
+func (a *App) SendServerAlert(serverName string) error {
+	return a.sendAlertEmail(AlertEvent{
+		Server: Server{Name: serverName},
+		Kind:   "DOWN",
+		Time:   time.Now(),
+	})
+}
+
+// handleAlert - Route une alerte du monitoring selon le mode de notification
+// inapp: notification desktop, email: email via le SMTP embarqué, none: rien
+func (a *App) handleAlert(event AlertEvent) {
+	a.settingsMu.RLock()
+	mode := a.settings.NotificationMode
+	a.settingsMu.RUnlock()
+
+	switch mode {
 	case "none":
-		a.notifier.SetEnabled(false)
+		return
+	case "email":
+		a.emailAlert(event)
 	default:
-		a.notifier.SetEnabled(true)
+		a.monitor.notifyDesktop(event)
 	}
-	a.notifier.SetCooldown(s.NotificationCooldown)
+}
 
-	// 2. Mettre à jour la valeur en mémoire
-	a.settings = s
+// emailAlert - Envoie une alerte par email en respectant le cooldown
+// Les alertes critiques ignorent le cooldown, comme en notification desktop
+func (a *App) emailAlert(event AlertEvent) {
+	if window := a.notifier.MaintenanceFor(event.Server.ID); window != "" {
+		log.Printf("🔧 Email suspendu pour %s (%s): maintenance %s", event.Server.Name, event.Kind, window)
+		return
+	}
 
-	// 3. Écrire dans le fichier settings.json
-	if err := backend.SaveSettings(a.settings); err != nil {
-		return err
+	var allowed bool
+	if event.Kind == "CRITICAL" {
+		allowed = a.notifier.ShouldNotifyCritical(event.Server.Name)
+	} else {
+		allowed = a.notifier.ShouldNotify(event.Server.Name, event.Kind)
+	}
+	if !allowed {
+		log.Printf("📧 Email bloqué par le cooldown pour %s (%s)", event.Server.Name, event.Kind)
+		return
 	}
 
-	// 4. Redémarrer le serveur SMTP si la config a changé
-	if smtpChanged && s.NotificationMode == "email" {
-		log.Printf("🔄 Configuration SMTP modifiée, redémarrage du serveur...")
-		go func() {
-			if err := a.RestartEmbeddedSMTP(); err != nil {
-				log.Printf("❌ Erreur redémarrage SMTP: %s", err)
-			}
-		}()
+	// Envoi asynchrone pour ne pas bloquer la boucle de monitoring
+	go func() {
+		if err := a.sendAlertEmail(event); err != nil {
+			log.Printf("❌ Erreur alerte email: %s", err)
+		}
+	}()
+}
+
+// sendAlertEmail - Envoie l'email correspondant à une alerte
+// Démarre le serveur SMTP embarqué si nécessaire
+func (a *App) sendAlertEmail(event AlertEvent) error {
+	email, err := a.renderAlertEmail(event)
+	if err != nil {
+		return err
 	}
+	return a.sendEmail(event.Kind, email)
+}
 
-	return nil
+// sendSummaryEmail - Envoie le résumé des serveurs en panne par email
+func (a *App) sendSummaryEmail(downServers []Server) error {
+	a.settingsMu.RLock()
+	tpl := a.settings.EmailTemplateFor(backend.EmailTemplateSummary)
+	a.settingsMu.RUnlock()
+
+	email, err := backend.RenderEmailTemplate(tpl, EmailTemplateData{
+		Kind:    backend.EmailTemplateSummary,
+		Time:    time.Now(),
+		Servers: downServers,
+	})
+	if err != nil {
+		return err
+	}
+	return a.sendEmail(backend.EmailTemplateSummary, email)
 }
 
-// ===== Configuration des emails =====
+// sendDigestEmail - Envoie le résumé des alertes retenues pendant les heures calmes
+func (a *App) sendDigestEmail(alerts []backend.DeferredAlert) error {
+	a.settingsMu.RLock()
+	tpl := a.settings.EmailTemplateFor(backend.EmailTemplateDigest)
+	a.settingsMu.RUnlock()
 
-// SetUserEmail - Configure l'email de l'utilisateur
-// Email utilisé pour recevoir les notifications d'alerte
-func (a *App) SetUserEmail(email string) {
-	a.settings.UserEmail = email
-	log.Printf("📧 Email configuré: %s", email)
+	email, err := backend.RenderEmailTemplate(tpl, EmailTemplateData{
+		Kind:   backend.EmailTemplateDigest,
+		Time:   time.Now(),
+		Alerts: alerts,
+	})
+	if err != nil {
+		return err
+	}
+	return a.sendEmail(backend.EmailTemplateDigest, email)
 }
 
-// SendServerAlert - Envoie une alerte email pour un serveur down
-// Version 100% autonome utilisant le serveur SMTP embarqué
-func (a *App) SendServerAlert(serverName string) error {
+// sendEmail - Envoie un email généré aux destinataires du type d'alerte
+// Démarre le serveur SMTP embarqué si nécessaire
+func (a *App) sendEmail(kind string, email backend.RenderedEmail) error {
+	a.settingsMu.RLock()
+	recipients := a.settings.RecipientsFor(kind)
+	a.settingsMu.RUnlock()
+
 	// Vérifier que l'email est configuré
-	if a.settings.UserEmail == "" {
+	if recipients.IsEmpty() {
 		return fmt.Errorf("email non configuré")
 	}
 
-	// Démarrer le serveur SMTP embarqué si nécessaire
-	if a.smtpServer == nil {
-		fmt.Println("🚀 Démarrage du serveur SMTP embarqué... : ", a.smtpServer)
-		if err := a.StartEmbeddedSMTP(); err != nil {
-			return err
-		}
-	}
+	// Utiliser notre propre serveur SMTP embarqué (démarré si nécessaire)
+	return a.sendViaEmbeddedSMTP(recipients, email)
+}
 
-	// Utiliser notre propre serveur SMTP embarqué
-	return a.sendViaEmbeddedSMTP(serverName)
+// hasEmailRecipients - Indique si au moins un destinataire est configuré
+func (a *App) hasEmailRecipients(kind string) bool {
+	a.settingsMu.RLock()
+	defer a.settingsMu.RUnlock()
+	return !a.settings.RecipientsFor(kind).IsEmpty()
 }
 
 // NotifyServerDown - Fonction principale pour les notifications de serveur down
@@ -924,7 +3191,7 @@ func (a *App) NotifyServerDown(serverName string) {
 	log.Printf("📱 Notification: %s DOWN", serverName)
 
 	// Email automatique via SMTP embarqué (asynchrone)
-	if a.settings.UserEmail != "" {
+	if a.hasEmailRecipients(backend.EmailTemplateDown) {
 		go func() {
 			err := a.SendServerAlert(serverName)
 			if err != nil {
@@ -937,8 +3204,8 @@ func (a *App) NotifyServerDown(serverName string) {
 // TestEmailAlert - Envoie un email de test
 // Utilise un serveur fictif pour tester la configuration email
 func (a *App) TestEmailAlert() error {
-	fmt.Println("Envoi d'une alerte de test... a ", a.settings.UserEmail)
-	if a.settings.UserEmail == "" {
+	fmt.Println("Envoi d'une alerte de test... a ", a.settings.RecipientsFor(backend.EmailTemplateDown).To)
+	if !a.hasEmailRecipients(backend.EmailTemplateDown) {
 		return fmt.Errorf("configurez votre email d'abord")
 	}
 	fmt.Println("Envoi d'une alerte de test ")
@@ -948,6 +3215,8 @@ func (a *App) TestEmailAlert() error {
 // GetSMTPPort - Obtient le port du serveur SMTP embarqué
 // Utilisé pour le debug et la configuration
 func (a *App) GetSMTPPort() int {
+	a.smtpMu.Lock()
+	defer a.smtpMu.Unlock()
 	fmt.Println("Port stmp :", a.smtpPort)
 	return a.smtpPort
 }
@@ -955,20 +3224,28 @@ func (a *App) GetSMTPPort() int {
 // StopSMTP - Arrête proprement le serveur SMTP embarqué
 // Libère les ressources et ferme le serveur
 func (a *App) StopSMTP() {
+	a.smtpMu.Lock()
+	defer a.smtpMu.Unlock()
 	if a.smtpServer != nil {
 		a.smtpServer.Close()
+		a.smtpServer = nil
 		log.Printf("🛑 Serveur SMTP embarqué arrêté")
 	}
 }
 
+// SetSMTPConfig - Remplace la configuration SMTP
+// Les settings complets sont validés avant toute modification, puis
+// appliqués et sauvegardés comme avec SaveSettings
 func (a *App) SetSMTPConfig(config backend.SMTPConfig) error {
-	a.settingsMu.Lock()
-	defer a.settingsMu.Unlock()
-
-	a.settings.SMTPConfig = config
+	a.settingsMu.RLock()
+	s := a.settings
+	a.settingsMu.RUnlock()
 
-	// Sauvegarder dans le fichier
-	return backend.SaveSettings(a.settings)
+	s.SMTPConfig = config
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	return a.applySettings(s, true)
 }
 
 // Obtenir la configuration SMTP
@@ -978,6 +3255,180 @@ func (a *App) GetSMTPConfig() backend.SMTPConfig {
 	return a.settings.SMTPConfig
 }
 
+// ValidateSettings - Erreurs de validation par champ, affichées par
+// l'interface à côté des saisies (liste vide si les settings sont valides)
+func (a *App) ValidateSettings(s backend.Settings) []backend.FieldError {
+	return backend.ValidateSettings(s)
+}
+
+// GetConfigMigrations - Mises à niveau du schéma des fichiers de configuration
+// appliquées au démarrage (versions, changements effectués)
+func (a *App) GetConfigMigrations() []backend.ConfigMigrationReport {
+	return backend.GetConfigMigrations()
+}
+
+// GetSecretStoreStatus - Stockage des secrets SMTP (trousseau ou fichier chiffré)
+func (a *App) GetSecretStoreStatus() backend.SecretStoreStatus {
+	return backend.GetSecretStoreStatus()
+}
+
+// UnlockSecretStore - Déverrouille (ou crée) le fichier de secrets chiffré
+// Les secrets encore en clair dans settings.json y sont ensuite déplacés
+func (a *App) UnlockSecretStore(passphrase string) error {
+	a.settingsMu.Lock()
+	defer a.settingsMu.Unlock()
+
+	if err := backend.UnlockSecrets(passphrase, &a.settings); err != nil {
+		return err
+	}
+	log.Printf("🔓 Fichier de secrets déverrouillé")
+	return backend.SaveSettings(a.settings)
+}
+
+// ===== File d'attente des emails sortants =====
+
+// GetMailQueue - Liste les emails en attente d'envoi ou abandonnés
+func (a *App) GetMailQueue() []backend.QueuedEmail {
+	return a.mailQueue.List()
+}
+
+// RetryQueuedEmail - Relance immédiatement l'envoi d'un email de la file
+func (a *App) RetryQueuedEmail(id string) error {
+	return a.mailQueue.Retry(id)
+}
+
+// DeleteQueuedEmail - Retire un email de la file sans l'envoyer
+func (a *App) DeleteQueuedEmail(id string) error {
+	return a.mailQueue.Delete(id)
+}
+
+// ===== Checks push (pings HTTP) =====
+
+// maxPushMessage - Longueur maximale du message joint à un ping
+const maxPushMessage = 200
+
+// StartPushServer - Démarre le serveur HTTP recevant les pings des checks push
+// Sans adresse d'écoute configurée, aucun port n'est ouvert
+func (a *App) StartPushServer() error {
+	a.pushMu.Lock()
+	defer a.pushMu.Unlock()
+	return a.startPushServerLocked()
+}
+
+// startPushServerLocked - Démarre le serveur des pings (appelant verrouillé sur pushMu)
+func (a *App) startPushServerLocked() error {
+	a.settingsMu.RLock()
+	addr := a.settings.PushListenAddr
+	a.settingsMu.RUnlock()
+	if addr == "" {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("impossible d'écouter les pings sur %s: %s", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/push/{token}", a.handlePush)
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       15 * time.Second,
+		WriteTimeout:      15 * time.Second,
+	}
+	a.pushServer = server
+	log.Printf("📍 Réception des pings sur %s", addr)
+
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Printf("❌ Erreur serveur de pings: %s", err)
+		}
+	}()
+	return nil
+}
+
+// stopPushServer - Arrête le serveur HTTP des pings s'il est démarré
+func (a *App) stopPushServer() {
+	a.pushMu.Lock()
+	defer a.pushMu.Unlock()
+	a.stopPushServerLocked()
+}
+
+// stopPushServerLocked - Arrête le serveur des pings (appelant verrouillé sur pushMu)
+func (a *App) stopPushServerLocked() {
+	if a.pushServer == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := a.pushServer.Shutdown(ctx); err != nil {
+		log.Printf("⚠️ Arrêt du serveur de pings: %s", err)
+	}
+	a.pushServer = nil
+}
+
+// RestartPushServer - Redémarre le serveur des pings (adresse d'écoute modifiée)
+func (a *App) RestartPushServer() error {
+	a.pushMu.Lock()
+	defer a.pushMu.Unlock()
+	a.stopPushServerLocked()
+	return a.startPushServerLocked()
+}
+
+// handlePush - Reçoit un ping: GET ou POST /push/<jeton>?status=up|down&msg=...
+// Sans paramètre status, le ping signale que le job s'est bien exécuté
+func (a *App) handlePush(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost && r.Method != http.MethodHead {
+		http.Error(w, "méthode non autorisée", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var isUp bool
+	switch strings.ToLower(r.FormValue("status")) {
+	case "", "up", "ok":
+		isUp = true
+	case "down", "error", "fail":
+		isUp = false
+	default:
+		http.Error(w, "status invalide (up ou down attendu)", http.StatusBadRequest)
+		return
+	}
+	message := strings.TrimSpace(r.FormValue("msg"))
+	if runes := []rune(message); len(runes) > maxPushMessage {
+		message = string(runes[:maxPushMessage])
+	}
+
+	if !a.monitor.RecordPush(r.PathValue("token"), isUp, message) {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"ok":true}` + "\n"))
+}
+
+// GetPushURL - URL complète de ping d'un serveur push
+func (a *App) GetPushURL(id string) (string, error) {
+	a.monitor.mutex.RLock()
+	server, exists := a.monitor.servers[id]
+	var token string
+	if exists {
+		token = server.PushToken
+	}
+	a.monitor.mutex.RUnlock()
+	if !exists || token == "" {
+		return "", fmt.Errorf("serveur push %s introuvable", id)
+	}
+
+	a.settingsMu.RLock()
+	base := a.settings.PushURLBase()
+	a.settingsMu.RUnlock()
+	if base == "" {
+		return "", fmt.Errorf("réception des pings désactivée (adresse d'écoute non configurée)")
+	}
+	return base + "/push/" + token, nil
+}
+
 /*
 // Tester la configuration SMTP - VERSION AMÉLIORÉE
 func (a *App) TestSMTPConfig(config backend.SMTPConfig) error {
@@ -1029,19 +3480,11 @@ func (a *App) SendTestEmail(to string) error {
 		return fmt.Errorf("configuration SMTP non définie")
 	}
 
-	c, err := mail.NewClient(config.Host, mail.WithPort(config.Port))
+	c, err := newSMTPClient(config, 15*time.Second)
 	if err != nil {
 		return err
 	}
 
-	c.SetSMTPAuth(mail.SMTPAuthPlain)
-	c.SetUsername(config.Username)
-	c.SetPassword(config.Password)
-
-	if config.TLS {
-		c.SetTLSPolicy(mail.TLSMandatory)
-	}
-
 	m := mail.NewMsg()
 
 	fromAddr := config.From
@@ -1060,7 +3503,8 @@ Ceci est un email de test envoyé depuis votre application de monitoring.
 Configuration utilisée:
 - Serveur SMTP: %s:%d
 - Utilisateur: %s
-- TLS: %t
+- TLS: %s
+- Authentification: %s
 
 Si vous recevez cet email, votre configuration est correcte !
 
@@ -1069,7 +3513,8 @@ Envoyé le %s`,
 		config.Host,
 		config.Port,
 		config.Username,
-		config.TLS,
+		config.EffectiveTLSMode(),
+		config.AuthMechanism(),
 		time.Now().Format("15:04:05 - 02/01/2006"))
 
 	m.SetBodyString(mail.TypeTextPlain, body)
@@ -1081,29 +3526,104 @@ Envoyé le %s`,
 	return nil
 }
 
-// Configurations pré-définies courantes (à ajouter comme méthodes utilitaires)
-func (a *App) GetGmailSMTPConfig() backend.SMTPConfig {
-	return backend.SMTPConfig{
-		Host: "smtp.gmail.com",
-		Port: 587,
-		TLS:  true,
+// DiagnoseSMTP - Diagnostic pas à pas d'une configuration SMTP
+// La configuration est celle du formulaire (pas forcément enregistrée).
+// Un email de test est envoyé à to s'il est renseigné
+func (a *App) DiagnoseSMTP(config backend.SMTPConfig, to string) backend.SMTPDiagReport {
+	log.Printf("🩺 Diagnostic SMTP: %s:%d (%s)", config.Host, config.Port, config.EffectiveTLSMode())
+
+	if config.AuthMechanism() != backend.SMTPAuthXOAUTH2 {
+		// Nettoyer le mot de passe pour Gmail (comme pour l'envoi réel)
+		config.Password = cleanAppPassword(config.Password)
 	}
+	report := backend.DiagnoseSMTP(config, to)
+
+	for _, step := range report.Steps {
+		if step.Status == backend.DiagStatusError {
+			log.Printf("❌ Diagnostic SMTP en échec (%s): %s", step.Label, step.Detail)
+		}
+	}
+	return report
+}
+
+// GetSMTPPresets - Catalogue des fournisseurs SMTP préconfigurés
+func (a *App) GetSMTPPresets() ([]backend.SMTPPreset, error) {
+	return backend.LoadSMTPPresets()
 }
 
-func (a *App) GetOutlookSMTPConfig() backend.SMTPConfig {
-	return backend.SMTPConfig{
-		Host: "smtp-mail.outlook.com",
-		Port: 587,
-		TLS:  true,
+// ApplySMTPPreset - Applique un fournisseur à une configuration SMTP
+// Hôte, port, TLS et authentification sont remplacés, les identifiants conservés
+func (a *App) ApplySMTPPreset(id string, config backend.SMTPConfig) (backend.SMTPConfig, error) {
+	preset, err := backend.FindSMTPPreset(id)
+	if err != nil {
+		return config, err
 	}
+	return preset.Apply(config), nil
+}
+
+// smtpAuthTypes - Correspondance des mécanismes d'authentification avec go-mail
+var smtpAuthTypes = map[string]mail.SMTPAuthType{
+	backend.SMTPAuthPlain:   mail.SMTPAuthPlain,
+	backend.SMTPAuthLogin:   mail.SMTPAuthLogin,
+	backend.SMTPAuthCramMD5: mail.SMTPAuthCramMD5,
+	backend.SMTPAuthXOAUTH2: mail.SMTPAuthXOAUTH2,
 }
 
-func (a *App) GetYahooSMTPConfig() backend.SMTPConfig {
-	return backend.SMTPConfig{
-		Host: "smtp.mail.yahoo.com",
-		Port: 587,
-		TLS:  true,
+// newSMTPClient - Crée un client SMTP pour le serveur externe configuré
+// Applique le mécanisme d'authentification (jeton OAuth2 renouvelé si besoin)
+// et le mode TLS (aucun, STARTTLS opportuniste ou obligatoire, TLS implicite)
+func newSMTPClient(config backend.SMTPConfig, timeout time.Duration) (*mail.Client, error) {
+	c, err := mail.NewClient(config.Host,
+		mail.WithPort(config.Port),
+		mail.WithTimeout(timeout),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("erreur création client SMTP: %s", err)
+	}
+
+	// Configuration de l'authentification
+	mechanism := config.AuthMechanism()
+	if mechanism != backend.SMTPAuthNone {
+		authType, ok := smtpAuthTypes[mechanism]
+		if !ok {
+			return nil, fmt.Errorf("authentification SMTP inconnue: %s", mechanism)
+		}
+		secret, err := config.Secret()
+		if err != nil {
+			return nil, err
+		}
+		if mechanism != backend.SMTPAuthXOAUTH2 {
+			// Nettoyer le mot de passe pour Gmail
+			secret = cleanAppPassword(secret)
+		}
+		c.SetSMTPAuth(authType)
+		c.SetUsername(config.Username)
+		c.SetPassword(secret)
+	}
+
+	// Configuration TLS selon le mode choisi
+	tlsConfig, err := config.TLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.SetTLSConfig(tlsConfig); err != nil {
+		return nil, fmt.Errorf("configuration TLS invalide: %s", err)
+	}
+	switch config.EffectiveTLSMode() {
+	case backend.TLSModeNone:
+		c.SetTLSPolicy(mail.NoTLS)
+	case backend.TLSModeOpportunistic:
+		c.SetTLSPolicy(mail.TLSOpportunistic)
+	case backend.TLSModeSTARTTLS:
+		c.SetTLSPolicy(mail.TLSMandatory)
+	case backend.TLSModeImplicit:
+		// SMTPS: la connexion est chiffrée dès l'ouverture, pas de STARTTLS
+		c.SetSSL(true)
+		c.SetTLSPolicy(mail.NoTLS)
+	default:
+		return nil, fmt.Errorf("mode TLS inconnu: %s", config.TLSMode)
 	}
+	return c, nil
 }
 
 // Fonction helper pour nettoyer le mot de passe d'application
@@ -1207,31 +3727,114 @@ func cleanAppPassword(password string) string {
 
 // ===== Génération du contenu des emails =====
 
-// createAlertEmailBody - Génère le corps d'un email d'alerte
-// Crée un message simple et clair sans caractères spéciaux
-func (a *App) createAlertEmailBody(serverName string) string {
-	var bodyBuilder strings.Builder
+// EmailTemplateData - Données accessibles dans les modèles d'email
+type EmailTemplateData struct {
+	Kind     string         // Type d'email: DOWN, UP, CRITICAL, SUMMARY ou DIGEST
+	Server   Server         // Serveur concerné (alertes)
+	Status   ServerStatus   // Statut au moment de l'alerte
+	Error    string         // Dernière erreur rencontrée
+	Message  string         // Détail de l'alerte critique
+	Downtime time.Duration  // Durée de l'incident
+	Time     time.Time      // Horodatage de l'événement
+	History  []ServerStatus // Dernières vérifications (plus récente en premier)
+	Servers  []Server       // Serveurs en panne (résumé)
+
+	Dependents []string // Serveurs dépendants injoignables (alerte DOWN de cause racine)
+
+	Alerts []backend.DeferredAlert // Alertes retenues pendant les heures calmes (résumé)
+}
 
-	// En-tête de l'alerte
-	bodyBuilder.WriteString("ALERTE SERVEUR\n\n")
-	// Informations du serveur
-	bodyBuilder.WriteString(fmt.Sprintf("Serveur: %s\n", serverName))
-	bodyBuilder.WriteString("Statut: HORS LIGNE\n")
-	bodyBuilder.WriteString(fmt.Sprintf("Heure: %s\n\n", time.Now().Format("15:04:05 - 02/01/2006")))
-	// Message d'alerte
-	bodyBuilder.WriteString(fmt.Sprintf("Votre serveur %s ne repond plus.\n\n", serverName))
-	// Pied de page
-	bodyBuilder.WriteString("---\n")
-	bodyBuilder.WriteString("Envoye par votre app de monitoring\n")
+// renderAlertEmail - Génère l'email d'une alerte à partir du modèle configuré
+func (a *App) renderAlertEmail(event AlertEvent) (backend.RenderedEmail, error) {
+	a.settingsMu.RLock()
+	tpl := a.settings.EmailTemplateFor(event.Kind)
+	a.settingsMu.RUnlock()
 
-	return bodyBuilder.String()
+	return backend.RenderEmailTemplate(tpl, EmailTemplateData{
+		Kind:     event.Kind,
+		Server:   event.Server,
+		Status:   event.Server.Status,
+		Error:    event.Server.Status.LastError,
+		Message:  event.Message,
+		Downtime: event.Downtime,
+		Time:     event.Time,
+		History:  event.History,
+
+		Dependents: event.Dependents,
+	})
+}
+
+// GetDefaultEmailTemplates - Modèles d'email par défaut (pour réinitialisation)
+func (a *App) GetDefaultEmailTemplates() map[string]backend.EmailTemplate {
+	return backend.DefaultEmailTemplates()
+}
+
+// PreviewEmailTemplate - Génère un aperçu d'un modèle avec des données d'exemple
+func (a *App) PreviewEmailTemplate(kind string, tpl backend.EmailTemplate) (backend.RenderedEmail, error) {
+	if err := backend.ValidateEmailTemplates(map[string]backend.EmailTemplate{kind: tpl}); err != nil {
+		return backend.RenderedEmail{}, err
+	}
+
+	// Compléter les champs vides avec le modèle par défaut
+	preview := backend.Settings{EmailTemplates: map[string]backend.EmailTemplate{kind: tpl}}
+	tpl = preview.EmailTemplateFor(kind)
+
+	now := time.Now()
+	downSince := now.Add(-12 * time.Minute)
+	sample := Server{
+		ID:       "exemple",
+		Name:     "Serveur d'exemple",
+		URL:      "https://example.com",
+		Type:     "http",
+		Interval: "30s",
+		Timeout:  "10s",
+		Status: ServerStatus{
+			IsUp:                kind == backend.EmailTemplateUp,
+			ResponseTime:        10000,
+			LastCheck:           now,
+			LastError:           "context deadline exceeded",
+			ConsecutiveFailures: 5,
+			DownSince:           &downSince,
+		},
+	}
+	history := []ServerStatus{
+		sample.Status,
+		{IsUp: false, ResponseTime: 10000, LastCheck: now.Add(-30 * time.Second), LastError: "HTTP 502"},
+		{IsUp: true, ResponseTime: 120, LastCheck: now.Add(-time.Minute)},
+	}
+
+	return backend.RenderEmailTemplate(tpl, EmailTemplateData{
+		Kind:     kind,
+		Server:   sample,
+		Status:   sample.Status,
+		Error:    sample.Status.LastError,
+		Message:  "DOWN (échecs: 5)",
+		Downtime: 12 * time.Minute,
+		Time:     now,
+		History:  history,
+		Servers:  []Server{sample},
+
+		Dependents: []string{"API d'exemple", "Base de données d'exemple"},
+
+		Alerts: []backend.DeferredAlert{
+			{Server: sample.Name, Kind: "DOWN", Time: now.Add(-6 * time.Hour), Release: now},
+			{Server: sample.Name, Kind: "CRITICAL", Message: "DOWN (échecs: 5)", Time: now.Add(-5 * time.Hour), Release: now},
+			{Server: sample.Name, Kind: "UP", Time: now.Add(-2 * time.Hour), Release: now},
+		},
+	})
 }
 
 // sendViaEmbeddedSMTP - Envoie un email via le serveur SMTP embarqué
 // Utilise le serveur SMTP local pour envoyer les alertes
-func (a *App) sendViaEmbeddedSMTP(serverName string) error {
+func (a *App) sendViaEmbeddedSMTP(recipients backend.RecipientList, email backend.RenderedEmail) error {
+	// Port et identifiants du serveur en cours, lus une seule fois (il peut être redémarré)
+	port, relay, err := a.embeddedSMTPClient()
+	if err != nil {
+		return err
+	}
+
 	// Créer le client SMTP vers le serveur embarqué
-	c, err := mail.NewClient("localhost", mail.WithPort(a.smtpPort))
+	c, err := mail.NewClient(embeddedSMTPHost, mail.WithPort(port))
 	if err != nil {
 		return fmt.Errorf("connexion SMTP embarqué échouée: %s", err)
 	}
@@ -1239,28 +3842,45 @@ func (a *App) sendViaEmbeddedSMTP(serverName string) error {
 	// Pas de TLS pour le serveur embarqué local
 	c.SetTLSPolicy(mail.NoTLS)
 
+	// Authentification avec les identifiants générés au démarrage du serveur
+	c.SetSMTPAuth(mail.SMTPAuthPlain)
+	c.SetUsername(relay.username)
+	c.SetPassword(relay.password)
+
 	// Créer le message
 	m := mail.NewMsg()
 	m.From("alert@monitoring-app.local")  // Adresse expéditeur locale
-	m.To(a.settings.UserEmail)              // Adresse destinataire configurée
-
-	// Sujet simple sans emojis
-	subject := fmt.Sprintf("ALERTE: %s est DOWN", serverName)
-	m.Subject(subject)
-
-	// Générer le corps de l'email
-	body := a.createAlertEmailBody(serverName)
+	// Destinataires configurés (les BCC restent dans l'enveloppe SMTP uniquement)
+	if len(recipients.To) == 0 {
+		m.SetGenHeaderPreformatted(mail.Header(mail.HeaderTo), undisclosedRecipients)
+	} else if err := m.To(recipients.To...); err != nil {
+		return fmt.Errorf("adresse To invalide: %s", err)
+	}
+	if len(recipients.Cc) > 0 {
+		if err := m.Cc(recipients.Cc...); err != nil {
+			return fmt.Errorf("adresse CC invalide: %s", err)
+		}
+	}
+	if err := m.Bcc(recipients.Bcc...); err != nil {
+		return fmt.Errorf("adresse BCC invalide: %s", err)
+	}
+	m.Subject(email.Subject)
 
 	// Configuration de l'encodage pour les caractères spéciaux
 	m.SetEncoding(mail.EncodingQP)    // Quoted-Printable
 	m.SetCharset(mail.CharsetUTF8)    // UTF-8
-	m.SetBodyString(mail.TypeTextPlain, body)
+	m.SetBodyString(mail.TypeTextPlain, email.Text)
+	// Version HTML en alternative (multipart/alternative)
+	if email.HTML != "" {
+		m.AddAlternativeString(mail.TypeTextHTML, email.HTML)
+	}
 
 	// Envoyer l'email
 	if err := c.DialAndSend(m); err != nil {
 		return fmt.Errorf("envoi via SMTP embarqué échoué: %s", err)
 	}
 
-	log.Printf("✅ Alerte envoyée via SMTP embarqué à : %s", a.settings.UserEmail)
+	log.Printf("✅ Alerte envoyée via SMTP embarqué à : %v (cc: %v, bcc: %v)",
+		recipients.To, recipients.Cc, recipients.Bcc)
 	return nil
 }
@@ -0,0 +1,238 @@
+// Package backend - Import des cibles Prometheus blackbox_exporter
+// Accepte un prometheus.yml (jobs scrape_configs appelant /probe) ou un
+// fichier file_sd de cibles (YAML ou JSON); le module de chaque cible
+// (http_2xx, tcp_connect, icmp...) détermine le type de check
+package backend
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Valeurs par défaut de Prometheus (section global)
+const (
+	promDefaultInterval = "1m"
+	promDefaultTimeout  = "10s"
+)
+
+// promConfig - Configuration Prometheus (seuls les champs utiles sont lus)
+type promConfig struct {
+	Global struct {
+		ScrapeInterval string `yaml:"scrape_interval"`
+		ScrapeTimeout  string `yaml:"scrape_timeout"`
+	} `yaml:"global"`
+	ScrapeConfigs []yaml.Node `yaml:"scrape_configs"`
+}
+
+// promScrapeConfig - Job de scrape
+type promScrapeConfig struct {
+	JobName        string              `yaml:"job_name"`
+	ScrapeInterval string              `yaml:"scrape_interval"`
+	ScrapeTimeout  string              `yaml:"scrape_timeout"`
+	MetricsPath    string              `yaml:"metrics_path"`
+	Params         map[string][]string `yaml:"params"`
+	StaticConfigs  []promTargetGroup   `yaml:"static_configs"`
+	FileSDConfigs  []yaml.Node         `yaml:"file_sd_configs"`
+}
+
+// promTargetGroup - Groupe de cibles (static_configs ou fichier file_sd)
+type promTargetGroup struct {
+	Targets []yaml.Node       `yaml:"targets"`
+	Labels  map[string]string `yaml:"labels"`
+}
+
+// promDurationRe - Durée Prometheus (ex: "1m30s", "2d", "500ms")
+var promDurationRe = regexp.MustCompile(`(\d+)(ms|y|w|d|h|m|s)`)
+
+// promUnits - Secondes par unité de durée Prometheus
+var promUnits = map[string]float64{
+	"ms": 0.001, "s": 1, "m": 60, "h": 3600, "d": 86400, "w": 7 * 86400, "y": 365 * 86400,
+}
+
+// yamlTypeLineRe - Ligne des erreurs de décodage de yaml.v3
+var yamlTypeLineRe = regexp.MustCompile(`^line (\d+): (.*)$`)
+
+// ParseBlackbox - Traduit les cibles blackbox_exporter d'un prometheus.yml
+// ou d'un fichier file_sd; les intervalles et timeouts sont ceux du job
+func ParseBlackbox(data []byte) (*ForeignImport, error) {
+	root, err := parseDeclarative("prometheus.yml", data)
+	if err != nil {
+		if ce, ok := err.(*ConfigError); ok {
+			return nil, ConfigErrors{*ce}
+		}
+		return nil, err
+	}
+	if root == nil {
+		return nil, fmt.Errorf("document vide")
+	}
+
+	result := &ForeignImport{ByLine: true}
+	// Fichier file_sd: liste de groupes de cibles sans job
+	if root.Kind == yaml.SequenceNode {
+		var groups []promTargetGroup
+		if err := root.Decode(&groups); err != nil {
+			return nil, yamlDecodeError(err)
+		}
+		for _, group := range groups {
+			result.addTargets(group, "", "", "")
+		}
+		return result, nil
+	}
+
+	var config promConfig
+	if err := root.Decode(&config); err != nil {
+		return nil, yamlDecodeError(err)
+	}
+	if config.ScrapeConfigs == nil {
+		return nil, fmt.Errorf("prometheus.yml (scrape_configs) ou fichier file_sd attendu")
+	}
+	interval := firstNonEmpty(config.Global.ScrapeInterval, promDefaultInterval)
+	timeout := firstNonEmpty(config.Global.ScrapeTimeout, promDefaultTimeout)
+	for _, node := range config.ScrapeConfigs {
+		var job promScrapeConfig
+		if err := node.Decode(&job); err != nil {
+			return nil, yamlDecodeError(err)
+		}
+		module := ""
+		if modules := job.Params["module"]; len(modules) > 0 {
+			module = modules[0]
+		}
+		if job.MetricsPath != "/probe" && module == "" {
+			result.warn(node.Line, "job %s: pas un job blackbox_exporter, ignoré", job.JobName)
+			continue
+		}
+		if len(job.FileSDConfigs) > 0 {
+			result.warn(node.Line, "job %s: cibles file_sd_configs non lues, importez leurs fichiers séparément", job.JobName)
+		}
+		for _, group := range job.StaticConfigs {
+			result.addTargets(group, module,
+				firstNonEmpty(job.ScrapeInterval, interval), firstNonEmpty(job.ScrapeTimeout, timeout))
+		}
+	}
+	return result, nil
+}
+
+// addTargets - Ajoute les cibles d'un groupe, sondées par le module indiqué
+// (le label module ou __param_module du groupe a priorité)
+func (f *ForeignImport) addTargets(group promTargetGroup, module, interval, timeout string) {
+	module = firstNonEmpty(group.Labels["__param_module"], group.Labels["module"], module)
+	for _, node := range group.Targets {
+		target := strings.TrimSpace(node.Value)
+		if target == "" {
+			continue
+		}
+		server := ForeignServer{
+			Line:     node.Line,
+			Name:     target,
+			Interval: formatSeconds(promDuration(interval)),
+			Timeout:  formatSeconds(promDuration(timeout)),
+		}
+		if name := group.Labels["name"]; name != "" && len(group.Targets) == 1 {
+			server.Name = name
+		}
+
+		prober := blackboxProber(module)
+		if prober == "" {
+			// Module inconnu: type déduit de la forme de la cible
+			switch _, _, err := net.SplitHostPort(target); {
+			case strings.Contains(target, "://"):
+				prober = "http"
+			case err == nil:
+				prober = "tcp"
+			default:
+				prober = "icmp"
+			}
+			f.warn(node.Line, "%s: module %q inconnu, type de check déduit de la cible", target, module)
+		}
+		switch prober {
+		case "http":
+			server.Type, server.URL = "http", target
+			if !strings.Contains(target, "://") {
+				server.URL = "http://" + target
+			}
+		case "tcp":
+			if _, _, err := net.SplitHostPort(target); err != nil {
+				f.warn(node.Line, "%s: port manquant pour le module %s, ignoré", target, module)
+				continue
+			}
+			server.Type, server.URL = "tcp", target
+		case "icmp", "dns":
+			host := target
+			if h, _, err := net.SplitHostPort(target); err == nil {
+				host = h
+			}
+			server.Type, server.URL = "ping", host
+			if prober == "dns" {
+				f.warn(node.Line, "%s: module %s importé en ping (résolution DNS non vérifiée)", target, module)
+			}
+		}
+		f.Servers = append(f.Servers, server)
+	}
+}
+
+// blackboxProber - Prober probable d'un module blackbox_exporter d'après son nom
+func blackboxProber(module string) string {
+	module = strings.ToLower(module)
+	switch {
+	case module == "":
+		return ""
+	case strings.Contains(module, "icmp") || strings.Contains(module, "ping"):
+		return "icmp"
+	case strings.Contains(module, "dns"):
+		return "dns"
+	case strings.Contains(module, "http"):
+		return "http"
+	}
+	for _, protocol := range []string{"tcp", "ssh", "smtp", "imap", "pop3", "irc", "tls", "grpc", "banner"} {
+		if strings.Contains(module, protocol) {
+			return "tcp"
+		}
+	}
+	return ""
+}
+
+// promDuration - Secondes d'une durée Prometheus, 0 si invalide
+func promDuration(value string) float64 {
+	if value == "" || promDurationRe.ReplaceAllString(value, "") != "" {
+		return 0
+	}
+	var seconds float64
+	for _, m := range promDurationRe.FindAllStringSubmatch(value, -1) {
+		n, _ := strconv.ParseFloat(m[1], 64)
+		seconds += n * promUnits[m[2]]
+	}
+	return seconds
+}
+
+// yamlDecodeError - Erreurs de décodage yaml.v3 localisées par ligne
+func yamlDecodeError(err error) error {
+	typeErr, ok := err.(*yaml.TypeError)
+	if !ok {
+		return err
+	}
+	var errs ConfigErrors
+	for _, message := range typeErr.Errors {
+		if m := yamlTypeLineRe.FindStringSubmatch(message); m != nil {
+			line, _ := strconv.Atoi(m[1])
+			errs = append(errs, ConfigError{Line: line, Message: m[2]})
+		} else {
+			errs = append(errs, ConfigError{Message: message})
+		}
+	}
+	return errs
+}
+
+// firstNonEmpty - Première valeur non vide
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
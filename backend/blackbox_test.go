@@ -0,0 +1,150 @@
+package backend
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseBlackbox(t *testing.T) {
+	src := `global:
+  scrape_interval: 30s
+scrape_configs:
+  - job_name: node
+    static_configs:
+      - targets: [localhost:9100]
+  - job_name: blackbox-http
+    metrics_path: /probe
+    scrape_timeout: 5s
+    params:
+      module: [http_2xx]
+    static_configs:
+      - targets:
+          - https://example.com
+          - example.org/health
+  - job_name: blackbox-tcp
+    metrics_path: /probe
+    scrape_interval: 1m30s
+    params:
+      module: [tcp_connect]
+    static_configs:
+      - targets: [db.example.com:5432, no-port.example.com]
+        labels:
+          env: prod
+      - targets: [10.0.0.1]
+        labels:
+          module: icmp
+          name: Gateway
+    file_sd_configs:
+      - files: [targets/*.yml]
+`
+	result, err := ParseBlackbox([]byte(src))
+	if err != nil {
+		t.Fatalf("erreur inattendue: %s", err)
+	}
+	want := []ForeignServer{
+		{Line: 14, Name: "https://example.com", URL: "https://example.com", Type: "http", Interval: "30s", Timeout: "5s"},
+		{Line: 15, Name: "example.org/health", URL: "http://example.org/health", Type: "http", Interval: "30s", Timeout: "5s"},
+		{Line: 22, Name: "db.example.com:5432", URL: "db.example.com:5432", Type: "tcp", Interval: "90s", Timeout: "10s"},
+		{Line: 25, Name: "Gateway", URL: "10.0.0.1", Type: "ping", Interval: "90s", Timeout: "10s"},
+	}
+	if len(result.Servers) != len(want) {
+		t.Fatalf("%d serveurs, attendu %d: %+v", len(result.Servers), len(want), result.Servers)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(result.Servers[i], want[i]) {
+			t.Errorf("serveur %d:\nobtenu  %+v\nattendu %+v", i, result.Servers[i], want[i])
+		}
+	}
+	warnings := []string{
+		"ligne 4: job node: pas un job blackbox_exporter",
+		"ligne 16: job blackbox-tcp: cibles file_sd_configs non lues",
+		"ligne 22: no-port.example.com: port manquant",
+	}
+	if len(result.Warnings) != len(warnings) {
+		t.Fatalf("avertissements: %q", result.Warnings)
+	}
+	for i, warning := range warnings {
+		if !strings.HasPrefix(result.Warnings[i], warning) {
+			t.Errorf("avertissement %q, attendu %q", result.Warnings[i], warning)
+		}
+	}
+}
+
+func TestParseBlackboxFileSD(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []ForeignServer
+	}{
+		{
+			name: "YAML",
+			src:  "- targets: [smtp.example.com:25]\n  labels:\n    module: smtp_starttls\n",
+			want: []ForeignServer{{Line: 1, Name: "smtp.example.com:25", URL: "smtp.example.com:25", Type: "tcp"}},
+		},
+		{
+			name: "JSON, module déduit de la cible",
+			src:  `[{"targets": ["https://a.example.com", "b.example.com:443", "c.example.com"]}]`,
+			want: []ForeignServer{
+				{Line: 1, Name: "https://a.example.com", URL: "https://a.example.com", Type: "http"},
+				{Line: 1, Name: "b.example.com:443", URL: "b.example.com:443", Type: "tcp"},
+				{Line: 1, Name: "c.example.com", URL: "c.example.com", Type: "ping"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseBlackbox([]byte(tt.src))
+			if err != nil {
+				t.Fatalf("erreur inattendue: %s", err)
+			}
+			if !reflect.DeepEqual(result.Servers, tt.want) {
+				t.Errorf("obtenu %+v, attendu %+v", result.Servers, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBlackboxErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		line int // 0: erreur sans ligne
+	}{
+		{"YAML invalide", "scrape_configs:\n  - job_name: [\n", 2},
+		{"type inattendu", "scrape_configs:\n  - job_name: a\n    static_configs: 12\n", 3},
+		{"sans scrape_configs", "global:\n  scrape_interval: 1m\n", 0},
+		{"document vide", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseBlackbox([]byte(tt.src))
+			if err == nil {
+				t.Fatal("erreur attendue")
+			}
+			var errs ConfigErrors
+			if !errors.As(err, &errs) {
+				if tt.line != 0 {
+					t.Fatalf("erreur localisée attendue, obtenu %v", err)
+				}
+				return
+			}
+			if errs[0].Line != tt.line {
+				t.Errorf("ligne %d, attendu %d (%s)", errs[0].Line, tt.line, errs)
+			}
+		})
+	}
+}
+
+func TestPromDuration(t *testing.T) {
+	tests := map[string]float64{
+		"15s": 15, "1m30s": 90, "2h": 7200, "1d": 86400, "500ms": 0.5, "1w": 604800,
+		"": 0, "10": 0, "1.5m": 0, "5x": 0,
+	}
+	for value, want := range tests {
+		if got := promDuration(value); got != want {
+			t.Errorf("promDuration(%q) = %v, attendu %v", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,402 @@
+// Package backend - Emplacement et écriture des fichiers de configuration
+// Ce fichier place les données de l'application dans le dossier de
+// configuration de l'utilisateur, les écrit de façon atomique (fichier
+// temporaire puis renommage) avec des sauvegardes tournantes, et reprend
+// les fichiers laissés dans le dossier courant par les anciennes versions
+package backend
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// configDirName - Sous-dossier de l'application dans le dossier de configuration
+const configDirName = "monitoring_serv"
+
+// configDirEnv - Variable d'environnement remplaçant le dossier de configuration
+const configDirEnv = "MONITORING_SERV_CONFIG_DIR"
+
+// ConfigBackups - Nombre de sauvegardes conservées pour les fichiers importants
+const ConfigBackups = 3
+
+// legacyMigratedSuffix - Suffixe des originaux repris dans le dossier de configuration
+const legacyMigratedSuffix = ".migrated"
+
+// legacyConfigFiles - Fichiers écrits dans le dossier courant par les anciennes
+// versions, avec la vérification que leur contenu est bien le nôtre (un
+// servers.json d'un autre outil lancé depuis le même dossier n'est pas repris)
+// et, si besoin, l'effacement des secrets de l'original une fois copié
+var legacyConfigFiles = []struct {
+	name  string
+	check func([]byte) error
+	scrub func([]byte) ([]byte, error)
+}{
+	{"settings.json", checkLegacySettings, scrubLegacySettings},
+	{"servers.json", checkLegacyServers, nil},
+	{"mail_queue.json", checkLegacyMailQueue, nil},
+	{"secrets.enc", checkLegacySecrets, nil},
+	{"smtp_presets.json", checkLegacySMTPPresets, nil},
+}
+
+var (
+	configDir     string
+	configDirErr  error
+	configDirOnce sync.Once
+)
+
+// ConfigDir - Dossier de configuration de l'application (créé si besoin)
+// Par défaut os.UserConfigDir()/monitoring_serv, ou MONITORING_SERV_CONFIG_DIR
+func ConfigDir() (string, error) {
+	configDirOnce.Do(func() {
+		dir := os.Getenv(configDirEnv)
+		if dir == "" {
+			base, err := os.UserConfigDir()
+			if err != nil {
+				configDirErr = fmt.Errorf("dossier de configuration introuvable: %s", err)
+				return
+			}
+			dir = filepath.Join(base, configDirName)
+		}
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			configDirErr = fmt.Errorf("création du dossier de configuration impossible: %s", err)
+			return
+		}
+		configDir = dir
+	})
+	return configDir, configDirErr
+}
+
+// ConfigFilePath - Chemin d'un fichier dans le dossier de configuration
+func ConfigFilePath(name string) (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// MigrateLegacyFiles - Copie les fichiers du dossier courant (anciennes
+// versions) vers le dossier de configuration, sans écraser un fichier existant
+// Un original copié est renommé en *.migrated, lisible par l'utilisateur seul
+// et sans les secrets en clair de settings.json; un fichier dont le contenu
+// ne correspond pas à notre format est ignoré.
+// À appeler avant toute lecture de la configuration
+func MigrateLegacyFiles() error {
+	dir, err := ConfigDir()
+	if err != nil {
+		return err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	return migrateLegacyFiles(cwd, dir)
+}
+
+// migrateLegacyFiles - Reprend les fichiers de from dans le dossier dir
+func migrateLegacyFiles(from, dir string) error {
+	if same, _ := sameDir(from, dir); same {
+		return nil
+	}
+
+	for _, file := range legacyConfigFiles {
+		legacy := filepath.Join(from, file.name)
+		target := filepath.Join(dir, file.name)
+		if _, err := os.Stat(target); err == nil {
+			continue // Déjà repris (ou créé par cette version)
+		}
+		info, err := os.Stat(legacy)
+		if err != nil || !info.Mode().IsRegular() {
+			continue // Rien à reprendre
+		}
+		data, err := os.ReadFile(legacy)
+		if err != nil {
+			return fmt.Errorf("migration de %s impossible: %s", legacy, err)
+		}
+		if err := file.check(data); err != nil {
+			log.Printf("⚠️ %s ignoré, ce n'est pas un fichier de l'application: %s", legacy, err)
+			continue
+		}
+		if err := WriteFileAtomic(target, data, 0o600); err != nil {
+			return fmt.Errorf("migration de %s impossible: %s", legacy, err)
+		}
+		if err := retireLegacyFile(legacy, data, file.scrub); err != nil {
+			log.Printf("⚠️ %s copié vers %s mais laissé en place: %s", legacy, target, err)
+			continue
+		}
+		log.Printf("📦 %s déplacé vers %s (original renommé en %s)", legacy, target, filepath.Base(legacy)+legacyMigratedSuffix)
+	}
+	return nil
+}
+
+// retireLegacyFile - Remplace un original repris par legacy.migrated (0600)
+// Avec scrub, le fichier renommé est réécrit sans ses secrets
+func retireLegacyFile(legacy string, data []byte, scrub func([]byte) ([]byte, error)) error {
+	migrated := legacy + legacyMigratedSuffix
+	if scrub != nil {
+		scrubbed, err := scrub(data)
+		if err != nil {
+			return err
+		}
+		if err := WriteFileAtomic(migrated, scrubbed, 0o600); err != nil {
+			return err
+		}
+		return os.Remove(legacy)
+	}
+	if err := os.Chmod(legacy, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(legacy, migrated)
+}
+
+// checkLegacySettings - Vérifie qu'un fichier est un settings.json de l'application
+func checkLegacySettings(data []byte) error {
+	_, payload, err := unwrapConfig(ConfigKindSettings, data)
+	if err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return fmt.Errorf("objet JSON attendu: %s", err)
+	}
+	migrated, err := MigrateConfigData(ConfigKindSettings, data)
+	if err != nil {
+		return err
+	}
+	var s Settings
+	if err := json.Unmarshal(migrated, &s); err != nil {
+		return err
+	}
+	// Au moins un réglage présent depuis les premières versions
+	for _, key := range []string{"notificationMode", "notificationCooldown", "userEmail", "recipients", "smtp_config"} {
+		if _, ok := fields[key]; ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("aucun réglage reconnu")
+}
+
+// scrubLegacySettings - Copie d'un settings.json sans ses secrets en clair
+// (mot de passe SMTP, secret et jeton OAuth2), références conservées
+func scrubLegacySettings(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc map[string]any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	settings := doc
+	if _, ok := doc["schema_version"]; ok {
+		settings, _ = doc["data"].(map[string]any)
+	}
+
+	blank := func(fields map[string]any, key string) {
+		if value, _ := fields[key].(string); value != "" && !strings.HasPrefix(value, secretRefPrefix) {
+			fields[key] = ""
+		}
+	}
+	if smtp, ok := settings["smtp_config"].(map[string]any); ok {
+		blank(smtp, "password")
+		if oauth, ok := smtp["oauth2"].(map[string]any); ok {
+			blank(oauth, "client_secret")
+			blank(oauth, "refresh_token")
+		}
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// checkLegacyServers - Vérifie qu'un fichier est un servers.json de l'application
+func checkLegacyServers(data []byte) error {
+	payload, err := MigrateConfigData(ConfigKindServers, data)
+	if err != nil {
+		return err
+	}
+	var servers []map[string]any
+	if err := json.Unmarshal(payload, &servers); err != nil {
+		return fmt.Errorf("liste de serveurs attendue: %s", err)
+	}
+	for i, server := range servers {
+		id, _ := server["id"].(string)
+		name, _ := server["name"].(string)
+		if id == "" || name == "" {
+			return fmt.Errorf("serveur n°%d sans identifiant ou nom", i+1)
+		}
+	}
+	return nil
+}
+
+// checkLegacyMailQueue - Vérifie qu'un fichier est une file d'envoi de l'application
+func checkLegacyMailQueue(data []byte) error {
+	var items []QueuedEmail
+	if err := strictUnmarshal(data, &items); err != nil {
+		return err
+	}
+	for i, item := range items {
+		if item.ID == "" {
+			return fmt.Errorf("email n°%d sans identifiant", i+1)
+		}
+	}
+	return nil
+}
+
+// checkLegacySecrets - Vérifie qu'un fichier est un fichier de secrets de l'application
+func checkLegacySecrets(data []byte) error {
+	var file secretFile
+	if err := strictUnmarshal(data, &file); err != nil {
+		return err
+	}
+	if file.Version < 1 || file.KDF != "argon2id" || len(file.Salt) == 0 || len(file.Nonce) == 0 {
+		return fmt.Errorf("en-tête de fichier de secrets invalide")
+	}
+	return nil
+}
+
+// checkLegacySMTPPresets - Vérifie qu'un fichier est un catalogue de préréglages SMTP
+func checkLegacySMTPPresets(data []byte) error {
+	var presets []SMTPPreset
+	if err := strictUnmarshal(data, &presets); err != nil {
+		return err
+	}
+	for _, preset := range presets {
+		if err := validateSMTPPreset(preset); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// strictUnmarshal - Décode un document JSON en refusant les champs inconnus
+func strictUnmarshal(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+// WriteFileAtomic - Écrit un fichier via un fichier temporaire renommé
+// Un arrêt brutal laisse soit l'ancien contenu, soit le nouveau, jamais un
+// fichier tronqué
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir, name := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	tmp, err := os.CreateTemp(dir, "."+name+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // Sans effet après le renommage
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	syncDir(dir)
+	// Ne pas prendre cette écriture pour une modification externe
+	rememberContent(path, data)
+	return nil
+}
+
+// WriteFileWithBackups - Écrit un fichier de façon atomique en conservant
+// les versions précédentes (path.1 la plus récente … path.N)
+func WriteFileWithBackups(path string, data []byte, perm os.FileMode, keep int) error {
+	if current, err := os.ReadFile(path); err == nil {
+		if string(current) == string(data) {
+			return nil // Contenu inchangé: pas de nouvelle sauvegarde
+		}
+		rotateBackups(path, keep)
+		if keep > 0 {
+			if err := WriteFileAtomic(backupPath(path, 1), current, perm); err != nil {
+				log.Printf("⚠️ Sauvegarde de %s impossible: %s", path, err)
+			}
+		}
+	}
+	return WriteFileAtomic(path, data, perm)
+}
+
+// ReadFileWithBackups - Lit un fichier, ou sa sauvegarde valide la plus récente
+// s'il est corrompu (valid renvoie une erreur). Renvoie une erreur
+// os.ErrNotExist si ni le fichier ni ses sauvegardes n'existent
+func ReadFileWithBackups(path string, keep int, valid func([]byte) error) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if err = valid(data); err == nil {
+			return data, nil
+		}
+		log.Printf("⚠️ %s illisible (%s), recherche d'une sauvegarde", path, err)
+	}
+	firstErr := err
+
+	for i := 1; i <= keep; i++ {
+		backup := backupPath(path, i)
+		data, err := os.ReadFile(backup)
+		if err != nil || valid(data) != nil {
+			continue
+		}
+		log.Printf("♻️ %s restauré depuis %s", path, backup)
+		return data, nil
+	}
+	return nil, firstErr
+}
+
+// backupPath - Chemin de la N-ième sauvegarde d'un fichier
+func backupPath(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
+
+// rotateBackups - Décale les sauvegardes (path.1 → path.2 …), la plus ancienne est supprimée
+func rotateBackups(path string, keep int) {
+	if keep <= 0 {
+		return
+	}
+	os.Remove(backupPath(path, keep))
+	for i := keep - 1; i >= 1; i-- {
+		if err := os.Rename(backupPath(path, i), backupPath(path, i+1)); err != nil && !os.IsNotExist(err) {
+			log.Printf("⚠️ Rotation des sauvegardes de %s: %s", path, err)
+		}
+	}
+}
+
+// sameDir - Indique si deux chemins désignent le même dossier
+func sameDir(a, b string) (bool, error) {
+	infoA, err := os.Stat(a)
+	if err != nil {
+		return false, err
+	}
+	infoB, err := os.Stat(b)
+	if err != nil {
+		return false, err
+	}
+	return os.SameFile(infoA, infoB), nil
+}
+
+// syncDir - Force l'écriture du renommage sur le disque (ignoré sous Windows)
+func syncDir(dir string) {
+	if runtime.GOOS == "windows" {
+		return
+	}
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
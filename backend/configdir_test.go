@@ -0,0 +1,112 @@
+package backend
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMigrateLegacyFiles(t *testing.T) {
+	cwd, dir := t.TempDir(), t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(cwd); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	settings := `{"notificationMode":"email","userEmail":"ops@example.com",
+		"smtp_config":{"host":"smtp.example.com","port":587,"username":"ops","password":"hunter2","tls":true,
+		"oauth2":{"token_url":"https://oauth.example.com/token","client_secret":"s3cret","refresh_token":"secret://keyring/smtp.oauth2.refresh_token"}}}`
+	servers := `[{"id":"1","name":"web","url":"https://example.com","type":"http"}]`
+	foreign := `{"servers":{"alpha":{"host":"a"}}}` // Fichier d'un autre outil
+	files := map[string]string{"settings.json": settings, "servers.json": servers, "mail_queue.json": foreign}
+	for name, content := range files {
+		if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := migrateLegacyFiles(".", dir); err != nil {
+		t.Fatalf("migrateLegacyFiles: %v", err)
+	}
+
+	// Copies intactes dans le dossier de configuration
+	for _, name := range []string{"settings.json", "servers.json"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("%s non copié: %v", name, err)
+		}
+		if string(data) != files[name] {
+			t.Errorf("%s modifié pendant la copie", name)
+		}
+		if _, err := os.Stat(name); !os.IsNotExist(err) {
+			t.Errorf("%s toujours présent dans le dossier courant", name)
+		}
+		info, err := os.Stat(name + legacyMigratedSuffix)
+		if err != nil {
+			t.Fatalf("%s%s absent: %v", name, legacyMigratedSuffix, err)
+		}
+		if perm := info.Mode().Perm(); perm != 0o600 {
+			t.Errorf("%s%s en mode %o, attendu 600", name, legacyMigratedSuffix, perm)
+		}
+	}
+
+	// Fichier étranger ignoré et laissé en place
+	if _, err := os.Stat(filepath.Join(dir, "mail_queue.json")); !os.IsNotExist(err) {
+		t.Error("mail_queue.json étranger copié")
+	}
+	if _, err := os.Stat("mail_queue.json"); err != nil {
+		t.Errorf("mail_queue.json étranger déplacé: %v", err)
+	}
+
+	// Secrets en clair effacés de l'original renommé, références conservées
+	data, err := os.ReadFile("settings.json" + legacyMigratedSuffix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"hunter2", "s3cret"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("secret %q encore présent dans l'original renommé", secret)
+		}
+	}
+	var scrubbed Settings
+	if err := json.Unmarshal(data, &scrubbed); err != nil {
+		t.Fatal(err)
+	}
+	if scrubbed.SMTPConfig.Host != "smtp.example.com" || scrubbed.UserEmail != "ops@example.com" {
+		t.Errorf("réglages perdus dans l'original renommé: %+v", scrubbed)
+	}
+	if ref := scrubbed.SMTPConfig.OAuth2.RefreshToken; ref != "secret://keyring/smtp.oauth2.refresh_token" {
+		t.Errorf("référence de secret perdue: %q", ref)
+	}
+
+	// Seconde exécution: rien à reprendre, les copies ne sont pas écrasées
+	if err := os.WriteFile("servers.json", []byte(`[]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := migrateLegacyFiles(".", dir); err != nil {
+		t.Fatalf("seconde migration: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "servers.json")); string(data) != servers {
+		t.Error("servers.json du dossier de configuration écrasé")
+	}
+}
+
+func TestScrubLegacySettingsEnvelope(t *testing.T) {
+	src := `{"schema_version":1,"kind":"settings","data":{"smtp_config":{"password":"hunter2","port":465}}}`
+	data, err := scrubLegacySettings([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "hunter2") {
+		t.Errorf("mot de passe conservé: %s", data)
+	}
+	if !strings.Contains(string(data), `"port": 465`) {
+		t.Errorf("nombre réécrit: %s", data)
+	}
+}
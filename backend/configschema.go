@@ -0,0 +1,285 @@
+// Package backend - Schéma versionné des fichiers de configuration
+// Ce fichier enveloppe settings.json et servers.json dans un document
+// {"schema_version", "kind", "data"} et met à niveau les fichiers plus
+// anciens étape par étape, en consignant chaque changement appliqué
+package backend
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Types de fichiers de configuration versionnés
+const (
+	ConfigKindSettings = "settings"
+	ConfigKindServers  = "servers"
+)
+
+// ConfigEnvelope - Enveloppe versionnée écrite sur le disque
+type ConfigEnvelope struct {
+	SchemaVersion int             `json:"schema_version"`
+	Kind          string          `json:"kind"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// ConfigMigration - Étape de migration de la version From à From+1
+// Apply modifie le document JSON générique et renvoie la liste des changements
+type ConfigMigration struct {
+	From        int
+	Description string
+	Apply       func(doc *any) ([]string, error)
+}
+
+// ConfigMigrationReport - Compte rendu de la mise à niveau d'un fichier
+type ConfigMigrationReport struct {
+	Kind        string    `json:"kind"`
+	Path        string    `json:"path"`
+	FromVersion int       `json:"from_version"`
+	ToVersion   int       `json:"to_version"`
+	Changes     []string  `json:"changes"`
+	MigratedAt  time.Time `json:"migrated_at"`
+}
+
+// configMigrations - Étapes de migration par type de fichier, dans l'ordre
+// La version courante d'un type est le nombre de ses étapes
+var configMigrations = map[string][]ConfigMigration{
+	ConfigKindSettings: {
+		{From: 0, Description: "Enveloppe versionnée, mode TLS explicite et valeurs par défaut", Apply: migrateSettingsV0},
+	},
+	ConfigKindServers: {
+		{From: 0, Description: "Enveloppe versionnée, serveurs sans type de check signalés", Apply: migrateServersV0},
+	},
+}
+
+var (
+	migrationReports []ConfigMigrationReport
+	migrationMutex   sync.Mutex
+)
+
+// CurrentSchemaVersion - Version du schéma écrite par cette application
+func CurrentSchemaVersion(kind string) int {
+	return len(configMigrations[kind])
+}
+
+// EncodeConfig - Enveloppe les données d'un fichier dans la version courante
+func EncodeConfig(kind string, v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(ConfigEnvelope{
+		SchemaVersion: CurrentSchemaVersion(kind),
+		Kind:          kind,
+		Data:          data,
+	}, "", "  ")
+}
+
+// LoadConfigFile - Lit un fichier versionné (ou, avec keep > 0, sa sauvegarde
+// valide la plus récente), le met à niveau si besoin et transmet les données
+// à decode. decode peut être appelé plusieurs fois: il doit repartir de zéro
+// à chaque appel. Un fichier mis à niveau est réécrit, l'ancienne version
+// restant en sauvegarde. Renvoie une erreur os.ErrNotExist si le fichier
+// n'existe pas
+func LoadConfigFile(path, kind string, keep int, decode func(json.RawMessage) error) error {
+	var (
+		report   *ConfigMigrationReport
+		migrated json.RawMessage
+	)
+	_, err := ReadFileWithBackups(path, keep, func(data []byte) error {
+		payload, r, err := migrateConfig(kind, data)
+		if err != nil {
+			return err
+		}
+		if err := decode(payload); err != nil {
+			return err
+		}
+		report, migrated = r, payload
+		return nil
+	})
+	if err != nil || report == nil {
+		return err
+	}
+
+	report.Path = path
+	recordMigration(*report)
+
+	data, err := EncodeConfig(kind, migrated)
+	if err != nil {
+		return err
+	}
+	if err := WriteFileWithBackups(path, data, 0o600, ConfigBackups); err != nil {
+		log.Printf("⚠️ Réécriture de %s après migration impossible: %s", path, err)
+	}
+	return nil
+}
+
+// MigrateConfigData - Données d'un document versionné (ou sans enveloppe)
+// mises à niveau au schéma courant, sans écriture ni compte rendu (import)
+func MigrateConfigData(kind string, data []byte) (json.RawMessage, error) {
+	payload, _, err := migrateConfig(kind, data)
+	return payload, err
+}
+
+// GetConfigMigrations - Migrations appliquées depuis le démarrage
+func GetConfigMigrations() []ConfigMigrationReport {
+	migrationMutex.Lock()
+	defer migrationMutex.Unlock()
+	return append([]ConfigMigrationReport(nil), migrationReports...)
+}
+
+// recordMigration - Consigne une migration dans les logs et le compte rendu
+func recordMigration(report ConfigMigrationReport) {
+	log.Printf("🔄 %s migré du schéma v%d vers v%d", report.Path, report.FromVersion, report.ToVersion)
+	for _, change := range report.Changes {
+		log.Printf("   • %s", change)
+	}
+	migrationMutex.Lock()
+	migrationReports = append(migrationReports, report)
+	migrationMutex.Unlock()
+}
+
+// migrateConfig - Extrait les données d'un fichier et les met à niveau
+// Un fichier sans enveloppe (anciennes versions) est en version 0
+func migrateConfig(kind string, data []byte) (json.RawMessage, *ConfigMigrationReport, error) {
+	version, payload, err := unwrapConfig(kind, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	current := CurrentSchemaVersion(kind)
+	if version > current {
+		log.Printf("⚠️ Fichier %s au schéma v%d, plus récent que cette application (v%d): les champs inconnus seront perdus à la prochaine sauvegarde",
+			kind, version, current)
+		return payload, nil, nil
+	}
+	if version == current {
+		return payload, nil, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber() // Conserver les nombres tels quels
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, nil, err
+	}
+
+	report := &ConfigMigrationReport{Kind: kind, FromVersion: version, ToVersion: current, MigratedAt: time.Now()}
+	for _, step := range configMigrations[kind][version:] {
+		changes, err := step.Apply(&doc)
+		if err != nil {
+			return nil, nil, fmt.Errorf("migration %s v%d → v%d: %s", kind, step.From, step.From+1, err)
+		}
+		report.Changes = append(report.Changes, fmt.Sprintf("v%d → v%d: %s", step.From, step.From+1, step.Description))
+		for _, change := range changes {
+			report.Changes = append(report.Changes, fmt.Sprintf("v%d → v%d: %s", step.From, step.From+1, change))
+		}
+	}
+
+	migrated, err := json.Marshal(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return migrated, report, nil
+}
+
+// unwrapConfig - Version et données d'un fichier, avec ou sans enveloppe
+func unwrapConfig(kind string, data []byte) (int, json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		// Tableau (servers.json des anciennes versions) ou JSON invalide
+		if !json.Valid(data) {
+			return 0, nil, err
+		}
+		return 0, data, nil
+	}
+	if _, ok := fields["schema_version"]; !ok {
+		return 0, data, nil
+	}
+
+	var envelope ConfigEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return 0, nil, fmt.Errorf("enveloppe de configuration invalide: %s", err)
+	}
+	if envelope.Kind != kind {
+		return 0, nil, fmt.Errorf("fichier de type %q au lieu de %q", envelope.Kind, kind)
+	}
+	if envelope.SchemaVersion < 0 || len(envelope.Data) == 0 {
+		return 0, nil, fmt.Errorf("enveloppe de configuration invalide (version %d)", envelope.SchemaVersion)
+	}
+	return envelope.SchemaVersion, envelope.Data, nil
+}
+
+// migrateSettingsV0 - Ancien champ smtp_config.tls (booléen) remplacé par
+// tls_mode, champs absents complétés avec leur valeur par défaut
+func migrateSettingsV0(doc *any) ([]string, error) {
+	settings, ok := (*doc).(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("objet JSON attendu pour les settings")
+	}
+	var changes []string
+
+	if smtp, ok := settings["smtp_config"].(map[string]any); ok {
+		if legacy, found := smtp["tls"]; found {
+			delete(smtp, "tls")
+			if mode, _ := smtp["tls_mode"].(string); mode == "" {
+				smtp["tls_mode"] = TLSModeNone
+				if legacy == true {
+					smtp["tls_mode"] = TLSModeSTARTTLS
+				}
+				changes = append(changes, fmt.Sprintf("smtp_config.tls=%v remplacé par tls_mode=%q", legacy, smtp["tls_mode"]))
+			} else {
+				changes = append(changes, "smtp_config.tls supprimé (tls_mode déjà défini)")
+			}
+		}
+	}
+
+	// Les champs ajoutés depuis la création du fichier prennent la valeur par défaut
+	data, err := json.Marshal(DefaultSettings())
+	if err != nil {
+		return nil, err
+	}
+	var defaults map[string]any
+	if err := json.Unmarshal(data, &defaults); err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(defaults))
+	for key := range defaults {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if _, found := settings[key]; found {
+			continue
+		}
+		settings[key] = defaults[key]
+		value, _ := json.Marshal(defaults[key])
+		changes = append(changes, fmt.Sprintf("%s ajouté avec la valeur par défaut %s", key, value))
+	}
+	return changes, nil
+}
+
+// migrateServersV0 - Signale les serveurs sans type de check
+// Ces serveurs n'ont jamais été surveillés (check en erreur « type non
+// supporté »): leur type reste vide pour ne pas changer leur comportement
+// sans l'accord de l'utilisateur, qui doit le choisir dans l'application
+func migrateServersV0(doc *any) ([]string, error) {
+	servers, ok := (*doc).([]any)
+	if !ok {
+		return nil, fmt.Errorf("tableau JSON attendu pour les serveurs")
+	}
+	var changes []string
+	for _, item := range servers {
+		server, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("objet JSON attendu pour chaque serveur")
+		}
+		if kind, _ := server["type"].(string); kind == "" {
+			changes = append(changes, fmt.Sprintf("serveur %v: aucun type de check, non surveillé (type à choisir)", server["name"]))
+		}
+	}
+	return changes, nil
+}
@@ -0,0 +1,275 @@
+package backend
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMigrateConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     string
+		data     string
+		version  int    // Version d'origine attendue dans le compte rendu (-1: pas de migration)
+		tlsMode  string // smtp_config.tls_mode attendu après migration (settings)
+		wantSame bool   // Données renvoyées telles quelles
+		wantErr  string
+	}{
+		{
+			name:    "settings sans enveloppe, tls=true",
+			kind:    ConfigKindSettings,
+			data:    `{"theme":"dark","smtp_config":{"host":"smtp.example.com","tls":true}}`,
+			version: 0,
+			tlsMode: TLSModeSTARTTLS,
+		},
+		{
+			name:    "settings sans enveloppe, tls=false",
+			kind:    ConfigKindSettings,
+			data:    `{"smtp_config":{"host":"smtp.example.com","tls":false}}`,
+			version: 0,
+			tlsMode: TLSModeNone,
+		},
+		{
+			name:    "settings sans enveloppe, tls_mode déjà défini",
+			kind:    ConfigKindSettings,
+			data:    `{"smtp_config":{"host":"smtp.example.com","tls":false,"tls_mode":"implicit"}}`,
+			version: 0,
+			tlsMode: TLSModeImplicit,
+		},
+		{
+			name:    "servers.json en tableau nu",
+			kind:    ConfigKindServers,
+			data:    `[{"id":"1","name":"web","type":"http"},{"id":"2","name":"legacy"}]`,
+			version: 0,
+		},
+		{
+			name:     "enveloppe à la version courante",
+			kind:     ConfigKindServers,
+			data:     `{"schema_version":1,"kind":"servers","data":[{"id":"1","name":"web","type":"http"}]}`,
+			version:  -1,
+			wantSame: true,
+		},
+		{
+			name:     "enveloppe plus récente que l'application",
+			kind:     ConfigKindSettings,
+			data:     `{"schema_version":99,"kind":"settings","data":{"theme":"dark","smtp_config":{"tls":true},"future_field":1}}`,
+			version:  -1,
+			wantSame: true,
+		},
+		{
+			name:    "type de fichier différent",
+			kind:    ConfigKindSettings,
+			data:    `{"schema_version":1,"kind":"servers","data":[]}`,
+			wantErr: `fichier de type "servers" au lieu de "settings"`,
+		},
+		{
+			name:    "enveloppe sans données",
+			kind:    ConfigKindSettings,
+			data:    `{"schema_version":1,"kind":"settings"}`,
+			wantErr: "enveloppe de configuration invalide",
+		},
+		{
+			name:    "settings en tableau",
+			kind:    ConfigKindSettings,
+			data:    `[]`,
+			wantErr: "objet JSON attendu",
+		},
+		{
+			name:    "JSON invalide",
+			kind:    ConfigKindServers,
+			data:    `[{"id":`,
+			wantErr: "unexpected end of JSON input",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, report, err := migrateConfig(tt.kind, []byte(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("erreur %v, attendu %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("migrateConfig: %v", err)
+			}
+
+			if tt.version < 0 {
+				if report != nil {
+					t.Errorf("compte rendu inattendu: %+v", report)
+				}
+			} else {
+				if report == nil {
+					t.Fatal("aucun compte rendu de migration")
+				}
+				if report.FromVersion != tt.version || report.ToVersion != CurrentSchemaVersion(tt.kind) {
+					t.Errorf("migration v%d → v%d, attendu v%d → v%d",
+						report.FromVersion, report.ToVersion, tt.version, CurrentSchemaVersion(tt.kind))
+				}
+				if len(report.Changes) == 0 {
+					t.Error("aucun changement consigné")
+				}
+			}
+			if tt.wantSame {
+				var envelope ConfigEnvelope
+				if err := json.Unmarshal([]byte(tt.data), &envelope); err != nil {
+					t.Fatal(err)
+				}
+				if string(payload) != string(envelope.Data) {
+					t.Errorf("données modifiées:\n%s\nattendu:\n%s", payload, envelope.Data)
+				}
+			}
+
+			if tt.kind != ConfigKindSettings || tt.tlsMode == "" {
+				return
+			}
+			var settings map[string]any
+			if err := json.Unmarshal(payload, &settings); err != nil {
+				t.Fatalf("données migrées illisibles: %v", err)
+			}
+			smtp, _ := settings["smtp_config"].(map[string]any)
+			if _, found := smtp["tls"]; found {
+				t.Error("smtp_config.tls toujours présent")
+			}
+			if smtp["tls_mode"] != tt.tlsMode {
+				t.Errorf("tls_mode = %v, attendu %q", smtp["tls_mode"], tt.tlsMode)
+			}
+			if _, found := settings["notificationMode"]; !found {
+				t.Error("champs par défaut non ajoutés")
+			}
+		})
+	}
+}
+
+func TestMigrateConfigServersUnchanged(t *testing.T) {
+	data := `[{"id":"1","name":"web","type":"http","interval":30},{"id":"2","name":"legacy"}]`
+	payload, report, err := migrateConfig(ConfigKindServers, []byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, want []map[string]any
+	if err := json.Unmarshal(payload, &got); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(data), &want); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) || got[0]["interval"] != want[0]["interval"] || got[1]["type"] != nil {
+		t.Errorf("serveurs modifiés par la migration: %s", payload)
+	}
+	// Seul le serveur sans type est signalé
+	var flagged int
+	for _, change := range report.Changes {
+		if strings.Contains(change, "aucun type de check") {
+			flagged++
+			if !strings.Contains(change, "legacy") {
+				t.Errorf("mauvais serveur signalé: %s", change)
+			}
+		}
+	}
+	if flagged != 1 {
+		t.Errorf("%d serveurs signalés, attendu 1: %v", flagged, report.Changes)
+	}
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	t.Run("fichier corrompu restauré depuis la sauvegarde", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "settings.json")
+		if err := os.WriteFile(path, []byte(`{"theme":`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		backup := `{"theme":"dark","smtp_config":{"host":"smtp.example.com","tls":true}}`
+		if err := os.WriteFile(backupPath(path, 1), []byte(backup), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		var got map[string]any
+		err := LoadConfigFile(path, ConfigKindSettings, ConfigBackups, func(data json.RawMessage) error {
+			got = nil
+			return json.Unmarshal(data, &got)
+		})
+		if err != nil {
+			t.Fatalf("LoadConfigFile: %v", err)
+		}
+		if got["theme"] != "dark" {
+			t.Errorf("données de la sauvegarde non chargées: %v", got)
+		}
+
+		// Le fichier est réécrit au schéma courant
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var envelope ConfigEnvelope
+		if err := json.Unmarshal(data, &envelope); err != nil {
+			t.Fatalf("fichier réécrit illisible: %v", err)
+		}
+		if envelope.Kind != ConfigKindSettings || envelope.SchemaVersion != CurrentSchemaVersion(ConfigKindSettings) {
+			t.Errorf("enveloppe %s v%d, attendu %s v%d", envelope.Kind, envelope.SchemaVersion,
+				ConfigKindSettings, CurrentSchemaVersion(ConfigKindSettings))
+		}
+	})
+
+	t.Run("decode refuse le fichier et accepte la sauvegarde", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "servers.json")
+		current, err := EncodeConfig(ConfigKindServers, []map[string]any{{"id": "1", "name": ""}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		previous, err := EncodeConfig(ConfigKindServers, []map[string]any{{"id": "1", "name": "web"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		os.WriteFile(path, current, 0o600)
+		os.WriteFile(backupPath(path, 1), previous, 0o600)
+
+		var names []string
+		err = LoadConfigFile(path, ConfigKindServers, ConfigBackups, func(data json.RawMessage) error {
+			var servers []struct {
+				Name string `json:"name"`
+			}
+			if err := json.Unmarshal(data, &servers); err != nil {
+				return err
+			}
+			names = nil
+			for _, s := range servers {
+				if s.Name == "" {
+					return os.ErrInvalid
+				}
+				names = append(names, s.Name)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("LoadConfigFile: %v", err)
+		}
+		if len(names) != 1 || names[0] != "web" {
+			t.Errorf("serveurs chargés %v, attendu [web]", names)
+		}
+	})
+
+	t.Run("type différent sans sauvegarde", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "settings.json")
+		data, err := EncodeConfig(ConfigKindServers, []any{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		os.WriteFile(path, data, 0o600)
+		err = LoadConfigFile(path, ConfigKindSettings, ConfigBackups, func(json.RawMessage) error { return nil })
+		if err == nil || !strings.Contains(err.Error(), "au lieu de") {
+			t.Errorf("erreur %v, attendu un refus du type de fichier", err)
+		}
+	})
+
+	t.Run("fichier absent", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "settings.json")
+		err := LoadConfigFile(path, ConfigKindSettings, ConfigBackups, func(json.RawMessage) error { return nil })
+		if !os.IsNotExist(err) {
+			t.Errorf("erreur %v, attendu os.ErrNotExist", err)
+		}
+	})
+}
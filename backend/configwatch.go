@@ -0,0 +1,162 @@
+// Package backend - Surveillance des fichiers de configuration
+// Ce fichier détecte les modifications de settings.json et servers.json faites
+// hors de l'application (édition manuelle, gestion de configuration) pour les
+// appliquer sans redémarrage. Les écritures de l'application elle-même sont
+// reconnues à leur contenu et ignorées
+package backend
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// configWatchDelay - Attente après la dernière modification avant rechargement
+// (un éditeur écrit souvent un fichier en plusieurs fois)
+const configWatchDelay = 500 * time.Millisecond
+
+// ConfigWatcher - Surveillance des fichiers du dossier de configuration
+type ConfigWatcher struct {
+	watcher  *fsnotify.Watcher
+	dir      string
+	handlers map[string]func() // nom du fichier → rechargement
+	mutex    sync.Mutex
+	timers   map[string]*time.Timer // Rechargements en attente par fichier
+	done     chan struct{}
+}
+
+// knownContents - Empreinte du dernier contenu écrit ou rechargé par chemin
+var knownContents sync.Map
+
+// rememberContent - Mémorise le contenu d'un fichier écrit par l'application
+func rememberContent(path string, data []byte) {
+	knownContents.Store(filepath.Clean(path), sha256.Sum256(data))
+}
+
+// contentChanged - Indique si un fichier diffère du dernier contenu connu
+// et mémorise le nouveau contenu
+func contentChanged(path string, data []byte) bool {
+	sum := sha256.Sum256(data)
+	previous, loaded := knownContents.Swap(filepath.Clean(path), sum)
+	return !loaded || previous.([sha256.Size]byte) != sum
+}
+
+// WatchConfigFiles - Surveille des fichiers du dossier de configuration
+// handlers associe un nom de fichier (ex: "servers.json") à sa fonction de
+// rechargement, appelée après chaque modification externe
+func WatchConfigFiles(handlers map[string]func()) (*ConfigWatcher, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("surveillance des fichiers indisponible: %s", err)
+	}
+	// Surveiller le dossier: les écritures atomiques remplacent le fichier
+	if err := watcher.Add(dir); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("surveillance de %s impossible: %s", dir, err)
+	}
+
+	// Contenu actuel: seule une modification ultérieure déclenche un rechargement
+	for name := range handlers {
+		if data, err := os.ReadFile(filepath.Join(dir, name)); err == nil {
+			contentChanged(filepath.Join(dir, name), data)
+		}
+	}
+
+	w := &ConfigWatcher{
+		watcher:  watcher,
+		dir:      dir,
+		handlers: handlers,
+		timers:   make(map[string]*time.Timer),
+		done:     make(chan struct{}),
+	}
+	go w.run()
+	log.Printf("👀 Surveillance des fichiers de configuration dans %s", dir)
+	return w, nil
+}
+
+// run - Boucle de réception des événements du système de fichiers
+func (w *ConfigWatcher) run() {
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) && !event.Has(fsnotify.Rename) {
+				continue
+			}
+			name := filepath.Base(event.Name)
+			if _, watched := w.handlers[name]; watched {
+				w.schedule(name)
+			}
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("⚠️ Surveillance des fichiers de configuration: %s", err)
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// schedule - Programme le rechargement d'un fichier (regroupe les événements proches)
+func (w *ConfigWatcher) schedule(name string) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if timer, ok := w.timers[name]; ok {
+		timer.Reset(configWatchDelay)
+		return
+	}
+	w.timers[name] = time.AfterFunc(configWatchDelay, func() {
+		w.mutex.Lock()
+		delete(w.timers, name)
+		w.mutex.Unlock()
+		w.reload(name)
+	})
+}
+
+// reload - Appelle le rechargement si le contenu a changé depuis la dernière
+// écriture de l'application
+func (w *ConfigWatcher) reload(name string) {
+	select {
+	case <-w.done:
+		return
+	default:
+	}
+	path := filepath.Join(w.dir, name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("⚠️ Lecture de %s impossible: %s", path, err)
+		}
+		return
+	}
+	if !contentChanged(path, data) {
+		return
+	}
+	log.Printf("📝 %s modifié sur le disque, rechargement", name)
+	w.handlers[name]()
+}
+
+// Close - Arrête la surveillance
+func (w *ConfigWatcher) Close() error {
+	close(w.done)
+	w.mutex.Lock()
+	for name, timer := range w.timers {
+		timer.Stop()
+		delete(w.timers, name)
+	}
+	w.mutex.Unlock()
+	return w.watcher.Close()
+}
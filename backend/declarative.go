@@ -0,0 +1,715 @@
+// Package backend - Configuration déclarative (YAML ou TOML)
+// Ce fichier lit un fichier de configuration versionnable (serveurs, groupes,
+// canaux de notification et settings), avec inclusions et interpolation des
+// variables d'environnement, et localise chaque erreur par fichier et ligne
+package backend
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DeclarativeConfigEnv - Variable d'environnement désignant le fichier déclaratif
+const DeclarativeConfigEnv = "MONITORING_SERV_CONFIG_FILE"
+
+// declarativeConfigNames - Fichiers déclaratifs recherchés dans le dossier de configuration
+var declarativeConfigNames = []string{"monitoring.yaml", "monitoring.yml", "monitoring.toml"}
+
+// notificationKeys - Clés de la section notifications → champs des settings
+var notificationKeys = map[string]string{
+	"mode":      "notificationMode",
+	"cooldown":  "notificationCooldown",
+	"user":      "userEmail",
+	"email":     "recipients",
+	"severity":  "severityRecipients",
+	"smtp":      "smtp_config",
+	"templates": "emailTemplates",
+	"relay":     "relayAllowList",
+}
+
+// ConfigPos - Emplacement d'une valeur dans un fichier déclaratif
+type ConfigPos struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// ConfigError - Erreur localisée d'un fichier déclaratif
+type ConfigError struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Field   string `json:"field,omitempty"` // Chemin du champ concerné (ex: "smtp_config.port")
+	Message string `json:"message"`
+}
+
+// Error - Format fichier:ligne: message (ligne N: message sans fichier)
+func (e ConfigError) Error() string {
+	if e.File == "" {
+		if e.Line == 0 {
+			return e.Message
+		}
+		return fmt.Sprintf("ligne %d: %s", e.Line, e.Message)
+	}
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.File, e.Message)
+}
+
+// ConfigErrors - Erreurs d'un fichier déclaratif, une par ligne
+type ConfigErrors []ConfigError
+
+// Error - Toutes les erreurs, une par ligne
+func (e ConfigErrors) Error() string {
+	lines := make([]string, 0, len(e))
+	for _, err := range e {
+		lines = append(lines, err.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// DeclaredServer - Serveur décrit dans un fichier déclaratif
+// Data contient les champs JSON du serveur, valeurs par défaut du groupe comprises
+type DeclaredServer struct {
+	Name   string
+	Group  string
+	Data   map[string]any
+	Pos    ConfigPos
+	Fields map[string]ConfigPos // Position de chaque champ
+}
+
+// Error - Erreur localisée sur un champ du serveur (ou sur le serveur entier)
+func (s DeclaredServer) Error(field, format string, args ...any) ConfigError {
+	pos, ok := s.Fields[field]
+	if !ok {
+		pos, field = s.Pos, ""
+	}
+	message := fmt.Sprintf(format, args...)
+	if s.Name != "" {
+		message = fmt.Sprintf("serveur %q: %s", s.Name, message)
+	}
+	return ConfigError{File: pos.File, Line: pos.Line, Field: field, Message: message}
+}
+
+// DeclarativeConfig - Contenu d'un fichier déclaratif et de ses inclusions
+type DeclarativeConfig struct {
+	Path    string           // Fichier principal
+	Files   []string         // Fichiers lus, inclusions comprises
+	Groups  []string         // Noms des groupes déclarés
+	Servers []DeclaredServer // Serveurs, dans l'ordre de déclaration
+
+	settings      map[string]any       // Surcharge des settings (clés JSON)
+	settingsIndex map[string]ConfigPos // Position de chaque champ de settings
+	settingsPos   *ConfigPos           // Première section settings ou notifications
+}
+
+// DeclarativeConfigPath - Fichier déclaratif à charger: variable
+// MONITORING_SERV_CONFIG_FILE, sinon monitoring.yaml, .yml ou .toml dans le
+// dossier de configuration. Vide si aucun fichier n'est utilisé
+func DeclarativeConfigPath() string {
+	if path := os.Getenv(DeclarativeConfigEnv); path != "" {
+		return path
+	}
+	dir, err := ConfigDir()
+	if err != nil {
+		return ""
+	}
+	for _, name := range declarativeConfigNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// declLoader - État du chargement d'un fichier déclaratif et de ses inclusions
+type declLoader struct {
+	config   *DeclarativeConfig
+	file     string          // Fichier en cours de lecture
+	visiting map[string]bool // Inclusions en cours (détection des cycles)
+	groups   map[string]bool
+	errors   ConfigErrors
+
+	envRefs map[*yaml.Node]bool // Valeurs entre guillemets réduites à une variable (typées selon le champ cible)
+}
+
+// LoadDeclarativeConfig - Lit un fichier déclaratif YAML ou TOML
+// Renvoie toutes les erreurs trouvées (syntaxe, variables, clés inconnues),
+// triées par fichier et ligne
+func LoadDeclarativeConfig(path string) (*DeclarativeConfig, ConfigErrors) {
+	l := &declLoader{
+		config: &DeclarativeConfig{
+			Path:          path,
+			settings:      make(map[string]any),
+			settingsIndex: make(map[string]ConfigPos),
+		},
+		visiting: make(map[string]bool),
+		groups:   make(map[string]bool),
+		envRefs:  make(map[*yaml.Node]bool),
+	}
+	l.loadFile(path, ConfigPos{})
+	return l.config, l.errors.Sorted()
+}
+
+// Sorted - Erreurs triées par fichier puis par ligne
+func (e ConfigErrors) Sorted() ConfigErrors {
+	sort.SliceStable(e, func(i, j int) bool {
+		if e[i].File != e[j].File {
+			return e[i].File < e[j].File
+		}
+		return e[i].Line < e[j].Line
+	})
+	return e
+}
+
+// errorf - Ajoute une erreur à une ligne du fichier en cours
+func (l *declLoader) errorf(line int, format string, args ...any) {
+	l.errors = append(l.errors, ConfigError{File: l.file, Line: line, Message: fmt.Sprintf(format, args...)})
+}
+
+// pos - Position d'un nœud dans le fichier en cours
+func (l *declLoader) pos(node *yaml.Node) ConfigPos {
+	return ConfigPos{File: l.file, Line: node.Line}
+}
+
+// loadFile - Lit un fichier (principal ou inclus) et fusionne son contenu
+// from est la position de l'inclusion (vide pour le fichier principal)
+func (l *declLoader) loadFile(path string, from ConfigPos) {
+	parent := l.file
+	defer func() { l.file = parent }()
+
+	report := func(format string, args ...any) {
+		l.errors = append(l.errors, ConfigError{File: from.File, Line: from.Line, Message: fmt.Sprintf(format, args...)})
+		if from.File == "" {
+			l.errors[len(l.errors)-1].File = path
+		}
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		report("chemin invalide: %s", err)
+		return
+	}
+	if l.visiting[abs] {
+		report("inclusion circulaire de %s", path)
+		return
+	}
+	l.visiting[abs] = true
+	defer delete(l.visiting, abs)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		report("lecture de %s impossible: %s", path, err)
+		return
+	}
+	l.file = path
+	l.config.Files = append(l.config.Files, path)
+
+	root, err := parseDeclarative(path, data)
+	if err != nil {
+		var ce *ConfigError
+		if errors.As(err, &ce) {
+			l.errorf(ce.Line, "%s", ce.Message)
+		} else {
+			l.errorf(0, "%s", err)
+		}
+		return
+	}
+	if root == nil {
+		return // Fichier vide
+	}
+	if root.Kind != yaml.MappingNode {
+		l.errorf(root.Line, "le document doit être une table de sections (include, settings, notifications, groups, servers)")
+		return
+	}
+	l.interpolate(root)
+
+	// Les inclusions d'abord: le fichier qui inclut a le dernier mot
+	if include := mappingValue(root, "include"); include != nil {
+		l.includes(include)
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		switch key.Value {
+		case "include":
+		case "settings":
+			l.settings(value)
+		case "notifications":
+			l.notifications(value)
+		case "groups":
+			l.groupList(value)
+		case "servers":
+			l.serverList(value, "", nil, nil)
+		default:
+			l.errorf(key.Line, "section inconnue: %s", key.Value)
+		}
+	}
+}
+
+// yamlLineRe - Numéro de ligne dans les messages d'erreur de yaml.v3
+var yamlLineRe = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// parseDeclarative - Analyse un fichier YAML ou TOML (selon son extension)
+// Renvoie la racine du document, nil si le document est vide
+func parseDeclarative(path string, data []byte) (*yaml.Node, error) {
+	var doc yaml.Node
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		parsed, err := parseTOML(data)
+		if err != nil {
+			return nil, err
+		}
+		doc = *parsed
+	} else if err := yaml.Unmarshal(data, &doc); err != nil {
+		if m := yamlLineRe.FindStringSubmatch(err.Error()); m != nil {
+			line, _ := strconv.Atoi(m[1])
+			return nil, &ConfigError{Line: line, Message: m[2]}
+		}
+		return nil, fmt.Errorf("%s", strings.TrimPrefix(err.Error(), "yaml: "))
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil, nil
+	}
+	return doc.Content[0], nil
+}
+
+// includes - Fichiers inclus: chemin ou motif, relatif au fichier en cours
+func (l *declLoader) includes(node *yaml.Node) {
+	items := []*yaml.Node{node}
+	if node.Kind == yaml.SequenceNode {
+		items = node.Content
+	}
+	for _, item := range items {
+		if item.Kind != yaml.ScalarNode || item.Value == "" {
+			l.errorf(item.Line, "include: chemin de fichier attendu")
+			continue
+		}
+		pattern := item.Value
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(filepath.Dir(l.file), pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			l.errorf(item.Line, "include: motif invalide %q", item.Value)
+			continue
+		}
+		// Un chemin sans joker doit exister, un motif peut ne rien désigner
+		if len(matches) == 0 && !strings.ContainsAny(item.Value, "*?[") {
+			l.errorf(item.Line, "include: fichier introuvable: %s", item.Value)
+			continue
+		}
+		for _, match := range matches {
+			l.loadFile(match, l.pos(item))
+		}
+	}
+}
+
+// envRefRe - Référence ${NOM} ou ${NOM:-défaut}, ou échappement $$
+var envRefRe = regexp.MustCompile(`\$\$|\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// interpolate - Remplace les variables d'environnement dans les valeurs
+// Une valeur sans guillemets composée d'une seule référence prend le type de
+// son contenu (port: ${SMTP_PORT} donne un nombre). Entre guillemets, elle
+// reste du texte (password: "${SMTP_PASSWORD}"), sauf pour un paramètre
+// numérique ou booléen (voir checkKeys)
+func (l *declLoader) interpolate(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			l.interpolate(node.Content[i])
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			l.interpolate(item)
+		}
+	case yaml.ScalarNode:
+		if !strings.Contains(node.Value, "$") {
+			return
+		}
+		whole := envRefRe.FindString(node.Value) == node.Value && node.Value != "$$"
+		value := envRefRe.ReplaceAllStringFunc(node.Value, func(ref string) string {
+			if ref == "$$" {
+				return "$"
+			}
+			m := envRefRe.FindStringSubmatch(ref)
+			hasDefault := strings.Contains(ref, ":-")
+			// Comme en shell, ${NOM:-défaut} remplace aussi une variable vide
+			if value, ok := os.LookupEnv(m[1]); ok && (value != "" || !hasDefault) {
+				return value
+			}
+			if hasDefault {
+				return m[2]
+			}
+			l.errorf(node.Line, "variable d'environnement %s non définie", m[1])
+			return ""
+		})
+		node.Value = value
+		node.Tag = "!!str"
+		switch {
+		case node.Style == 0:
+			node.Tag = resolveScalarTag(value)
+		case whole:
+			l.envRefs[node] = true
+		}
+	}
+}
+
+// resolveScalarTag - Type d'une valeur non typée (booléen, nombre, null ou texte)
+func resolveScalarTag(value string) string {
+	switch value {
+	case "true", "false":
+		return "!!bool"
+	case "", "null", "~":
+		return "!!null"
+	}
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return "!!int"
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return "!!float"
+	}
+	return "!!str"
+}
+
+// convert - Valeur générique d'un nœud (compatible encoding/json)
+// index reçoit la position de chaque valeur par chemin JSON (ex: "recipients.cc[1]")
+func (l *declLoader) convert(node *yaml.Node, path string, index map[string]ConfigPos) any {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	if index != nil && path != "" {
+		index[path] = l.pos(node)
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		result := make(map[string]any, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			// Clé de fusion YAML (<<: *ancre): valeurs par défaut de la table
+			if key.Value == "<<" && key.Style == 0 {
+				if merged, ok := l.convert(value, path, nil).(map[string]any); ok {
+					for k, v := range merged {
+						if _, set := result[k]; !set {
+							result[k] = v
+						}
+					}
+				}
+				continue
+			}
+			if key.Kind != yaml.ScalarNode {
+				l.errorf(key.Line, "clé de table invalide")
+				continue
+			}
+			if _, dup := result[key.Value]; dup {
+				l.errorf(key.Line, "clé %q en double", key.Value)
+				continue
+			}
+			result[key.Value] = l.convert(value, joinConfigPath(path, key.Value), index)
+		}
+		return result
+	case yaml.SequenceNode:
+		result := make([]any, 0, len(node.Content))
+		for i, item := range node.Content {
+			result = append(result, l.convert(item, fmt.Sprintf("%s[%d]", path, i), index))
+		}
+		return result
+	case yaml.ScalarNode:
+		switch node.ShortTag() {
+		case "!!null":
+			return nil
+		case "!!bool", "!!int", "!!float":
+			var value any
+			if err := node.Decode(&value); err != nil {
+				l.errorf(node.Line, "valeur invalide %q: %s", node.Value, err)
+				return nil
+			}
+			if f, ok := value.(float64); ok && (math.IsNaN(f) || math.IsInf(f, 0)) {
+				l.errorf(node.Line, "nombre non représentable: %s", node.Value)
+				return nil
+			}
+			return value
+		}
+		return node.Value
+	}
+	return nil
+}
+
+// joinConfigPath - Chemin JSON d'une clé dans une table
+func joinConfigPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// settings - Section settings (clés JSON de settings.json)
+func (l *declLoader) settings(node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		l.errorf(node.Line, "settings: table attendue")
+		return
+	}
+	if l.config.settingsPos == nil {
+		pos := l.pos(node)
+		l.config.settingsPos = &pos
+	}
+	l.checkKeys(node, reflect.TypeOf(Settings{}), "")
+	if value, ok := l.convert(node, "", l.config.settingsIndex).(map[string]any); ok {
+		deepMerge(l.config.settings, value)
+	}
+}
+
+// notifications - Section notifications: canaux et destinataires des alertes
+func (l *declLoader) notifications(node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		l.errorf(node.Line, "notifications: table attendue")
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		field, ok := notificationKeys[key.Value]
+		if !ok {
+			l.errorf(key.Line, "notifications: clé inconnue: %s", key.Value)
+			continue
+		}
+		// Valeur simple ou table: placée sous le champ correspondant des settings
+		wrapper := &yaml.Node{Kind: yaml.MappingNode, Line: key.Line, Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: field, Line: key.Line}, value,
+		}}
+		l.settings(wrapper)
+	}
+}
+
+// groupList - Section groups: serveurs partageant des valeurs par défaut
+func (l *declLoader) groupList(node *yaml.Node) {
+	if node.Kind != yaml.SequenceNode {
+		l.errorf(node.Line, "groups: liste attendue")
+		return
+	}
+	for _, group := range node.Content {
+		if group.Kind != yaml.MappingNode {
+			l.errorf(group.Line, "groups: table attendue pour chaque groupe")
+			continue
+		}
+		var (
+			name     string
+			defaults map[string]any
+			fields   = make(map[string]ConfigPos)
+			servers  *yaml.Node
+		)
+		for i := 0; i+1 < len(group.Content); i += 2 {
+			key, value := group.Content[i], group.Content[i+1]
+			switch key.Value {
+			case "name":
+				name = value.Value
+			case "defaults":
+				if value.Kind != yaml.MappingNode {
+					l.errorf(value.Line, "groups: defaults doit être une table")
+					continue
+				}
+				defaults, _ = l.convert(value, "", fields).(map[string]any)
+			case "servers":
+				servers = value
+			default:
+				l.errorf(key.Line, "groups: clé inconnue: %s", key.Value)
+			}
+		}
+		if name == "" {
+			l.errorf(group.Line, "groups: nom du groupe requis")
+			continue
+		}
+		if l.groups[name] {
+			l.errorf(group.Line, "groups: groupe %q déjà déclaré", name)
+			continue
+		}
+		l.groups[name] = true
+		l.config.Groups = append(l.config.Groups, name)
+		if servers != nil {
+			l.serverList(servers, name, defaults, fields)
+		}
+	}
+}
+
+// serverList - Liste de serveurs, complétés par les valeurs par défaut du groupe
+func (l *declLoader) serverList(node *yaml.Node, group string, defaults map[string]any, defaultFields map[string]ConfigPos) {
+	if node.Kind != yaml.SequenceNode {
+		l.errorf(node.Line, "servers: liste attendue")
+		return
+	}
+	for _, item := range node.Content {
+		if item.Kind != yaml.MappingNode {
+			l.errorf(item.Line, "servers: table attendue pour chaque serveur")
+			continue
+		}
+		server := DeclaredServer{Group: group, Pos: l.pos(item), Fields: make(map[string]ConfigPos)}
+		for field, pos := range defaultFields {
+			server.Fields[field] = pos
+		}
+		data, _ := l.convert(item, "", server.Fields).(map[string]any)
+		server.Data = make(map[string]any, len(defaults)+len(data))
+		for key, value := range defaults {
+			server.Data[key] = value
+		}
+		for key, value := range data {
+			server.Data[key] = value
+		}
+		server.Name, _ = server.Data["name"].(string)
+		l.config.Servers = append(l.config.Servers, server)
+	}
+}
+
+// checkKeys - Signale les clés d'une table absentes du type Go cible
+// (champs JSON d'une structure, récursivement)
+func (l *declLoader) checkKeys(node *yaml.Node, t reflect.Type, path string) {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil {
+		return
+	}
+	switch {
+	case node.Kind == yaml.MappingNode && t.Kind() == reflect.Struct:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			if key.Value == "<<" {
+				continue
+			}
+			field := jsonFieldType(t, key.Value)
+			if field == nil {
+				l.errorf(key.Line, "clé inconnue: %s", joinConfigPath(path, key.Value))
+				continue
+			}
+			l.checkKeys(node.Content[i+1], field, joinConfigPath(path, key.Value))
+		}
+	case node.Kind == yaml.MappingNode && t.Kind() == reflect.Map:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			l.checkKeys(node.Content[i+1], t.Elem(), joinConfigPath(path, node.Content[i].Value))
+		}
+	case node.Kind == yaml.SequenceNode && t.Kind() == reflect.Slice:
+		for i, item := range node.Content {
+			l.checkKeys(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))
+		}
+	case node.Kind == yaml.ScalarNode && l.envRefs[node]:
+		// Variable entre guillemets (seule écriture possible en TOML) pour un
+		// paramètre numérique ou booléen: typée selon son contenu
+		tag := resolveScalarTag(node.Value)
+		switch t.Kind() {
+		case reflect.Bool:
+			if tag == "!!bool" {
+				node.Tag = tag
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if tag == "!!int" {
+				node.Tag = tag
+			}
+		case reflect.Float32, reflect.Float64:
+			if tag == "!!int" || tag == "!!float" {
+				node.Tag = tag
+			}
+		}
+	}
+}
+
+// jsonFieldType - Type du champ d'une structure portant un nom JSON (nil si absent)
+func jsonFieldType(t reflect.Type, name string) reflect.Type {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := strings.Split(field.Tag.Get("json"), ",")[0]
+		if tag == "-" || !field.IsExported() {
+			continue
+		}
+		if tag == name || (tag == "" && strings.EqualFold(field.Name, name)) {
+			return field.Type
+		}
+	}
+	return nil
+}
+
+// deepMerge - Fusionne src dans dst: les tables sont fusionnées, les autres
+// valeurs remplacées
+func deepMerge(dst, src map[string]any) {
+	for key, value := range src {
+		if sub, ok := value.(map[string]any); ok {
+			if existing, ok := dst[key].(map[string]any); ok {
+				deepMerge(existing, sub)
+				continue
+			}
+		}
+		dst[key] = value
+	}
+}
+
+// HasSettings - Indique si le fichier déclare des settings ou des notifications
+func (c *DeclarativeConfig) HasSettings() bool {
+	return c.settingsPos != nil
+}
+
+// ApplySettings - Settings du fichier appliqués sur base, puis validés
+// Les erreurs de validation sont rapportées à la ligne du champ concerné
+func (c *DeclarativeConfig) ApplySettings(base Settings) (Settings, ConfigErrors) {
+	if !c.HasSettings() {
+		return base, nil
+	}
+	data, err := json.Marshal(base)
+	if err != nil {
+		return base, ConfigErrors{{File: c.Path, Message: err.Error()}}
+	}
+	var merged map[string]any
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return base, ConfigErrors{{File: c.Path, Message: err.Error()}}
+	}
+	deepMerge(merged, c.settings)
+	if data, err = json.Marshal(merged); err != nil {
+		return base, ConfigErrors{{File: c.Path, Message: err.Error()}}
+	}
+
+	var s Settings
+	if err := json.Unmarshal(data, &s); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return base, ConfigErrors{c.settingsError(typeErr.Field, fmt.Sprintf("%s: type %s attendu", typeErr.Field, typeErr.Type))}
+		}
+		return base, ConfigErrors{c.settingsError("", err.Error())}
+	}
+
+	var errs ConfigErrors
+	for _, field := range ValidateSettings(s) {
+		errs = append(errs, c.settingsError(field.Field, field.Message))
+	}
+	if len(errs) > 0 {
+		return base, errs.Sorted()
+	}
+	return s, nil
+}
+
+// settingsError - Erreur sur un champ de settings, à la ligne du champ ou de
+// son parent le plus proche déclaré dans le fichier
+func (c *DeclarativeConfig) settingsError(field, message string) ConfigError {
+	for path := field; path != ""; {
+		if pos, ok := c.settingsIndex[path]; ok {
+			return ConfigError{File: pos.File, Line: pos.Line, Field: field, Message: message}
+		}
+		cut := strings.LastIndexAny(path, ".[")
+		if cut < 0 {
+			break
+		}
+		path = path[:cut]
+	}
+	pos := ConfigPos{File: c.Path}
+	if c.settingsPos != nil {
+		pos = *c.settingsPos
+	}
+	return ConfigError{File: pos.File, Line: pos.Line, Field: field, Message: message}
+}
@@ -0,0 +1,126 @@
+package backend
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeConfig - Écrit un fichier déclaratif dans un dossier temporaire
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDeclarativeInterpolation(t *testing.T) {
+	t.Setenv("SMTP_PASSWORD", "123456")
+	t.Setenv("SMTP_USER", "true")
+	t.Setenv("SMTP_PORT", "2525")
+
+	tests := []struct {
+		name string
+		file string
+		src  string
+	}{
+		{
+			name: "YAML",
+			file: "monitoring.yaml",
+			src: `notifications:
+  smtp:
+    host: smtp.example.com
+    port: ${SMTP_PORT}
+    username: "${SMTP_USER}"
+    password: "${SMTP_PASSWORD}"
+`,
+		},
+		{
+			name: "YAML, port entre guillemets",
+			file: "monitoring.yaml",
+			src: `notifications:
+  smtp:
+    host: smtp.example.com
+    port: "${SMTP_PORT}"
+    username: '${SMTP_USER}'
+    password: "${SMTP_PASSWORD}"
+`,
+		},
+		{
+			name: "TOML",
+			file: "monitoring.toml",
+			src: `[notifications.smtp]
+host = "smtp.example.com"
+port = "${SMTP_PORT}"
+username = "${SMTP_USER}"
+password = "${SMTP_PASSWORD}"
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, errs := LoadDeclarativeConfig(writeConfig(t, tt.file, tt.src))
+			if len(errs) > 0 {
+				t.Fatalf("erreurs inattendues: %s", errs)
+			}
+			s, errs := config.ApplySettings(DefaultSettings())
+			if len(errs) > 0 {
+				t.Fatalf("erreurs inattendues: %s", errs)
+			}
+			// Les valeurs entre guillemets restent du texte, même si elles ressemblent à un nombre ou un booléen
+			if s.SMTPConfig.Password != "123456" || s.SMTPConfig.Username != "true" {
+				t.Errorf("identifiants: %q / %q", s.SMTPConfig.Username, s.SMTPConfig.Password)
+			}
+			if s.SMTPConfig.Port != 2525 {
+				t.Errorf("port: %d", s.SMTPConfig.Port)
+			}
+		})
+	}
+}
+
+func TestDeclarativeInterpolationErrors(t *testing.T) {
+	os.Unsetenv("MONITORING_TEST_UNDEFINED")
+	tests := []struct {
+		name string
+		src  string
+		line int
+	}{
+		{"variable non définie", "servers:\n  - name: a\n    url: http://${MONITORING_TEST_UNDEFINED}/\n", 3},
+		{"texte pour un port", "notifications:\n  smtp:\n    port: \"${MONITORING_TEST_UNDEFINED:-abc}\"\n", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, errs := LoadDeclarativeConfig(writeConfig(t, "monitoring.yaml", tt.src))
+			if len(errs) == 0 {
+				_, errs = config.ApplySettings(DefaultSettings())
+			}
+			if len(errs) == 0 {
+				t.Fatal("erreur attendue")
+			}
+			if errs[0].Line != tt.line {
+				t.Errorf("ligne %d, attendu %d (%s)", errs[0].Line, tt.line, errs[0].Message)
+			}
+		})
+	}
+}
+
+func TestDeclarativeDefaultValue(t *testing.T) {
+	t.Setenv("MONITORING_TEST_EMPTY", "")
+	config, errs := LoadDeclarativeConfig(writeConfig(t, "monitoring.yaml",
+		"notifications:\n  cooldown: ${MONITORING_TEST_EMPTY:-15}\n  smtp:\n    host: \"$${HOME}\"\n    port: 25\n"))
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	s, errs := config.ApplySettings(DefaultSettings())
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if s.NotificationCooldown != 15 {
+		t.Errorf("cooldown: %d", s.NotificationCooldown)
+	}
+	if s.SMTPConfig.Host != "${HOME}" {
+		t.Errorf("host: %q", s.SMTPConfig.Host)
+	}
+}
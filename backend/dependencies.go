@@ -0,0 +1,125 @@
+// Package backend - Dépendances entre serveurs
+// Un serveur peut déclarer des parents (routeur, pare-feu, hôte...) dont
+// dépend son accès: quand un parent est DOWN, ses dépendants sont
+// injoignables et seule la panne du parent est notifiée
+package backend
+
+import "sort"
+
+// DependencyGraph - Parents de chaque serveur, par identifiant
+type DependencyGraph map[string][]string
+
+// ParentState - Dernier état connu d'un serveur parent
+type ParentState struct {
+	Name      string // Nom affiché du parent
+	Checked   bool   // Au moins une vérification effectuée
+	IsUp      bool   // État de la dernière vérification
+	RootCause string // Cause racine de sa panne s'il est lui-même injoignable
+}
+
+// RootCause - Cause racine connue de la panne d'un serveur, d'après l'état de
+// ses parents dans l'ordre déclaré: le premier parent DOWN, ou sa propre cause
+// racine s'il est injoignable. Vide si aucun parent vérifié n'est DOWN
+func RootCause(parents []ParentState) string {
+	for _, parent := range parents {
+		if !parent.Checked || parent.IsUp {
+			continue
+		}
+		if parent.RootCause != "" {
+			return parent.RootCause
+		}
+		return parent.Name
+	}
+	return ""
+}
+
+// NormalizeParents - Parents sans doublons ni valeurs vides, dans l'ordre saisi
+func NormalizeParents(parents []string) []string {
+	seen := make(map[string]bool, len(parents))
+	var result []string
+	for _, parent := range parents {
+		if parent == "" || seen[parent] {
+			continue
+		}
+		seen[parent] = true
+		result = append(result, parent)
+	}
+	return result
+}
+
+// Cycle - Première dépendance circulaire trouvée (ex: [a b a]), nil si aucune
+// Les serveurs sont parcourus par identifiant pour un résultat stable
+func (g DependencyGraph) Cycle() []string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int, len(g))
+	var path []string
+
+	var visit func(id string) []string
+	visit = func(id string) []string {
+		switch state[id] {
+		case visiting:
+			// Retour sur un serveur du chemin en cours: le cycle part de lui
+			for i, step := range path {
+				if step == id {
+					return append(append([]string(nil), path[i:]...), id)
+				}
+			}
+		case done:
+			return nil
+		}
+		state[id] = visiting
+		path = append(path, id)
+		for _, parent := range g[id] {
+			if cycle := visit(parent); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = done
+		return nil
+	}
+
+	ids := make([]string, 0, len(g))
+	for id := range g {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if cycle := visit(id); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// Dependents - Serveurs qui dépendent d'un serveur, directement ou non (triés)
+func (g DependencyGraph) Dependents(id string) []string {
+	children := make(map[string][]string)
+	for child, parents := range g {
+		for _, parent := range parents {
+			children[parent] = append(children[parent], child)
+		}
+	}
+
+	seen := map[string]bool{id: true}
+	queue := []string{id}
+	var result []string
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, child := range children[current] {
+			if seen[child] {
+				continue
+			}
+			seen[child] = true
+			result = append(result, child)
+			queue = append(queue, child)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
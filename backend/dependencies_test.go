@@ -0,0 +1,123 @@
+package backend
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeParents(t *testing.T) {
+	got := NormalizeParents([]string{"b", "", "a", "b", "c", "a"})
+	if want := []string{"b", "a", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("NormalizeParents = %v, attendu %v", got, want)
+	}
+	if got := NormalizeParents([]string{"", ""}); got != nil {
+		t.Errorf("NormalizeParents sans parent = %v, attendu nil", got)
+	}
+}
+
+func TestDependencyGraphCycle(t *testing.T) {
+	tests := []struct {
+		name  string
+		graph DependencyGraph
+		want  []string
+	}{
+		{
+			name:  "sans dépendance",
+			graph: DependencyGraph{"a": nil, "b": nil},
+		},
+		{
+			name:  "chaîne et losange",
+			graph: DependencyGraph{"web": {"lb", "fw"}, "lb": {"fw"}, "fw": {"router"}, "router": nil},
+		},
+		{
+			name:  "boucle sur soi-même",
+			graph: DependencyGraph{"a": {"a"}},
+			want:  []string{"a", "a"},
+		},
+		{
+			name:  "cycle indirect",
+			graph: DependencyGraph{"a": {"b"}, "b": {"c"}, "c": {"a"}},
+			want:  []string{"a", "b", "c", "a"},
+		},
+		{
+			name:  "cycle atteint par un serveur hors du cycle",
+			graph: DependencyGraph{"a": {"x"}, "x": {"y"}, "y": {"z"}, "z": {"x"}},
+			want:  []string{"x", "y", "z", "x"},
+		},
+		{
+			name:  "parent absent du graphe",
+			graph: DependencyGraph{"a": {"ghost"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.graph.Cycle(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Cycle = %v, attendu %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDependencyGraphDependents(t *testing.T) {
+	// router ← fw ← {lb, vpn}; lb ← {web, api}; web et api dépendent aussi de fw
+	graph := DependencyGraph{
+		"router": nil,
+		"fw":     {"router"},
+		"lb":     {"fw"},
+		"vpn":    {"fw"},
+		"web":    {"lb", "fw"},
+		"api":    {"lb", "fw", "fw"},
+		"db":     nil,
+	}
+	tests := []struct {
+		id   string
+		want []string
+	}{
+		{id: "router", want: []string{"api", "fw", "lb", "vpn", "web"}},
+		{id: "fw", want: []string{"api", "lb", "vpn", "web"}},
+		{id: "lb", want: []string{"api", "web"}},
+		{id: "web"},
+		{id: "db"},
+		{id: "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			if got := graph.Dependents(tt.id); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Dependents(%s) = %v, attendu %v", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRootCause(t *testing.T) {
+	// Chaîne router ← fw ← web: chaque niveau reprend la cause de son parent
+	router := ParentState{Name: "router", Checked: true}
+	fw := ParentState{Name: "fw", Checked: true, RootCause: RootCause([]ParentState{router})}
+	if fw.RootCause != "router" {
+		t.Fatalf("cause racine de fw = %q, attendu router", fw.RootCause)
+	}
+
+	tests := []struct {
+		name    string
+		parents []ParentState
+		want    string
+	}{
+		{name: "sans parent"},
+		{name: "parents UP", parents: []ParentState{{Name: "fw", Checked: true, IsUp: true}}},
+		{name: "parent jamais vérifié", parents: []ParentState{{Name: "fw"}}},
+		{name: "parent DOWN", parents: []ParentState{router}, want: "router"},
+		{name: "grand-parent à l'origine de la panne", parents: []ParentState{fw}, want: "router"},
+		{
+			name:    "premier parent DOWN dans l'ordre déclaré",
+			parents: []ParentState{{Name: "lb", Checked: true, IsUp: true}, {Name: "vpn", Checked: true}, fw},
+			want:    "vpn",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RootCause(tt.parents); got != tt.want {
+				t.Errorf("RootCause = %q, attendu %q", got, tt.want)
+			}
+		})
+	}
+}
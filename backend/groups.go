@@ -0,0 +1,76 @@
+// Package backend - Groupes et tags des serveurs
+// Un groupe est un chemin hiérarchique ("prod/eu/web"): un serveur appartient
+// à son groupe et à tous ses ancêtres. Les tags sont des libellés libres,
+// comparés sans tenir compte de la casse
+package backend
+
+import (
+	"strings"
+)
+
+// GroupSeparator - Séparateur des niveaux d'un chemin de groupe
+const GroupSeparator = "/"
+
+// NormalizeGroup - Chemin de groupe sans espaces superflus ni niveaux vides
+// ("  prod / eu//web " → "prod/eu/web")
+func NormalizeGroup(group string) string {
+	var parts []string
+	for _, part := range strings.Split(group, GroupSeparator) {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, GroupSeparator)
+}
+
+// GroupAncestors - Le groupe et tous ses ancêtres, du plus général au plus précis
+// ("prod/eu/web" → ["prod", "prod/eu", "prod/eu/web"])
+func GroupAncestors(group string) []string {
+	if group == "" {
+		return nil
+	}
+	parts := strings.Split(group, GroupSeparator)
+	paths := make([]string, len(parts))
+	for i := range parts {
+		paths[i] = strings.Join(parts[:i+1], GroupSeparator)
+	}
+	return paths
+}
+
+// InGroup - Indique si le groupe d'un serveur est group ou l'un de ses sous-groupes
+func InGroup(serverGroup, group string) bool {
+	serverGroup, group = strings.ToLower(serverGroup), strings.ToLower(NormalizeGroup(group))
+	return group == "" || serverGroup == group || strings.HasPrefix(serverGroup, group+GroupSeparator)
+}
+
+// NormalizeTags - Tags sans espaces superflus, vides ni doublons (ordre conservé)
+func NormalizeTags(tags []string) []string {
+	var result []string
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[strings.ToLower(tag)] {
+			continue
+		}
+		seen[strings.ToLower(tag)] = true
+		result = append(result, tag)
+	}
+	return result
+}
+
+// HasTags - Indique si tags contient tous les tags demandés
+func HasTags(tags, wanted []string) bool {
+	for _, want := range wanted {
+		found := false
+		for _, tag := range tags {
+			if strings.EqualFold(tag, strings.TrimSpace(want)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,97 @@
+package backend
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeGroup(t *testing.T) {
+	tests := []struct {
+		group, want string
+	}{
+		{"", ""},
+		{"prod", "prod"},
+		{"  prod / eu//web ", "prod/eu/web"},
+		{"/prod/", "prod"},
+		{" / / ", ""},
+		{"Prod/EU", "Prod/EU"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeGroup(tt.group); got != tt.want {
+			t.Errorf("NormalizeGroup(%q) = %q, attendu %q", tt.group, got, tt.want)
+		}
+	}
+}
+
+func TestGroupAncestors(t *testing.T) {
+	tests := []struct {
+		group string
+		want  []string
+	}{
+		{"", nil},
+		{"prod", []string{"prod"}},
+		{"prod/eu/web", []string{"prod", "prod/eu", "prod/eu/web"}},
+	}
+	for _, tt := range tests {
+		if got := GroupAncestors(tt.group); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("GroupAncestors(%q) = %v, attendu %v", tt.group, got, tt.want)
+		}
+	}
+}
+
+func TestInGroup(t *testing.T) {
+	tests := []struct {
+		name        string
+		serverGroup string
+		group       string
+		want        bool
+	}{
+		{name: "même groupe", serverGroup: "prod", group: "prod", want: true},
+		{name: "sous-groupe", serverGroup: "prod/eu/web", group: "prod/eu", want: true},
+		{name: "préfixe sans séparateur", serverGroup: "production", group: "prod"},
+		{name: "préfixe d'un sous-groupe", serverGroup: "prod/europe", group: "prod/eu"},
+		{name: "groupe parent demandé par l'enfant", serverGroup: "prod", group: "prod/eu"},
+		{name: "casse différente", serverGroup: "Prod/EU/web", group: "prod/eu", want: true},
+		{name: "filtre à normaliser", serverGroup: "prod/eu/web", group: " prod / eu /", want: true},
+		{name: "filtre vide", serverGroup: "prod", group: "", want: true},
+		{name: "filtre vide, serveur sans groupe", serverGroup: "", group: "", want: true},
+		{name: "serveur sans groupe", serverGroup: "", group: "prod"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InGroup(tt.serverGroup, tt.group); got != tt.want {
+				t.Errorf("InGroup(%q, %q) = %v, attendu %v", tt.serverGroup, tt.group, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	got := NormalizeTags([]string{" critique ", "", "client:acme", "Critique", "  "})
+	if want := []string{"critique", "client:acme"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("NormalizeTags = %v, attendu %v", got, want)
+	}
+}
+
+func TestHasTags(t *testing.T) {
+	tags := []string{"critique", "client:acme"}
+	tests := []struct {
+		name   string
+		wanted []string
+		want   bool
+	}{
+		{name: "aucun tag demandé", want: true},
+		{name: "un tag", wanted: []string{"critique"}, want: true},
+		{name: "tous les tags", wanted: []string{"client:acme", "critique"}, want: true},
+		{name: "casse et espaces", wanted: []string{" CRITIQUE "}, want: true},
+		{name: "tag manquant", wanted: []string{"critique", "db"}},
+		{name: "préfixe d'un tag", wanted: []string{"client"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HasTags(tags, tt.wanted); got != tt.want {
+				t.Errorf("HasTags(%v) = %v, attendu %v", tt.wanted, got, tt.want)
+			}
+		})
+	}
+}
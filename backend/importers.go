@@ -0,0 +1,125 @@
+// Package backend - Import depuis d'autres outils de monitoring
+// Ce fichier définit le résultat commun des importeurs (Uptime Kuma, Nagios,
+// Prometheus blackbox_exporter): des serveurs traduits vers le type de check
+// le plus proche, et des avertissements pour ce qui n'a pas d'équivalent
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Formats d'import des autres outils
+const (
+	TransferUptimeKuma = "uptimekuma" // Sauvegarde JSON d'Uptime Kuma
+	TransferNagios     = "nagios"     // Définitions d'objets Nagios (host, service, command...)
+	TransferBlackbox   = "blackbox"   // prometheus.yml ou fichier file_sd de cibles blackbox_exporter
+)
+
+// ForeignServer - Serveur traduit depuis la configuration d'un autre outil
+type ForeignServer struct {
+	Line      int      // Ligne (Nagios, blackbox) ou rang (Uptime Kuma) dans le fichier source
+	Name      string   // Nom du serveur
+	URL       string   // URL (http), hôte:port (tcp) ou hôte (ping)
+	Type      string   // http, tcp, ping ou push
+	Interval  string   // Intervalle de vérification (ex: "1m"), vide = défaut
+	Timeout   string   // Timeout (ex: "10s"), vide = défaut
+	PushToken string   // Jeton des checks push repris de l'outil d'origine
+	Group     string   // Groupe (groupes Uptime Kuma)
+	Tags      []string // Tags (tags Uptime Kuma, hostgroups Nagios)
+	Paused    bool     // Moniteur désactivé dans l'outil d'origine
+	Parents   []string // Noms des serveurs importés dont celui-ci dépend (parents Nagios)
+}
+
+// ForeignImport - Résultat d'un importeur
+type ForeignImport struct {
+	Servers  []ForeignServer
+	Warnings []string // Éléments ignorés ou traduits approximativement
+	ByLine   bool     // Line est un numéro de ligne (sinon un rang)
+}
+
+// ForeignImporters - Importeurs par format
+var ForeignImporters = map[string]func(data []byte) (*ForeignImport, error){
+	TransferUptimeKuma: ParseUptimeKuma,
+	TransferNagios:     ParseNagios,
+	TransferBlackbox:   ParseBlackbox,
+}
+
+// nagiosDefineRe - Début d'une définition d'objet Nagios
+var nagiosDefineRe = regexp.MustCompile(`(?m)^\s*define\s+\w+\s*\{`)
+
+// detectForeignFormat - Format d'un autre outil reconnu dans un contenu, ou ""
+func detectForeignFormat(data []byte) string {
+	if nagiosDefineRe.Match(data) {
+		return TransferNagios
+	}
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil { // JSON est du YAML
+		return ""
+	}
+	switch v := doc.(type) {
+	case map[string]any:
+		if _, ok := v["monitorList"]; ok {
+			return TransferUptimeKuma
+		}
+		if _, ok := v["scrape_configs"]; ok {
+			return TransferBlackbox
+		}
+	case []any:
+		// file_sd: [{targets: [...], labels: {...}}]
+		if len(v) > 0 {
+			if group, ok := v[0].(map[string]any); ok {
+				if _, ok := group["targets"]; ok {
+					return TransferBlackbox
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// warn - Ajoute un avertissement localisé
+func (f *ForeignImport) warn(line int, format string, args ...any) {
+	position := "élément"
+	if f.ByLine {
+		position = "ligne"
+	}
+	f.Warnings = append(f.Warnings, fmt.Sprintf("%s %d: %s", position, line, fmt.Sprintf(format, args...)))
+}
+
+// formatSeconds - Durée en secondes au format des serveurs ("30s", "5m", "1h")
+func formatSeconds(seconds float64) string {
+	if seconds <= 0 {
+		return ""
+	}
+	d := time.Duration(seconds * float64(time.Second)).Round(time.Second)
+	switch {
+	case d == 0:
+		return "1s"
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+	return fmt.Sprintf("%ds", d/time.Second)
+}
+
+// hostPort - Adresse hôte:port (crochets pour IPv6)
+func hostPort(host string, port int) string {
+	return net.JoinHostPort(strings.Trim(host, "[]"), strconv.Itoa(port))
+}
+
+// decodeJSON - Décode un JSON avec des erreurs localisées par ligne
+func decodeJSON(data []byte, v any) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return LocateJSONError(data, err)
+	}
+	return nil
+}
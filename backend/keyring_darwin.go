@@ -0,0 +1,84 @@
+//go:build darwin
+
+// Package backend - Trousseau macOS
+// Ce fichier range les secrets comme mots de passe génériques du trousseau
+// de session via l'outil /usr/bin/security
+package backend
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// securityTool - Outil en ligne de commande du trousseau macOS
+const securityTool = "/usr/bin/security"
+
+// securityNotFound - Code de sortie de security pour un élément absent
+const securityNotFound = 44
+
+// keychainEncodingPrefix - Les valeurs sont encodées en base64 pour éviter
+// l'affichage hexadécimal de security pour les caractères non ASCII
+const keychainEncodingPrefix = "base64:"
+
+// keychainStore - Secrets rangés dans le trousseau macOS
+type keychainStore struct{}
+
+// newKeyringStore - Vérifie la présence de l'outil security
+func newKeyringStore() (SecretStore, error) {
+	if _, err := os.Stat(securityTool); err != nil {
+		return nil, fmt.Errorf("outil %s introuvable", securityTool)
+	}
+	return keychainStore{}, nil
+}
+
+// Get - Lit un mot de passe générique du trousseau
+func (keychainStore) Get(key string) (string, error) {
+	out, err := exec.Command(securityTool, "find-generic-password", "-s", secretServiceName, "-a", key, "-w").Output()
+	if err != nil {
+		if isSecurityNotFound(err) {
+			return "", ErrSecretNotFound
+		}
+		return "", fmt.Errorf("lecture du trousseau impossible: %s", err)
+	}
+	value := strings.TrimSuffix(string(out), "\n")
+	if encoded, ok := strings.CutPrefix(value, keychainEncodingPrefix); ok {
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return "", fmt.Errorf("secret du trousseau illisible: %s", err)
+		}
+		value = string(decoded)
+	}
+	return value, nil
+}
+
+// Set - Crée ou remplace un mot de passe générique
+// La commande passe par l'entrée standard pour ne pas exposer le secret
+// dans la liste des processus
+func (keychainStore) Set(key, value string) error {
+	encoded := keychainEncodingPrefix + base64.StdEncoding.EncodeToString([]byte(value))
+	cmd := exec.Command(securityTool, "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n", secretServiceName, key, encoded))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("enregistrement dans le trousseau impossible: %s (%s)", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Delete - Supprime un mot de passe générique (sans erreur s'il est absent)
+func (keychainStore) Delete(key string) error {
+	err := exec.Command(securityTool, "delete-generic-password", "-s", secretServiceName, "-a", key).Run()
+	if err != nil && !isSecurityNotFound(err) {
+		return fmt.Errorf("suppression du secret impossible: %s", err)
+	}
+	return nil
+}
+
+// isSecurityNotFound - Indique si security a signalé un élément absent
+func isSecurityNotFound(err error) bool {
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr) && exitErr.ExitCode() == securityNotFound
+}
@@ -0,0 +1,207 @@
+//go:build linux
+
+// Package backend - Trousseau Linux (API freedesktop Secret Service)
+// Ce fichier range les secrets dans la collection par défaut du Secret
+// Service (GNOME Keyring, KWallet) via le bus D-Bus de session
+package backend
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Noms D-Bus de l'API Secret Service
+const (
+	secretServiceBus        = "org.freedesktop.secrets"
+	secretServicePath       = "/org/freedesktop/secrets"
+	secretServiceIface      = "org.freedesktop.Secret.Service"
+	secretCollectionIface   = "org.freedesktop.Secret.Collection"
+	secretItemIface         = "org.freedesktop.Secret.Item"
+	secretPromptIface       = "org.freedesktop.Secret.Prompt"
+	secretDefaultCollection = dbus.ObjectPath("/org/freedesktop/secrets/aliases/default")
+)
+
+// secretPromptTimeout - Délai laissé à l'utilisateur pour déverrouiller le trousseau
+const secretPromptTimeout = 2 * time.Minute
+
+// dbusSecret - Secret au format de l'API Secret Service (oayays)
+type dbusSecret struct {
+	Session     dbus.ObjectPath
+	Parameters  []byte
+	Value       []byte
+	ContentType string
+}
+
+// secretServiceStore - Secrets rangés dans le Secret Service
+type secretServiceStore struct {
+	conn    *dbus.Conn
+	session dbus.ObjectPath
+}
+
+// newKeyringStore - Ouvre une session Secret Service sur le bus de session
+func newKeyringStore() (SecretStore, error) {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("bus D-Bus de session inaccessible: %s", err)
+	}
+
+	var output dbus.Variant
+	var session dbus.ObjectPath
+	err = conn.Object(secretServiceBus, secretServicePath).
+		Call(secretServiceIface+".OpenSession", 0, "plain", dbus.MakeVariant("")).
+		Store(&output, &session)
+	if err != nil {
+		return nil, fmt.Errorf("Secret Service indisponible: %s", err)
+	}
+
+	// La collection par défaut doit exister pour y ranger les secrets
+	collection := conn.Object(secretServiceBus, secretDefaultCollection)
+	if _, err := collection.GetProperty(secretCollectionIface + ".Label"); err != nil {
+		return nil, fmt.Errorf("aucune collection par défaut dans le Secret Service: %s", err)
+	}
+	return &secretServiceStore{conn: conn, session: session}, nil
+}
+
+// Get - Lit un secret de la collection par défaut
+func (s *secretServiceStore) Get(key string) (string, error) {
+	items, err := s.search(key)
+	if err != nil {
+		return "", err
+	}
+	if len(items) == 0 {
+		return "", ErrSecretNotFound
+	}
+	if err := s.unlock(items[0]); err != nil {
+		return "", err
+	}
+
+	var secret dbusSecret
+	err = s.conn.Object(secretServiceBus, items[0]).
+		Call(secretItemIface+".GetSecret", 0, s.session).
+		Store(&secret)
+	if err != nil {
+		return "", fmt.Errorf("lecture du secret impossible: %s", err)
+	}
+	return string(secret.Value), nil
+}
+
+// Set - Crée ou remplace un secret dans la collection par défaut
+func (s *secretServiceStore) Set(key, value string) error {
+	if err := s.unlock(secretDefaultCollection); err != nil {
+		return err
+	}
+
+	properties := map[string]dbus.Variant{
+		secretItemIface + ".Label":      dbus.MakeVariant(secretServiceName + " - " + key),
+		secretItemIface + ".Attributes": dbus.MakeVariant(s.attributes(key)),
+	}
+	secret := dbusSecret{
+		Session:     s.session,
+		Parameters:  []byte{},
+		Value:       []byte(value),
+		ContentType: "text/plain; charset=utf8",
+	}
+
+	var item, prompt dbus.ObjectPath
+	err := s.conn.Object(secretServiceBus, secretDefaultCollection).
+		Call(secretCollectionIface+".CreateItem", 0, properties, secret, true).
+		Store(&item, &prompt)
+	if err != nil {
+		return fmt.Errorf("enregistrement dans le trousseau impossible: %s", err)
+	}
+	return s.prompt(prompt)
+}
+
+// Delete - Supprime un secret (sans erreur s'il est absent)
+func (s *secretServiceStore) Delete(key string) error {
+	items, err := s.search(key)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		var prompt dbus.ObjectPath
+		err := s.conn.Object(secretServiceBus, item).
+			Call(secretItemIface+".Delete", 0).
+			Store(&prompt)
+		if err != nil {
+			return fmt.Errorf("suppression du secret impossible: %s", err)
+		}
+		if err := s.prompt(prompt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attributes - Attributs de recherche d'un secret (compatibles secret-tool)
+func (s *secretServiceStore) attributes(key string) map[string]string {
+	return map[string]string{"service": secretServiceName, "username": key}
+}
+
+// search - Éléments de la collection par défaut correspondant à une clé
+func (s *secretServiceStore) search(key string) ([]dbus.ObjectPath, error) {
+	var items []dbus.ObjectPath
+	err := s.conn.Object(secretServiceBus, secretDefaultCollection).
+		Call(secretCollectionIface+".SearchItems", 0, s.attributes(key)).
+		Store(&items)
+	if err != nil {
+		return nil, fmt.Errorf("recherche dans le trousseau impossible: %s", err)
+	}
+	return items, nil
+}
+
+// unlock - Déverrouille un objet du trousseau (peut afficher une invite)
+func (s *secretServiceStore) unlock(path dbus.ObjectPath) error {
+	var unlocked []dbus.ObjectPath
+	var prompt dbus.ObjectPath
+	err := s.conn.Object(secretServiceBus, secretServicePath).
+		Call(secretServiceIface+".Unlock", 0, []dbus.ObjectPath{path}).
+		Store(&unlocked, &prompt)
+	if err != nil {
+		return fmt.Errorf("déverrouillage du trousseau impossible: %s", err)
+	}
+	return s.prompt(prompt)
+}
+
+// prompt - Affiche l'invite du Secret Service et attend la réponse
+func (s *secretServiceStore) prompt(path dbus.ObjectPath) error {
+	if path == "/" || path == "" {
+		return nil // Aucune invite nécessaire
+	}
+
+	match := []dbus.MatchOption{
+		dbus.WithMatchObjectPath(path),
+		dbus.WithMatchInterface(secretPromptIface),
+		dbus.WithMatchMember("Completed"),
+	}
+	if err := s.conn.AddMatchSignal(match...); err != nil {
+		return err
+	}
+	defer s.conn.RemoveMatchSignal(match...)
+
+	signals := make(chan *dbus.Signal, 4)
+	s.conn.Signal(signals)
+	defer s.conn.RemoveSignal(signals)
+
+	if err := s.conn.Object(secretServiceBus, path).Call(secretPromptIface+".Prompt", 0, "").Err; err != nil {
+		return fmt.Errorf("invite du trousseau impossible: %s", err)
+	}
+
+	timeout := time.After(secretPromptTimeout)
+	for {
+		select {
+		case signal := <-signals:
+			if signal.Path != path || signal.Name != secretPromptIface+".Completed" {
+				continue
+			}
+			if len(signal.Body) > 0 && signal.Body[0] == true {
+				return fmt.Errorf("déverrouillage du trousseau annulé")
+			}
+			return nil
+		case <-timeout:
+			return fmt.Errorf("déverrouillage du trousseau: délai dépassé")
+		}
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !linux && !darwin && !windows
+
+// Package backend - Trousseau absent sur les autres systèmes
+// Les secrets sont alors rangés dans le fichier chiffré
+package backend
+
+import "fmt"
+
+// newKeyringStore - Aucun trousseau supporté sur ce système
+func newKeyringStore() (SecretStore, error) {
+	return nil, fmt.Errorf("aucun trousseau supporté sur ce système")
+}
@@ -0,0 +1,123 @@
+//go:build windows
+
+// Package backend - Trousseau Windows (Gestionnaire d'identification)
+// Ce fichier range les secrets comme identifiants génériques via advapi32
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// Fonctions du Gestionnaire d'identification
+var (
+	advapi32        = syscall.NewLazyDLL("advapi32.dll")
+	procCredReadW   = advapi32.NewProc("CredReadW")
+	procCredWriteW  = advapi32.NewProc("CredWriteW")
+	procCredDeleteW = advapi32.NewProc("CredDeleteW")
+	procCredFree    = advapi32.NewProc("CredFree")
+)
+
+// Constantes de l'API CREDENTIAL
+const (
+	credTypeGeneric         = 1
+	credPersistLocalMachine = 2
+	errorNotFound           = syscall.Errno(1168)
+)
+
+// winCredential - Structure CREDENTIALW
+type winCredential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// credentialStore - Secrets rangés dans le Gestionnaire d'identification
+type credentialStore struct{}
+
+// newKeyringStore - Vérifie la disponibilité d'advapi32
+func newKeyringStore() (SecretStore, error) {
+	if err := procCredReadW.Find(); err != nil {
+		return nil, fmt.Errorf("Gestionnaire d'identification indisponible: %s", err)
+	}
+	return credentialStore{}, nil
+}
+
+// credentialTarget - Nom de l'identifiant générique d'une clé
+func credentialTarget(key string) (*uint16, error) {
+	return syscall.UTF16PtrFromString(secretServiceName + ":" + key)
+}
+
+// Get - Lit un identifiant générique
+func (credentialStore) Get(key string) (string, error) {
+	target, err := credentialTarget(key)
+	if err != nil {
+		return "", err
+	}
+	var cred *winCredential
+	ret, _, err := procCredReadW.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred)))
+	if ret == 0 {
+		if errors.Is(err, errorNotFound) {
+			return "", ErrSecretNotFound
+		}
+		return "", fmt.Errorf("lecture du Gestionnaire d'identification impossible: %s", err)
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred)))
+
+	if cred.CredentialBlobSize == 0 {
+		return "", nil
+	}
+	return string(unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize)), nil
+}
+
+// Set - Crée ou remplace un identifiant générique
+func (credentialStore) Set(key, value string) error {
+	target, err := credentialTarget(key)
+	if err != nil {
+		return err
+	}
+	user, err := syscall.UTF16PtrFromString(key)
+	if err != nil {
+		return err
+	}
+	blob := []byte(value)
+	cred := winCredential{
+		Type:               credTypeGeneric,
+		TargetName:         target,
+		CredentialBlobSize: uint32(len(blob)),
+		Persist:            credPersistLocalMachine,
+		UserName:           user,
+	}
+	if len(blob) > 0 {
+		cred.CredentialBlob = &blob[0]
+	}
+	ret, _, err := procCredWriteW.Call(uintptr(unsafe.Pointer(&cred)), 0)
+	if ret == 0 {
+		return fmt.Errorf("enregistrement dans le Gestionnaire d'identification impossible: %s", err)
+	}
+	return nil
+}
+
+// Delete - Supprime un identifiant générique (sans erreur s'il est absent)
+func (credentialStore) Delete(key string) error {
+	target, err := credentialTarget(key)
+	if err != nil {
+		return err
+	}
+	ret, _, err := procCredDeleteW.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0)
+	if ret == 0 && !errors.Is(err, errorNotFound) {
+		return fmt.Errorf("suppression du secret impossible: %s", err)
+	}
+	return nil
+}
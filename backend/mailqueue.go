@@ -0,0 +1,326 @@
+// Package backend - File d'attente des emails sortants
+// Ce fichier gère la file persistante des emails à relayer vers le serveur
+// SMTP externe, avec nouvelles tentatives (backoff exponentiel) et
+// suivi des emails abandonnés (dead-letter)
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Statuts possibles d'un email en file d'attente
+const (
+	QueueStatusPending = "pending" // En attente d'envoi ou de nouvelle tentative
+	QueueStatusDead    = "dead"    // Abandonné après trop d'échecs
+)
+
+// QueuedEmail - Email en attente d'envoi vers le serveur SMTP externe
+type QueuedEmail struct {
+	ID          string            `json:"id"`                    // Identifiant unique
+	From        string            `json:"from"`                  // Adresse de l'expéditeur
+	To          []string          `json:"to"`                    // Liste des destinataires
+	Cc          []string          `json:"cc,omitempty"`          // Destinataires en copie
+	Bcc         []string          `json:"bcc,omitempty"`         // Destinataires en copie cachée
+	ReplyTo     []string          `json:"reply_to,omitempty"`    // Adresses de réponse
+	Subject     string            `json:"subject"`               // Sujet de l'email
+	Body        string            `json:"body"`                  // Corps texte brut
+	HTML        string            `json:"html,omitempty"`        // Corps HTML (optionnel)
+	Attachments []EmailAttachment `json:"attachments,omitempty"` // Pièces jointes et images intégrées
+	Status      string            `json:"status"`                // pending | dead
+	Attempts    int               `json:"attempts"`              // Nombre de tentatives effectuées
+	LastError   string            `json:"last_error,omitempty"`  // Dernière erreur d'envoi
+	CreatedAt   time.Time         `json:"created_at"`            // Date de mise en file
+	NextAttempt time.Time         `json:"next_attempt"`          // Date de la prochaine tentative
+}
+
+// MailQueue - File d'attente persistante des emails sortants
+// Les emails envoyés avec succès sont retirés de la file
+type MailQueue struct {
+	path        string                  // Fichier de persistance
+	send        func(QueuedEmail) error // Fonction d'envoi réel
+	items       []QueuedEmail           // Emails en attente ou abandonnés
+	mutex       sync.Mutex              // Mutex pour accès concurrent
+	seq         uint64                  // Compteur des identifiants attribués
+	wake        chan struct{}           // Réveil du worker (nouvel email, relance)
+	stop        chan struct{}           // Arrêt du worker
+	stopOnce    sync.Once               // Arrêt du worker une seule fois
+	Ready       func() error            // Refuse la mise en file si l'envoi est impossible (nil = toujours accepté)
+	MaxAttempts int                     // Tentatives avant abandon
+	BaseDelay   time.Duration           // Délai après le premier échec
+	MaxDelay    time.Duration           // Délai maximum entre deux tentatives
+}
+
+// mailQueueFilePath - Chemin du fichier de persistance de la file
+func mailQueueFilePath() (string, error) {
+	return ConfigFilePath("mail_queue.json")
+}
+
+// NewMailQueue - Constructeur de la file d'attente
+// send est appelée par le worker pour chaque tentative d'envoi
+func NewMailQueue(send func(QueuedEmail) error) *MailQueue {
+	path, err := mailQueueFilePath()
+	if err != nil {
+		log.Printf("⚠️ Chemin de la file d'emails indisponible: %s", err)
+	}
+	return newMailQueue(path, send)
+}
+
+// newMailQueue - File d'attente persistée dans path
+func newMailQueue(path string, send func(QueuedEmail) error) *MailQueue {
+	return &MailQueue{
+		path:        path,
+		send:        send,
+		wake:        make(chan struct{}, 1),
+		stop:        make(chan struct{}),
+		MaxAttempts: 8,
+		BaseDelay:   30 * time.Second,
+		MaxDelay:    30 * time.Minute,
+	}
+}
+
+// Start - Charge la file depuis le disque et démarre le worker d'envoi
+func (q *MailQueue) Start() error {
+	err := q.load()
+	go q.run()
+	return err
+}
+
+// Stop - Arrête le worker et sauvegarde la file (sans effet sur le worker
+// s'il est déjà arrêté)
+func (q *MailQueue) Stop() error {
+	q.stopOnce.Do(func() { close(q.stop) })
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.save()
+}
+
+// Enqueue - Ajoute un email à la file et déclenche un envoi immédiat
+// Refusé (sans mise en file) si Ready signale que l'envoi est impossible:
+// l'email épuiserait ses tentatives avant d'être abandonné
+func (q *MailQueue) Enqueue(email QueuedEmail) (QueuedEmail, error) {
+	if q.Ready != nil {
+		if err := q.Ready(); err != nil {
+			return email, err
+		}
+	}
+
+	now := time.Now()
+	email.Status = QueueStatusPending
+	email.CreatedAt = now
+	email.NextAttempt = now
+
+	q.mutex.Lock()
+	if email.ID == "" {
+		// Horodatage + compteur: unique même pour deux emails du même instant
+		q.seq++
+		email.ID = fmt.Sprintf("%d-%d", now.UnixNano(), q.seq)
+	}
+	q.items = append(q.items, email)
+	if err := q.save(); err != nil {
+		log.Printf("⚠️ Sauvegarde de la file d'emails échouée: %s", err)
+	}
+	q.mutex.Unlock()
+
+	q.notify()
+	return email, nil
+}
+
+// List - Copie des emails en file (les plus anciens en premier)
+func (q *MailQueue) List() []QueuedEmail {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	items := make([]QueuedEmail, len(q.items))
+	copy(items, q.items)
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].CreatedAt.Before(items[j].CreatedAt)
+	})
+	return items
+}
+
+// Retry - Replanifie immédiatement un email (en attente ou abandonné)
+// Le compteur de tentatives est remis à zéro
+func (q *MailQueue) Retry(id string) error {
+	q.mutex.Lock()
+	index := q.indexOf(id)
+	if index < 0 {
+		q.mutex.Unlock()
+		return fmt.Errorf("email %s introuvable dans la file", id)
+	}
+	q.items[index].Status = QueueStatusPending
+	q.items[index].Attempts = 0
+	q.items[index].NextAttempt = time.Now()
+	err := q.save()
+	q.mutex.Unlock()
+
+	q.notify()
+	return err
+}
+
+// Delete - Retire définitivement un email de la file
+func (q *MailQueue) Delete(id string) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	index := q.indexOf(id)
+	if index < 0 {
+		return fmt.Errorf("email %s introuvable dans la file", id)
+	}
+	q.items = append(q.items[:index], q.items[index+1:]...)
+	return q.save()
+}
+
+// run - Boucle du worker: envoie les emails arrivés à échéance
+func (q *MailQueue) run() {
+	for {
+		q.processDue()
+
+		timer := time.NewTimer(q.nextWait())
+		select {
+		case <-timer.C:
+		case <-q.wake:
+			timer.Stop()
+		case <-q.stop:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// processDue - Tente l'envoi de chaque email arrivé à échéance
+func (q *MailQueue) processDue() {
+	for {
+		q.mutex.Lock()
+		var email QueuedEmail
+		found := false
+		now := time.Now()
+		for _, item := range q.items {
+			if item.Status == QueueStatusPending && !item.NextAttempt.After(now) {
+				email = item
+				found = true
+				break
+			}
+		}
+		q.mutex.Unlock()
+
+		if !found {
+			return
+		}
+
+		// Envoi hors verrou (peut prendre plusieurs secondes)
+		err := q.send(email)
+
+		q.mutex.Lock()
+		index := q.indexOf(email.ID)
+		if index >= 0 {
+			if err == nil {
+				q.items = append(q.items[:index], q.items[index+1:]...)
+				log.Printf("✅ Email %s envoyé, retiré de la file", email.ID)
+			} else {
+				item := &q.items[index]
+				item.Attempts++
+				item.LastError = err.Error()
+				if item.Attempts >= q.MaxAttempts {
+					item.Status = QueueStatusDead
+					log.Printf("☠️ Email %s abandonné après %d tentatives: %s", item.ID, item.Attempts, err)
+				} else {
+					item.NextAttempt = time.Now().Add(q.backoff(item.Attempts))
+					log.Printf("🔁 Échec d'envoi de l'email %s (tentative %d), nouvel essai à %s: %s",
+						item.ID, item.Attempts, item.NextAttempt.Format("15:04:05"), err)
+				}
+			}
+			if err := q.save(); err != nil {
+				log.Printf("⚠️ Sauvegarde de la file d'emails échouée: %s", err)
+			}
+		}
+		q.mutex.Unlock()
+	}
+}
+
+// backoff - Délai avant la prochaine tentative (exponentiel, plafonné)
+func (q *MailQueue) backoff(attempts int) time.Duration {
+	delay := q.BaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= q.MaxDelay {
+			return q.MaxDelay
+		}
+	}
+	return delay
+}
+
+// nextWait - Durée avant la prochaine échéance de la file
+func (q *MailQueue) nextWait() time.Duration {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	wait := q.MaxDelay
+	now := time.Now()
+	for _, item := range q.items {
+		if item.Status != QueueStatusPending {
+			continue
+		}
+		if d := item.NextAttempt.Sub(now); d < wait {
+			wait = d
+		}
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	return wait
+}
+
+// notify - Réveille le worker sans bloquer
+func (q *MailQueue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// indexOf - Position d'un email dans la file (appelant verrouillé)
+func (q *MailQueue) indexOf(id string) int {
+	for i, item := range q.items {
+		if item.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// load - Lit la file depuis le disque
+func (q *MailQueue) load() error {
+	data, err := os.ReadFile(q.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // Pas encore de file, c'est normal
+		}
+		return err
+	}
+
+	var items []QueuedEmail
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	q.items = items
+	q.mutex.Unlock()
+	return nil
+}
+
+// save - Écrit la file sur le disque (appelant verrouillé)
+func (q *MailQueue) save() error {
+	data, err := json.MarshalIndent(q.items, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Mode 0600: la file contient le contenu des alertes et les destinataires
+	return WriteFileAtomic(q.path, data, 0o600)
+}
@@ -0,0 +1,182 @@
+package backend
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestMailQueue - File persistée dans un dossier temporaire, délais courts
+func newTestMailQueue(t *testing.T, send func(QueuedEmail) error) *MailQueue {
+	t.Helper()
+	q := newMailQueue(filepath.Join(t.TempDir(), "mail_queue.json"), send)
+	q.MaxAttempts = 3
+	q.BaseDelay = time.Millisecond
+	q.MaxDelay = 5 * time.Millisecond
+	return q
+}
+
+// waitFor - Attend qu'une condition sur la file soit remplie
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("délai dépassé: %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestMailQueueEnqueueIDs(t *testing.T) {
+	q := newTestMailQueue(t, func(QueuedEmail) error { return nil })
+
+	seen := make(map[string]bool)
+	for i := 0; i < 20; i++ {
+		email, err := q.Enqueue(QueuedEmail{To: []string{"ops@example.com"}, Subject: "alerte"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[email.ID] {
+			t.Fatalf("identifiant %s attribué deux fois", email.ID)
+		}
+		seen[email.ID] = true
+		if email.Status != QueueStatusPending {
+			t.Errorf("statut %q, attendu %q", email.Status, QueueStatusPending)
+		}
+	}
+
+	// La file est relue depuis le disque au démarrage suivant
+	reloaded := newMailQueue(q.path, nil)
+	if err := reloaded.load(); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(reloaded.List()); got != 20 {
+		t.Errorf("%d emails relus, attendu 20", got)
+	}
+}
+
+func TestMailQueueReady(t *testing.T) {
+	q := newTestMailQueue(t, func(QueuedEmail) error { return nil })
+	q.Ready = func() error { return ErrSMTPNotConfigured }
+
+	if _, err := q.Enqueue(QueuedEmail{To: []string{"ops@example.com"}}); !errors.Is(err, ErrSMTPNotConfigured) {
+		t.Fatalf("Enqueue = %v, attendu ErrSMTPNotConfigured", err)
+	}
+	if len(q.List()) != 0 {
+		t.Error("email refusé placé dans la file")
+	}
+}
+
+func TestMailQueueDelivery(t *testing.T) {
+	var (
+		mutex sync.Mutex
+		sent  []string
+	)
+	q := newTestMailQueue(t, func(email QueuedEmail) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		sent = append(sent, email.Subject)
+		return nil
+	})
+	if err := q.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer q.Stop()
+
+	if _, err := q.Enqueue(QueuedEmail{To: []string{"ops@example.com"}, Subject: "DOWN"}); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "email envoyé et retiré de la file", func() bool { return len(q.List()) == 0 })
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(sent) != 1 || sent[0] != "DOWN" {
+		t.Errorf("emails envoyés: %v", sent)
+	}
+}
+
+func TestMailQueueDeadLetterAndRetry(t *testing.T) {
+	var (
+		mutex    sync.Mutex
+		attempts int
+		fail     = true
+	)
+	q := newTestMailQueue(t, func(QueuedEmail) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		attempts++
+		if fail {
+			return errors.New("relais indisponible")
+		}
+		return nil
+	})
+	if err := q.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer q.Stop()
+
+	email, err := q.Enqueue(QueuedEmail{To: []string{"ops@example.com"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "email abandonné", func() bool {
+		items := q.List()
+		return len(items) == 1 && items[0].Status == QueueStatusDead
+	})
+	dead := q.List()[0]
+	if dead.Attempts != q.MaxAttempts || dead.LastError != "relais indisponible" {
+		t.Errorf("email abandonné: %d tentatives, erreur %q", dead.Attempts, dead.LastError)
+	}
+
+	// Relance manuelle: le compteur repart de zéro et l'envoi réussit
+	mutex.Lock()
+	fail = false
+	mutex.Unlock()
+	if err := q.Retry(email.ID); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "email relancé envoyé", func() bool { return len(q.List()) == 0 })
+
+	if err := q.Retry(email.ID); err == nil {
+		t.Error("relance d'un email absent acceptée")
+	}
+	if err := q.Delete(email.ID); err == nil {
+		t.Error("suppression d'un email absent acceptée")
+	}
+}
+
+func TestMailQueueStopTwice(t *testing.T) {
+	q := newTestMailQueue(t, func(QueuedEmail) error { return nil })
+	if err := q.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Stop(); err != nil {
+		t.Fatalf("second arrêt: %v", err)
+	}
+}
+
+func TestMailQueueBackoff(t *testing.T) {
+	q := newMailQueue("", nil)
+	q.BaseDelay, q.MaxDelay = 30*time.Second, 5*time.Minute
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{4, 4 * time.Minute},
+		{5, 5 * time.Minute},
+		{20, 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := q.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %s, attendu %s", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,473 @@
+// Package backend - Fenêtres de maintenance planifiées
+// Pendant une fenêtre, les serveurs concernés sont toujours vérifiés et leur
+// historique enregistré, mais les incidents sont marqués "maintenance" et le
+// NotificationManager n'envoie aucune alerte. Une fenêtre est ponctuelle
+// (début/fin), récurrente par expression cron (début + durée) ou récurrente
+// par plages horaires hebdomadaires, dans le fuseau horaire choisi
+package backend
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "time/tzdata" // Fuseaux IANA embarqués (Windows n'en fournit pas)
+)
+
+// MaintenanceTimeLayout - Format des dates des fenêtres ponctuelles
+const MaintenanceTimeLayout = "2006-01-02 15:04"
+
+// maxMaintenanceDuration - Durée maximale d'une fenêtre récurrente
+const maxMaintenanceDuration = 7 * 24 * time.Hour
+
+// MaintenanceWindow - Fenêtre de maintenance (un seul mode de planification)
+// Sans serveur ni groupe, la fenêtre s'applique à tous les serveurs
+type MaintenanceWindow struct {
+	Name     string   `json:"name"`               // Nom affiché (ex: "Déploiement hebdomadaire")
+	Servers  []string `json:"servers,omitempty"`  // Identifiants des serveurs concernés
+	Groups   []string `json:"groups,omitempty"`   // Groupes concernés, sous-groupes compris
+	Timezone string   `json:"timezone,omitempty"` // Fuseau IANA (ex: "Europe/Paris"), vide = fuseau local
+
+	// Fenêtre ponctuelle
+	Start string `json:"start,omitempty"` // Début (ex: "2026-10-20 22:00")
+	End   string `json:"end,omitempty"`   // Fin
+
+	// Fenêtre récurrente: début en expression cron et durée...
+	Cron     string `json:"cron,omitempty"`     // Début, cron à 5 champs (ex: "0 2 * * sun")
+	Duration string `json:"duration,omitempty"` // Durée (ex: "2h")
+
+	// ...ou plages horaires sur certains jours de la semaine
+	Days []string `json:"days,omitempty"` // Jours ("mon" à "sun"), vide = tous les jours
+	From string   `json:"from,omitempty"` // Début (ex: "22:00")
+	To   string   `json:"to,omitempty"`   // Fin, le lendemain si elle précède le début (ex: "02:00")
+}
+
+// MaintenanceWindowStatus - Fenêtre avec son état à un instant donné
+type MaintenanceWindowStatus struct {
+	Window MaintenanceWindow `json:"window"`
+	Active bool              `json:"active"`         // Fenêtre en cours
+	Next   *time.Time        `json:"next,omitempty"` // Prochain début (dans l'année), nil si aucun
+}
+
+// maintenanceError - Erreur sur un champ d'une fenêtre
+type maintenanceError struct {
+	field   string
+	message string
+}
+
+func (e *maintenanceError) Error() string {
+	return e.message
+}
+
+// maintenanceRule - Fenêtre compilée, prête à être évaluée
+type maintenanceRule struct {
+	window   MaintenanceWindow
+	location *time.Location
+	start    time.Time     // Fenêtre ponctuelle (zéro pour les fenêtres récurrentes)
+	end      time.Time     // Fenêtre ponctuelle
+	cron     *cronSchedule // Fenêtre cron
+	days     [7]bool       // Plages hebdomadaires (indexé par time.Weekday)
+	from     int           // Plages hebdomadaires: minute du jour du début
+	duration time.Duration // Fenêtres récurrentes
+}
+
+// weekdays - Jours acceptés dans les plages hebdomadaires
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// compile - Vérifie et prépare une fenêtre
+func (w MaintenanceWindow) compile() (*maintenanceRule, error) {
+	if strings.TrimSpace(w.Name) == "" {
+		return nil, &maintenanceError{"name", "nom de la fenêtre de maintenance requis"}
+	}
+	rule := &maintenanceRule{window: w, location: time.Local}
+	if w.Timezone != "" {
+		location, err := time.LoadLocation(w.Timezone)
+		if err != nil {
+			return nil, &maintenanceError{"timezone", fmt.Sprintf("%s: fuseau horaire inconnu: %s", w.Name, w.Timezone)}
+		}
+		rule.location = location
+	}
+
+	oneOff, cron, weekly := w.Start != "" || w.End != "", w.Cron != "", w.From != "" || w.To != "" || len(w.Days) > 0
+	switch {
+	case !oneOff && !cron && !weekly:
+		return nil, &maintenanceError{"start", fmt.Sprintf("%s: début/fin, expression cron ou plage horaire requis", w.Name)}
+	case oneOff && (cron || weekly) || cron && weekly:
+		return nil, &maintenanceError{"cron", fmt.Sprintf("%s: une seule planification par fenêtre (ponctuelle, cron ou plage horaire)", w.Name)}
+
+	case oneOff:
+		var err error
+		if rule.start, err = time.ParseInLocation(MaintenanceTimeLayout, strings.TrimSpace(w.Start), rule.location); err != nil {
+			return nil, &maintenanceError{"start", fmt.Sprintf("%s: début invalide (AAAA-MM-JJ HH:MM attendu): %s", w.Name, w.Start)}
+		}
+		if rule.end, err = time.ParseInLocation(MaintenanceTimeLayout, strings.TrimSpace(w.End), rule.location); err != nil {
+			return nil, &maintenanceError{"end", fmt.Sprintf("%s: fin invalide (AAAA-MM-JJ HH:MM attendu): %s", w.Name, w.End)}
+		}
+		if !rule.end.After(rule.start) {
+			return nil, &maintenanceError{"end", fmt.Sprintf("%s: la fin doit suivre le début", w.Name)}
+		}
+
+	case cron:
+		schedule, err := parseCron(w.Cron)
+		if err != nil {
+			return nil, &maintenanceError{"cron", fmt.Sprintf("%s: expression cron invalide: %s", w.Name, err)}
+		}
+		rule.cron = schedule
+		duration, err := time.ParseDuration(strings.TrimSpace(w.Duration))
+		if err != nil || duration <= 0 || duration > maxMaintenanceDuration {
+			return nil, &maintenanceError{"duration", fmt.Sprintf("%s: durée invalide (ex: 2h, 7 jours au plus): %s", w.Name, w.Duration)}
+		}
+		rule.duration = duration
+
+	case weekly:
+		from, ok := parseClock(w.From)
+		if !ok {
+			return nil, &maintenanceError{"from", fmt.Sprintf("%s: heure de début invalide (HH:MM attendu): %s", w.Name, w.From)}
+		}
+		to, ok := parseClock(w.To)
+		if !ok {
+			return nil, &maintenanceError{"to", fmt.Sprintf("%s: heure de fin invalide (HH:MM attendu): %s", w.Name, w.To)}
+		}
+		if to == from {
+			return nil, &maintenanceError{"to", fmt.Sprintf("%s: la fin doit différer du début", w.Name)}
+		}
+		if to < from {
+			to += 24 * 60 // Plage passant minuit
+		}
+		rule.from, rule.duration = from, time.Duration(to-from)*time.Minute
+		for i, day := range w.Days {
+			weekday, ok := parseWeekday(day)
+			if !ok {
+				return nil, &maintenanceError{fmt.Sprintf("days[%d]", i), fmt.Sprintf("%s: jour inconnu: %s (mon à sun)", w.Name, day)}
+			}
+			rule.days[weekday] = true
+		}
+		if len(w.Days) == 0 {
+			rule.days = [7]bool{true, true, true, true, true, true, true}
+		}
+	}
+	return rule, nil
+}
+
+// parseWeekday - Jour de la semaine en abrégé ou en toutes lettres ("mon", "Monday")
+func parseWeekday(value string) (time.Weekday, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for name, weekday := range weekdays {
+		if value == name || value == strings.ToLower(weekday.String()) {
+			return weekday, true
+		}
+	}
+	return 0, false
+}
+
+// parseClock - Minute du jour d'une heure "HH:MM"
+func parseClock(value string) (int, bool) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+// startsOn - Minutes du jour (croissantes) où une occurrence de la fenêtre
+// récurrente commence, pour le jour de date
+func (r *maintenanceRule) startsOn(date time.Time) []int {
+	if r.cron == nil {
+		if r.days[date.Weekday()] {
+			return []int{r.from}
+		}
+		return nil
+	}
+	if !r.cron.dayMatches(date) {
+		return nil
+	}
+	var starts []int
+	for hour, ok := range r.cron.hours {
+		if !ok {
+			continue
+		}
+		for minute, ok := range r.cron.minutes {
+			if ok {
+				starts = append(starts, hour*60+minute)
+			}
+		}
+	}
+	return starts
+}
+
+// occurrences - Instants d'un début (jour, minute du jour), dans l'ordre:
+// aucun si l'heure n'existe pas ce jour-là (passage à l'heure d'été), deux si
+// elle se répète (retour à l'heure d'hiver)
+func (r *maintenanceRule) occurrences(year int, month time.Month, day, minute int) []time.Time {
+	s := time.Date(year, month, day, minute/60, minute%60, 0, 0, r.location)
+	if s.Hour()*60+s.Minute() != minute {
+		return nil
+	}
+	for _, shift := range []time.Duration{time.Hour, 30 * time.Minute} {
+		if earlier := s.Add(-shift); earlier.Hour() == s.Hour() && earlier.Minute() == s.Minute() {
+			return []time.Time{earlier, s}
+		}
+		if later := s.Add(shift); later.Hour() == s.Hour() && later.Minute() == s.Minute() {
+			return []time.Time{s, later}
+		}
+	}
+	return []time.Time{s}
+}
+
+// lastStart - Dernier début d'occurrence avant t (inclus), parmi ceux qui
+// peuvent encore être en cours (moins de la durée de la fenêtre)
+func (r *maintenanceRule) lastStart(t time.Time) (time.Time, bool) {
+	t = t.In(r.location)
+	year, month, day := t.Date()
+	earliest := t.Add(-r.duration)
+	days := int(r.duration/(24*time.Hour)) + 1
+	for i := 0; i <= days; i++ {
+		// Le plus tardif du jour: l'heure répétée rompt l'ordre des minutes
+		var last time.Time
+		for _, minute := range r.startsOn(time.Date(year, month, day-i, 12, 0, 0, 0, r.location)) {
+			for _, s := range r.occurrences(year, month, day-i, minute) {
+				if !s.After(t) && s.After(last) {
+					last = s
+				}
+			}
+		}
+		if !last.IsZero() {
+			return last, last.After(earliest)
+		}
+	}
+	return time.Time{}, false
+}
+
+// active - Indique si la fenêtre est en cours à l'instant t
+// Une fenêtre récurrente est active si l'une de ses occurrences a commencé
+// depuis moins que sa durée
+func (r *maintenanceRule) active(t time.Time) bool {
+	if !r.start.IsZero() {
+		return !t.Before(r.start) && t.Before(r.end)
+	}
+	_, ok := r.lastStart(t)
+	return ok
+}
+
+// next - Prochain début après t, dans l'année qui suit
+// Les jours sans occurrence sont écartés d'un coup, sans parcourir leurs minutes
+func (r *maintenanceRule) next(t time.Time) (time.Time, bool) {
+	t = t.In(r.location)
+	if !r.start.IsZero() {
+		return r.start, r.start.After(t)
+	}
+	year, month, day := t.Date()
+	limit := t.AddDate(1, 0, 0)
+	for i := 0; ; i++ {
+		if time.Date(year, month, day+i, 0, 0, 0, 0, r.location).After(limit) {
+			return time.Time{}, false
+		}
+		date := time.Date(year, month, day+i, 12, 0, 0, 0, r.location)
+		var first time.Time
+		for _, minute := range r.startsOn(date) {
+			for _, s := range r.occurrences(year, month, day+i, minute) {
+				if s.After(t) && s.Before(limit) && (first.IsZero() || s.Before(first)) {
+					first = s
+				}
+			}
+		}
+		if !first.IsZero() {
+			return first, true
+		}
+	}
+}
+
+// appliesTo - Indique si la fenêtre concerne un serveur
+func (r *maintenanceRule) appliesTo(serverID, group string) bool {
+	if len(r.window.Servers) == 0 && len(r.window.Groups) == 0 {
+		return true
+	}
+	for _, id := range r.window.Servers {
+		if id == serverID {
+			return true
+		}
+	}
+	for _, g := range r.window.Groups {
+		if NormalizeGroup(g) != "" && InGroup(group, g) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaintenanceSchedule - Fenêtres de maintenance compilées
+type MaintenanceSchedule struct {
+	rules []*maintenanceRule
+}
+
+// NewMaintenanceSchedule - Compile les fenêtres (les fenêtres invalides sont ignorées)
+func NewMaintenanceSchedule(windows []MaintenanceWindow) *MaintenanceSchedule {
+	schedule := &MaintenanceSchedule{}
+	for _, window := range windows {
+		if rule, err := window.compile(); err == nil {
+			schedule.rules = append(schedule.rules, rule)
+		}
+	}
+	return schedule
+}
+
+// ActiveFor - Nom de la fenêtre en cours pour un serveur, vide hors maintenance
+func (s *MaintenanceSchedule) ActiveFor(serverID, group string, t time.Time) string {
+	if s == nil {
+		return ""
+	}
+	for _, rule := range s.rules {
+		if rule.appliesTo(serverID, group) && rule.active(t) {
+			return rule.window.Name
+		}
+	}
+	return ""
+}
+
+// Status - État de chaque fenêtre à l'instant t
+func (s *MaintenanceSchedule) Status(t time.Time) []MaintenanceWindowStatus {
+	statuses := []MaintenanceWindowStatus{}
+	if s == nil {
+		return statuses
+	}
+	for _, rule := range s.rules {
+		status := MaintenanceWindowStatus{Window: rule.window, Active: rule.active(t)}
+		if next, ok := rule.next(t); ok {
+			status.Next = &next
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// ===== Expressions cron =====
+
+// cronSchedule - Expression cron à 5 champs (minute heure jour mois jour-de-semaine)
+type cronSchedule struct {
+	minutes, hours, days, months, weekdays []bool
+	anyDay, anyWeekday                     bool // Champ "*": seul l'autre champ de jour compte
+}
+
+// cronMacros - Raccourcis usuels
+var cronMacros = map[string]string{
+	"@yearly": "0 0 1 1 *", "@annually": "0 0 1 1 *", "@monthly": "0 0 1 * *",
+	"@weekly": "0 0 * * 0", "@daily": "0 0 * * *", "@midnight": "0 0 * * *", "@hourly": "0 * * * *",
+}
+
+// cronNames - Noms acceptés pour les mois et les jours de la semaine
+var (
+	cronMonthNames = []string{"", "jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+	cronDayNames   = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+)
+
+// parseCron - Analyse une expression cron (listes, plages, pas et noms acceptés)
+func parseCron(expr string) (*cronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := cronMacros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("5 champs attendus (minute heure jour mois jour-de-semaine), %d trouvés", len(fields))
+	}
+	schedule := &cronSchedule{anyDay: fields[2] == "*", anyWeekday: fields[4] == "*"}
+	var err error
+	if schedule.minutes, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("minute: %s", err)
+	}
+	if schedule.hours, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("heure: %s", err)
+	}
+	if schedule.days, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("jour: %s", err)
+	}
+	if schedule.months, err = parseCronField(fields[3], 1, 12, cronMonthNames); err != nil {
+		return nil, fmt.Errorf("mois: %s", err)
+	}
+	// Le dimanche s'écrit 0 ou 7
+	if schedule.weekdays, err = parseCronField(fields[4], 0, 7, cronDayNames); err != nil {
+		return nil, fmt.Errorf("jour de la semaine: %s", err)
+	}
+	schedule.weekdays[0] = schedule.weekdays[0] || schedule.weekdays[7]
+	return schedule, nil
+}
+
+// parseCronField - Valeurs autorisées d'un champ ("*/15", "1-5", "mon,wed"...)
+func parseCronField(field string, min, max int, names []string) ([]bool, error) {
+	allowed := make([]bool, max+1)
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("pas invalide: %s", part)
+			}
+			rangePart, step = part[:i], n
+		}
+		low, high := min, max
+		if rangePart != "*" {
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if low, err = cronValue(bounds[0], min, max, names); err != nil {
+				return nil, err
+			}
+			high = low
+			if len(bounds) == 2 {
+				if high, err = cronValue(bounds[1], min, max, names); err != nil {
+					return nil, err
+				}
+			} else if step > 1 {
+				high = max // "5/10": de 5 à la fin par pas de 10
+			}
+			if high < low {
+				return nil, fmt.Errorf("plage invalide: %s", rangePart)
+			}
+		}
+		for v := low; v <= high; v += step {
+			allowed[v] = true
+		}
+	}
+	return allowed, nil
+}
+
+// cronValue - Valeur numérique ou nom d'un élément de champ
+func cronValue(value string, min, max int, names []string) (int, error) {
+	for i, name := range names {
+		if name != "" && strings.EqualFold(value, name) {
+			return i, nil
+		}
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("valeur invalide: %s (de %d à %d)", value, min, max)
+	}
+	return n, nil
+}
+
+// matches - Indique si la minute t correspond à l'expression
+func (c *cronSchedule) matches(t time.Time) bool {
+	return c.minutes[t.Minute()] && c.hours[t.Hour()] && c.dayMatches(t)
+}
+
+// dayMatches - Indique si le jour de t correspond à l'expression
+// Comme cron, si jour du mois et jour de la semaine sont restreints tous
+// les deux, l'un ou l'autre suffit
+func (c *cronSchedule) dayMatches(t time.Time) bool {
+	if !c.months[int(t.Month())] {
+		return false
+	}
+	day, weekday := c.days[t.Day()], c.weekdays[int(t.Weekday())]
+	switch {
+	case c.anyDay && c.anyWeekday:
+		return true
+	case c.anyDay:
+		return weekday
+	case c.anyWeekday:
+		return day
+	}
+	return day || weekday
+}
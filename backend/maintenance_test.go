@@ -0,0 +1,197 @@
+package backend
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	// Lundi 19 octobre 2026
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		expr  string
+		match []time.Time
+		skip  []time.Time
+	}{
+		{"0 2 * * sun", []time.Time{at(25, 2, 0)}, []time.Time{at(25, 2, 1), at(24, 2, 0)}},
+		{"*/15 9-17 * * mon-fri", []time.Time{at(19, 9, 0), at(23, 17, 45)}, []time.Time{at(19, 9, 10), at(24, 10, 0), at(19, 18, 0)}},
+		{"5/20 * * * *", []time.Time{at(19, 0, 5), at(19, 0, 45)}, []time.Time{at(19, 0, 0), at(19, 0, 50)}},
+		{"0 0 1,15 * *", []time.Time{at(1, 0, 0), at(15, 0, 0)}, []time.Time{at(2, 0, 0)}},
+		{"0 0 * oct *", []time.Time{at(5, 0, 0)}, []time.Time{time.Date(2026, 11, 5, 0, 0, 0, 0, time.UTC)}},
+		// Jour du mois et jour de la semaine restreints: l'un ou l'autre suffit
+		{"0 0 13 * fri", []time.Time{at(13, 0, 0), at(23, 0, 0)}, []time.Time{at(22, 0, 0)}},
+		// Le dimanche s'écrit 0 ou 7
+		{"0 0 * * 7", []time.Time{at(25, 0, 0)}, []time.Time{at(24, 0, 0)}},
+		{"@daily", []time.Time{at(20, 0, 0)}, []time.Time{at(20, 1, 0)}},
+		{"@HOURLY", []time.Time{at(20, 13, 0)}, []time.Time{at(20, 13, 30)}},
+		{"  30   4  *  *  *  ", []time.Time{at(20, 4, 30)}, []time.Time{at(20, 4, 31)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			schedule, err := parseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("erreur inattendue: %s", err)
+			}
+			for _, m := range tt.match {
+				if !schedule.matches(m) {
+					t.Errorf("%s devrait correspondre", m.Format(MaintenanceTimeLayout))
+				}
+			}
+			for _, m := range tt.skip {
+				if schedule.matches(m) {
+					t.Errorf("%s ne devrait pas correspondre", m.Format(MaintenanceTimeLayout))
+				}
+			}
+		})
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"", "5 champs attendus"},
+		{"0 2 * *", "5 champs attendus"},
+		{"0 2 * * * *", "5 champs attendus"},
+		{"60 * * * *", "minute: valeur invalide"},
+		{"* 24 * * *", "heure: valeur invalide"},
+		{"* * 0 * *", "jour: valeur invalide"},
+		{"* * * 13 *", "mois: valeur invalide"},
+		{"* * * foo *", "mois: valeur invalide"},
+		{"* * * * 8", "jour de la semaine: valeur invalide"},
+		{"*/0 * * * *", "pas invalide"},
+		{"*/x * * * *", "pas invalide"},
+		{"10-5 * * * *", "plage invalide"},
+		{"1-2-3 * * * *", "valeur invalide"},
+		{"1,,2 * * * *", "valeur invalide"},
+		{"-1 * * * *", "valeur invalide"},
+		{"@reboot", "5 champs attendus"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := parseCron(tt.expr)
+			if err == nil {
+				t.Fatal("erreur attendue")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("erreur %q, attendu %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaintenanceWindowCompileErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		window MaintenanceWindow
+		field  string
+	}{
+		{"nom manquant", MaintenanceWindow{Cron: "0 2 * * *", Duration: "1h"}, "name"},
+		{"sans planification", MaintenanceWindow{Name: "w"}, "start"},
+		{"deux planifications", MaintenanceWindow{Name: "w", Cron: "0 2 * * *", Duration: "1h", From: "01:00", To: "02:00"}, "cron"},
+		{"fuseau inconnu", MaintenanceWindow{Name: "w", Cron: "0 2 * * *", Duration: "1h", Timezone: "Mars/Olympus"}, "timezone"},
+		{"début invalide", MaintenanceWindow{Name: "w", Start: "demain", End: "2026-10-20 23:00"}, "start"},
+		{"fin avant début", MaintenanceWindow{Name: "w", Start: "2026-10-20 23:00", End: "2026-10-20 22:00"}, "end"},
+		{"cron invalide", MaintenanceWindow{Name: "w", Cron: "0 2 * *", Duration: "1h"}, "cron"},
+		{"durée manquante", MaintenanceWindow{Name: "w", Cron: "0 2 * * *"}, "duration"},
+		{"durée trop longue", MaintenanceWindow{Name: "w", Cron: "0 2 * * *", Duration: "200h"}, "duration"},
+		{"heure invalide", MaintenanceWindow{Name: "w", From: "25:00", To: "02:00"}, "from"},
+		{"fin égale au début", MaintenanceWindow{Name: "w", From: "02:00", To: "02:00"}, "to"},
+		{"jour inconnu", MaintenanceWindow{Name: "w", Days: []string{"mon", "lundi"}, From: "01:00", To: "02:00"}, "days[1]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.window.compile()
+			merr, ok := err.(*maintenanceError)
+			if !ok {
+				t.Fatalf("maintenanceError attendue, obtenu %v", err)
+			}
+			if merr.field != tt.field {
+				t.Errorf("champ %q, attendu %q (%s)", merr.field, tt.field, merr.message)
+			}
+		})
+	}
+}
+
+func TestMaintenanceRuleActiveNext(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, paris)
+	}
+	tests := []struct {
+		name   string
+		window MaintenanceWindow
+		at     time.Time
+		active bool
+		next   time.Time // Zéro: aucun début dans l'année
+	}{
+		{
+			name:   "cron en cours",
+			window: MaintenanceWindow{Cron: "0 2 * * sun", Duration: "2h"},
+			at:     at(10, 11, 3, 59),
+			active: true,
+			next:   at(10, 18, 2, 0),
+		},
+		{
+			name:   "cron terminé",
+			window: MaintenanceWindow{Cron: "0 2 * * sun", Duration: "2h"},
+			at:     at(10, 11, 4, 0),
+			next:   at(10, 18, 2, 0),
+		},
+		{
+			name:   "cron sur plusieurs jours",
+			window: MaintenanceWindow{Cron: "0 22 * * fri", Duration: "60h"},
+			at:     at(10, 19, 9, 0),
+			active: true,
+			next:   at(10, 23, 22, 0),
+		},
+		{
+			name:   "plage passant minuit, lendemain",
+			window: MaintenanceWindow{Days: []string{"sat"}, From: "22:00", To: "06:00"},
+			at:     at(10, 18, 5, 30),
+			active: true,
+			next:   at(10, 24, 22, 0),
+		},
+		{
+			name:   "plage, jour non sélectionné",
+			window: MaintenanceWindow{Days: []string{"sat"}, From: "22:00", To: "06:00"},
+			at:     at(10, 19, 5, 30),
+			next:   at(10, 24, 22, 0),
+		},
+		{
+			name:   "heure répétée au passage à l'heure d'hiver",
+			window: MaintenanceWindow{Cron: "30 2 25 oct *", Duration: "10m"},
+			at:     time.Date(2026, 10, 25, 1, 35, 0, 0, time.UTC), // 02:35, seconde fois
+			active: true,
+			next:   time.Date(2027, 10, 25, 2, 30, 0, 0, paris),
+		},
+		{
+			name:   "date impossible",
+			window: MaintenanceWindow{Cron: "0 0 30 feb *", Duration: "1h"},
+			at:     at(1, 1, 0, 0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.window.Name, tt.window.Timezone = "w", "Europe/Paris"
+			rule, err := tt.window.compile()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if active := rule.active(tt.at); active != tt.active {
+				t.Errorf("active = %v, attendu %v", active, tt.active)
+			}
+			next, ok := rule.next(tt.at)
+			if ok != !tt.next.IsZero() || !next.Equal(tt.next) {
+				t.Errorf("next = %s (%v), attendu %s", next, ok, tt.next)
+			}
+		})
+	}
+}
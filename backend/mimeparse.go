@@ -0,0 +1,243 @@
+// Package backend - Analyse des emails reçus par le relais SMTP
+// Ce fichier décode les messages RFC 5322 / MIME: parties multipart
+// imbriquées, jeux de caractères, sujets encodés (RFC 2047), Reply-To et
+// pièces jointes, afin de les relayer sans perte
+package backend
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	netmail "net/mail"
+	"net/textproto"
+	"strings"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// MaxEmailSize - Taille maximale d'un email relayé, pièces jointes comprises (25 Mo)
+const MaxEmailSize = 25 << 20
+
+// ErrEmailTooLarge - Email dépassant MaxEmailSize
+var ErrEmailTooLarge = errors.New("email trop volumineux (25 Mo maximum)")
+
+// EmailAttachment - Pièce jointe (ou image intégrée) d'un email
+type EmailAttachment struct {
+	Filename    string `json:"filename"`             // Nom du fichier
+	ContentType string `json:"content_type"`         // Type MIME
+	ContentID   string `json:"content_id,omitempty"` // Identifiant des images intégrées (cid:)
+	Inline      bool   `json:"inline,omitempty"`     // Intégrée au corps HTML plutôt que jointe
+	Data        []byte `json:"data"`                 // Contenu décodé (base64 en JSON)
+}
+
+// ParsedEmail - Email décodé, prêt à être relayé
+type ParsedEmail struct {
+	Header      netmail.Header    `json:"-"`                  // En-têtes bruts
+	Subject     string            `json:"subject"`            // Sujet décodé (UTF-8)
+	ReplyTo     []string          `json:"reply_to,omitempty"` // Adresses Reply-To
+	Text        string            `json:"text"`               // Corps texte brut (UTF-8)
+	HTML        string            `json:"html,omitempty"`     // Corps HTML (UTF-8)
+	Attachments []EmailAttachment `json:"attachments,omitempty"`
+}
+
+// SplitRecipients - Répartit les destinataires de l'enveloppe SMTP en To/CC/BCC
+// Les destinataires absents des en-têtes To et Cc sont des copies cachées,
+// y compris lorsque le message n'a aucun en-tête To ni Cc
+func SplitRecipients(header netmail.Header, envelope []string) (to, cc, bcc []string) {
+	inHeader := func(name string) map[string]bool {
+		found := make(map[string]bool)
+		addresses, err := header.AddressList(name)
+		if err != nil {
+			return found
+		}
+		for _, addr := range addresses {
+			found[strings.ToLower(addr.Address)] = true
+		}
+		return found
+	}
+	toHeader, ccHeader := inHeader("To"), inHeader("Cc")
+
+	for _, rcpt := range envelope {
+		switch key := strings.ToLower(rcpt); {
+		case toHeader[key]:
+			to = append(to, rcpt)
+		case ccHeader[key]:
+			cc = append(cc, rcpt)
+		default:
+			bcc = append(bcc, rcpt)
+		}
+	}
+	return to, cc, bcc
+}
+
+// wordDecoder - Décodeur RFC 2047 acceptant tous les jeux de caractères connus
+var wordDecoder = &mime.WordDecoder{CharsetReader: charsetReader}
+
+// ParseEmail - Décode un message RFC 5322 complet
+// Un message de plus de MaxEmailSize octets est refusé (ErrEmailTooLarge)
+func ParseEmail(data []byte) (ParsedEmail, error) {
+	var parsed ParsedEmail
+
+	if len(data) > MaxEmailSize {
+		return parsed, ErrEmailTooLarge
+	}
+
+	msg, err := netmail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return parsed, fmt.Errorf("message invalide: %s", err)
+	}
+	parsed.Header = msg.Header
+	parsed.Subject = decodeHeader(msg.Header.Get("Subject"))
+
+	// Un Reply-To illisible est ignoré: le message est relayé sans lui
+	if raw := msg.Header.Get("Reply-To"); raw != "" {
+		parser := netmail.AddressParser{WordDecoder: wordDecoder}
+		addresses, err := parser.ParseList(raw)
+		if err != nil {
+			log.Printf("⚠️ En-tête Reply-To invalide ignoré (%q): %s", raw, err)
+			delete(parsed.Header, "Reply-To")
+		}
+		for _, addr := range addresses {
+			parsed.ReplyTo = append(parsed.ReplyTo, addr.String())
+		}
+	}
+
+	if err := parsed.parsePart(textproto.MIMEHeader(msg.Header), msg.Body, ""); err != nil {
+		return parsed, err
+	}
+	parsed.Text = strings.TrimSpace(parsed.Text)
+	return parsed, nil
+}
+
+// parsePart - Décode une partie MIME (récursif pour les multipart imbriqués)
+// Le premier texte brut et le premier HTML rencontrés forment le corps,
+// les autres parties deviennent des pièces jointes; parent est le type du
+// multipart contenant la partie (vide au premier niveau)
+func (p *ParsedEmail) parsePart(header textproto.MIMEHeader, body io.Reader, parent string) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		// Pas de Content-Type exploitable: texte brut (RFC 2045, section 5.2)
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("partie multipart invalide: %s", err)
+			}
+			if err := p.parsePart(part.Header, part, mediaType); err != nil {
+				return err
+			}
+		}
+	}
+
+	content, err := io.ReadAll(transferDecoder(header.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return fmt.Errorf("contenu illisible (%s): %s", mediaType, err)
+	}
+
+	disposition, dispParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	filename := dispParams["filename"]
+	if filename == "" {
+		filename = params["name"]
+	}
+	filename = decodeHeader(filename)
+	isAttachment := disposition == "attachment" || filename != ""
+
+	if !isAttachment {
+		switch {
+		case mediaType == "text/plain" && p.Text == "":
+			p.Text = decodeCharset(content, params["charset"])
+			return nil
+		case mediaType == "text/html" && p.HTML == "":
+			p.HTML = decodeCharset(content, params["charset"])
+			return nil
+		}
+	}
+
+	// Image intégrée (cid:): Content-ID dans un multipart/related, ou sans
+	// disposition attachment (beaucoup de clients omettent Content-Disposition)
+	contentID := strings.Trim(header.Get("Content-ID"), "<> ")
+	inline := contentID != "" && (parent == "multipart/related" || disposition != "attachment")
+	if filename == "" {
+		filename = defaultAttachmentName(mediaType, len(p.Attachments)+1)
+	}
+	p.Attachments = append(p.Attachments, EmailAttachment{
+		Filename:    filename,
+		ContentType: mediaType,
+		ContentID:   contentID,
+		Inline:      inline,
+		Data:        content,
+	})
+	return nil
+}
+
+// transferDecoder - Décode le Content-Transfer-Encoding d'une partie
+func transferDecoder(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, body)
+	}
+	return body
+}
+
+// decodeHeader - Décode les encoded-words RFC 2047 d'un en-tête
+// La valeur brute est conservée si le décodage échoue
+func decodeHeader(value string) string {
+	decoded, err := wordDecoder.DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
+
+// decodeCharset - Convertit un contenu texte en UTF-8
+// Un jeu de caractères inconnu laisse le contenu inchangé
+func decodeCharset(content []byte, charset string) string {
+	reader, err := charsetReader(charset, bytes.NewReader(content))
+	if err != nil {
+		return string(content)
+	}
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		return string(content)
+	}
+	return string(decoded)
+}
+
+// charsetReader - Lecteur convertissant un jeu de caractères en UTF-8
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	charset = strings.ToLower(strings.TrimSpace(charset))
+	if charset == "" || charset == "utf-8" || charset == "us-ascii" {
+		return input, nil
+	}
+	encoding, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, fmt.Errorf("jeu de caractères non supporté: %s", charset)
+	}
+	return encoding.NewDecoder().Reader(input), nil
+}
+
+// defaultAttachmentName - Nom attribué à une pièce jointe anonyme
+func defaultAttachmentName(mediaType string, index int) string {
+	ext := ".bin"
+	if mediaType == "message/rfc822" {
+		ext = ".eml"
+	} else if exts, err := mime.ExtensionsByType(mediaType); err == nil && len(exts) > 0 {
+		ext = exts[0]
+	}
+	return fmt.Sprintf("piece-jointe-%d%s", index, ext)
+}
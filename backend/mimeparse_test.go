@@ -0,0 +1,374 @@
+package backend
+
+import (
+	"errors"
+	netmail "net/mail"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// rawEmail - Message brut aux fins de ligne CRLF
+func rawEmail(lines ...string) string {
+	return strings.Join(lines, "\r\n")
+}
+
+func TestParseEmail(t *testing.T) {
+	tests := []struct {
+		name        string
+		raw         string
+		subject     string
+		replyTo     []string
+		text        string
+		html        string
+		attachments []EmailAttachment
+	}{
+		{
+			name: "texte brut sans Content-Type",
+			raw: rawEmail(
+				"From: monitor@example.com",
+				"To: ops@example.com",
+				"Subject: Serveur hors ligne",
+				"",
+				"Le serveur web ne répond plus.",
+				""),
+			subject: "Serveur hors ligne",
+			text:    "Le serveur web ne répond plus.",
+		},
+		{
+			name: "multipart/alternative",
+			raw: rawEmail(
+				"Subject: Alerte",
+				"MIME-Version: 1.0",
+				`Content-Type: multipart/alternative; boundary="alt"`,
+				"",
+				"--alt",
+				"Content-Type: text/plain; charset=utf-8",
+				"",
+				"Serveur web: DOWN",
+				"--alt",
+				"Content-Type: text/html; charset=utf-8",
+				"",
+				"<p>Serveur web: <b>DOWN</b></p>",
+				"--alt--",
+				""),
+			subject: "Alerte",
+			text:    "Serveur web: DOWN",
+			html:    "<p>Serveur web: <b>DOWN</b></p>",
+		},
+		{
+			name: "pièces jointes et image intégrée",
+			raw: rawEmail(
+				"Subject: Rapport",
+				`Content-Type: multipart/mixed; boundary="mixed"`,
+				"",
+				"--mixed",
+				`Content-Type: multipart/related; boundary="rel"`,
+				"",
+				"--rel",
+				"Content-Type: text/html; charset=utf-8",
+				"",
+				`<img src="cid:logo@example.com">`,
+				"--rel",
+				"Content-Type: image/png",
+				"Content-Transfer-Encoding: base64",
+				"Content-ID: <logo@example.com>",
+				"Content-Disposition: inline",
+				"",
+				"iVBORw==",
+				"--rel--",
+				"--mixed",
+				`Content-Type: application/pdf; name="ignored.pdf"`,
+				"Content-Transfer-Encoding: base64",
+				`Content-Disposition: attachment; filename="rapport.pdf"`,
+				"",
+				"JVBERi0xLjQ=",
+				"--mixed",
+				"Content-Type: text/plain; charset=utf-8",
+				`Content-Disposition: attachment; filename="journal.log"`,
+				"",
+				"journal",
+				"--mixed",
+				"Content-Type: message/rfc822",
+				"",
+				"Subject: original",
+				"",
+				"corps",
+				"--mixed--",
+				""),
+			subject: "Rapport",
+			html:    `<img src="cid:logo@example.com">`,
+			attachments: []EmailAttachment{
+				{Filename: "piece-jointe-1.png", ContentType: "image/png", ContentID: "logo@example.com", Inline: true, Data: []byte("\x89PNG")},
+				{Filename: "rapport.pdf", ContentType: "application/pdf", Data: []byte("%PDF-1.4")},
+				{Filename: "journal.log", ContentType: "text/plain", Data: []byte("journal")},
+				{Filename: "piece-jointe-4.eml", ContentType: "message/rfc822", Data: []byte("Subject: original\r\n\r\ncorps")},
+			},
+		},
+		{
+			name: "images intégrées sans Content-Disposition",
+			raw: rawEmail(
+				"Subject: Tableau de bord",
+				`Content-Type: multipart/mixed; boundary="mixed"`,
+				"",
+				"--mixed",
+				`Content-Type: multipart/related; boundary="rel"`,
+				"",
+				"--rel",
+				"Content-Type: text/html; charset=utf-8",
+				"",
+				`<img src="cid:graph"><img src="cid:logo">`,
+				"--rel",
+				`Content-Type: image/png; name="graph.png"`,
+				"Content-Transfer-Encoding: base64",
+				"Content-ID: <graph>",
+				"",
+				"iVBORw==",
+				"--rel",
+				"Content-Type: image/png",
+				"Content-Transfer-Encoding: base64",
+				"Content-ID: <logo>",
+				`Content-Disposition: attachment; filename="logo.png"`,
+				"",
+				"iVBORw==",
+				"--rel--",
+				"--mixed",
+				"Content-Type: image/png",
+				"Content-Transfer-Encoding: base64",
+				"Content-ID: <signature>",
+				"",
+				"iVBORw==",
+				"--mixed",
+				"Content-Type: image/png",
+				"Content-Transfer-Encoding: base64",
+				"Content-ID: <scan>",
+				`Content-Disposition: attachment; filename="scan.png"`,
+				"",
+				"iVBORw==",
+				"--mixed--",
+				""),
+			subject: "Tableau de bord",
+			html:    `<img src="cid:graph"><img src="cid:logo">`,
+			attachments: []EmailAttachment{
+				{Filename: "graph.png", ContentType: "image/png", ContentID: "graph", Inline: true, Data: []byte("\x89PNG")},
+				{Filename: "logo.png", ContentType: "image/png", ContentID: "logo", Inline: true, Data: []byte("\x89PNG")},
+				{Filename: "piece-jointe-3.png", ContentType: "image/png", ContentID: "signature", Inline: true, Data: []byte("\x89PNG")},
+				{Filename: "scan.png", ContentType: "image/png", ContentID: "scan", Data: []byte("\x89PNG")},
+			},
+		},
+		{
+			name: "en-têtes RFC 2047",
+			raw: rawEmail(
+				"Subject: =?UTF-8?B?U2VydmV1ciBob3JzIGxpZ25lIOKaoA==?=",
+				"Reply-To: =?ISO-8859-1?Q?=C9quipe_d'astreinte?= <astreinte@example.com>, noc@example.com",
+				`Content-Type: multipart/mixed; boundary="b"`,
+				"",
+				"--b",
+				"Content-Type: text/plain",
+				"",
+				"voir pièce jointe",
+				"--b",
+				"Content-Type: text/csv",
+				`Content-Disposition: attachment; filename="=?UTF-8?Q?disponibilit=C3=A9.csv?="`,
+				"",
+				"web;99.9",
+				"--b--",
+				""),
+			subject: "Serveur hors ligne ⚠",
+			// Noms décodés puis réencodés en UTF-8 pour l'en-tête relayé
+			replyTo: []string{"=?utf-8?b?w4lxdWlwZSBkJ2FzdHJlaW50ZQ==?= <astreinte@example.com>", "<noc@example.com>"},
+			text:    "voir pièce jointe",
+			attachments: []EmailAttachment{
+				{Filename: "disponibilité.csv", ContentType: "text/csv", Data: []byte("web;99.9")},
+			},
+		},
+		{
+			name: "jeux de caractères non UTF-8",
+			raw: rawEmail(
+				"Subject: =?ISO-8859-1?Q?Alerte_=E9t=E9?=",
+				`Content-Type: multipart/alternative; boundary="alt"`,
+				"",
+				"--alt",
+				"Content-Type: text/plain; charset=ISO-8859-1",
+				"Content-Transfer-Encoding: quoted-printable",
+				"",
+				"Caf=E9 indisponible =E0 10h",
+				"--alt",
+				"Content-Type: text/html; charset=windows-1252",
+				"Content-Transfer-Encoding: quoted-printable",
+				"",
+				"<p>Co=FBt: 5 =80</p>",
+				"--alt--",
+				""),
+			subject: "Alerte été",
+			text:    "Café indisponible à 10h",
+			html:    "<p>Coût: 5 €</p>",
+		},
+		{
+			name: "jeu de caractères inconnu conservé tel quel",
+			raw: rawEmail(
+				"Content-Type: text/plain; charset=x-inconnu",
+				"",
+				"brut",
+				""),
+			text: "brut",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseEmail([]byte(tt.raw))
+			if err != nil {
+				t.Fatalf("ParseEmail: %v", err)
+			}
+			if parsed.Subject != tt.subject {
+				t.Errorf("Subject = %q, attendu %q", parsed.Subject, tt.subject)
+			}
+			if !reflect.DeepEqual(parsed.ReplyTo, tt.replyTo) {
+				t.Errorf("ReplyTo = %q, attendu %q", parsed.ReplyTo, tt.replyTo)
+			}
+			if parsed.Text != tt.text {
+				t.Errorf("Text = %q, attendu %q", parsed.Text, tt.text)
+			}
+			if parsed.HTML != tt.html {
+				t.Errorf("HTML = %q, attendu %q", parsed.HTML, tt.html)
+			}
+			if !reflect.DeepEqual(parsed.Attachments, tt.attachments) {
+				t.Errorf("Attachments = %+v\nattendu %+v", parsed.Attachments, tt.attachments)
+			}
+		})
+	}
+}
+
+func TestParseEmailInvalidReplyTo(t *testing.T) {
+	raw := rawEmail(
+		"Subject: Alerte",
+		"Reply-To: <pas une adresse",
+		"",
+		"corps",
+		"")
+	parsed, err := ParseEmail([]byte(raw))
+	if err != nil {
+		t.Fatalf("message refusé pour un Reply-To invalide: %v", err)
+	}
+	if parsed.ReplyTo != nil || parsed.Header.Get("Reply-To") != "" {
+		t.Errorf("Reply-To invalide conservé: %q, en-tête %q", parsed.ReplyTo, parsed.Header.Get("Reply-To"))
+	}
+	if parsed.Subject != "Alerte" || parsed.Text != "corps" {
+		t.Errorf("message relayé incomplet: sujet %q, texte %q", parsed.Subject, parsed.Text)
+	}
+}
+
+func TestParseEmailErrors(t *testing.T) {
+	header := "Subject: volumineux\r\nContent-Type: text/plain\r\n\r\n"
+	atLimit := header + strings.Repeat("x", MaxEmailSize-len(header))
+
+	tests := []struct {
+		name    string
+		raw     string
+		wantErr error  // Erreur attendue (errors.Is)
+		errText string // Ou extrait du message d'erreur
+	}{
+		{name: "exactement 25 Mo", raw: atLimit},
+		{name: "au-delà de 25 Mo", raw: atLimit + "x", wantErr: ErrEmailTooLarge},
+		{name: "en-têtes invalides", raw: "pas un en-tête\r\n\r\ncorps", errText: "message invalide"},
+		{
+			name: "multipart tronqué",
+			raw: rawEmail(
+				`Content-Type: multipart/mixed; boundary="b"`,
+				"",
+				"--b",
+				"Content-Type: text/plain",
+				"",
+				"début sans fin"),
+			errText: "contenu illisible (text/plain): unexpected EOF",
+		},
+		{
+			name: "base64 invalide",
+			raw: rawEmail(
+				"Content-Type: application/pdf",
+				"Content-Transfer-Encoding: base64",
+				"",
+				"!!!"),
+			errText: "contenu illisible",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseEmail([]byte(tt.raw))
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("erreur %v, attendu %v", err, tt.wantErr)
+				}
+			case tt.errText != "":
+				if err == nil || !strings.Contains(err.Error(), tt.errText) {
+					t.Errorf("erreur %v, attendu %q", err, tt.errText)
+				}
+			case err != nil:
+				t.Errorf("ParseEmail: %v", err)
+			case len(parsed.Text) != MaxEmailSize-len(header):
+				t.Errorf("corps de %d octets, attendu %d", len(parsed.Text), MaxEmailSize-len(header))
+			}
+		})
+	}
+}
+
+func TestSplitRecipients(t *testing.T) {
+	envelope := []string{"alice@example.com", "Bob@Example.com", "carol@example.com"}
+	tests := []struct {
+		name        string
+		header      netmail.Header
+		envelope    []string
+		to, cc, bcc []string
+	}{
+		{
+			name:     "To, Cc et copie cachée",
+			header:   netmail.Header{"To": {"Alice <alice@example.com>"}, "Cc": {"bob@example.com"}},
+			envelope: envelope,
+			to:       []string{"alice@example.com"},
+			cc:       []string{"Bob@Example.com"},
+			bcc:      []string{"carol@example.com"},
+		},
+		{
+			name:     "To uniquement",
+			header:   netmail.Header{"To": {"alice@example.com, bob@example.com, carol@example.com"}},
+			envelope: envelope,
+			to:       envelope,
+		},
+		{
+			name:     "Cc uniquement",
+			header:   netmail.Header{"Cc": {"alice@example.com"}},
+			envelope: envelope,
+			cc:       []string{"alice@example.com"},
+			bcc:      []string{"Bob@Example.com", "carol@example.com"},
+		},
+		{
+			name:     "sans en-tête To ni Cc: tout en copie cachée",
+			header:   netmail.Header{"Subject": {"alerte"}},
+			envelope: envelope,
+			bcc:      envelope,
+		},
+		{
+			name:     "destinataires non divulgués",
+			header:   netmail.Header{"To": {"undisclosed-recipients:;"}},
+			envelope: envelope,
+			bcc:      envelope,
+		},
+		{
+			name:     "sans en-têtes",
+			envelope: envelope,
+			bcc:      envelope,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			to, cc, bcc := SplitRecipients(tt.header, tt.envelope)
+			if !reflect.DeepEqual(to, tt.to) || !reflect.DeepEqual(cc, tt.cc) || !reflect.DeepEqual(bcc, tt.bcc) {
+				t.Errorf("SplitRecipients = to %v, cc %v, bcc %v; attendu to %v, cc %v, bcc %v",
+					to, cc, bcc, tt.to, tt.cc, tt.bcc)
+			}
+		})
+	}
+}
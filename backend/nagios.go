@@ -0,0 +1,495 @@
+// Package backend - Import de définitions d'objets Nagios
+// Lit les blocs "define host/service/command/hostgroup { ... }" (modèles
+// "use" compris) et traduit le check de chaque hôte et service vers le check
+// le plus proche: ping, tcp sur le port du plugin ou http
+package backend
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// nagiosIntervalLength - Durée d'une unité d'intervalle (interval_length de nagios.cfg)
+const nagiosIntervalLength = 60
+
+// nagiosObject - Définition d'objet Nagios
+type nagiosObject struct {
+	kind  string            // host, service, command...
+	line  int               // Ligne du "define"
+	attrs map[string]string // Directives de l'objet
+}
+
+// nagiosObjects - Objets d'un fichier et modèles utilisables par "use"
+type nagiosObjects struct {
+	objects   []*nagiosObject
+	templates map[string]*nagiosObject // kind/name → modèle
+}
+
+// nagiosPorts - Port par défaut des plugins vérifiant un service TCP
+var nagiosPorts = map[string]int{
+	"check_ssh":    22,
+	"check_ftp":    21,
+	"check_smtp":   25,
+	"check_pop":    110,
+	"check_spop":   995,
+	"check_imap":   143,
+	"check_simap":  993,
+	"check_nntp":   119,
+	"check_ldap":   389,
+	"check_ldaps":  636,
+	"check_mysql":  3306,
+	"check_pgsql":  5432,
+	"check_jabber": 5222,
+	"check_clamd":  3310,
+}
+
+// nagiosValueFlags - Options des plugins suivies d'une valeur
+var nagiosValueFlags = map[string]bool{
+	"-H": true, "--hostname": true, "-I": true, "--IP-address": true,
+	"-p": true, "-P": true, "--port": true, "-u": true, "--url": true,
+	"-t": true, "--timeout": true, "-s": true,
+}
+
+// nagiosMacroRe - Macros $NOM$ des lignes de commande
+var nagiosMacroRe = regexp.MustCompile(`\$([A-Z0-9_]+)\$`)
+
+// ParseNagios - Traduit les hôtes et services de définitions d'objets Nagios
+// Les intervalles sont comptés en minutes (interval_length par défaut); les
+// checks sans équivalent (disque, charge, NRPE, SNMP...) sont ignorés. Les
+// hostgroups d'un hôte deviennent les tags de ses serveurs; un hôte dépend de
+// ses parents, un service de son hôte
+func ParseNagios(data []byte) (*ForeignImport, error) {
+	objs, err := parseNagiosObjects(data)
+	if err != nil {
+		return nil, err
+	}
+
+	commands := make(map[string]string)
+	hosts := make(map[string]*nagiosObject)
+	var hostNames []string
+	groups := make(map[string][]string)
+	for _, obj := range objs.objects {
+		switch obj.kind {
+		case "command":
+			commands[objs.get(obj, "command_name")] = objs.get(obj, "command_line")
+		case "host":
+			name := objs.get(obj, "host_name")
+			hosts[name] = obj
+			hostNames = append(hostNames, name)
+			for _, group := range nagiosList(objs.get(obj, "hostgroups")) {
+				groups[group] = append(groups[group], name)
+			}
+		case "hostgroup":
+			name := objs.get(obj, "hostgroup_name")
+			groups[name] = append(groups[name], nagiosList(objs.get(obj, "members"))...)
+		}
+	}
+
+	// Les hostgroups d'un hôte deviennent les tags de ses serveurs
+	hostGroups := make(map[string][]string)
+	for group, members := range groups {
+		for _, member := range members {
+			hostGroups[member] = append(hostGroups[member], group)
+		}
+	}
+	for _, tags := range hostGroups {
+		sort.Strings(tags)
+	}
+
+	result := &ForeignImport{ByLine: true}
+	for _, obj := range objs.objects {
+		switch obj.kind {
+		case "host":
+			name := objs.get(obj, "host_name")
+			if objs.get(obj, "check_command") == "" {
+				continue // Hôte jamais vérifié par Nagios
+			}
+			if server, ok := objs.translate(result, obj, name, obj, commands); ok {
+				server.Tags = hostGroups[name]
+				server.Parents = nagiosList(objs.get(obj, "parents"))
+				result.Servers = append(result.Servers, server)
+			}
+		case "service":
+			description := objs.get(obj, "service_description")
+			selected := nagiosSelect(nagiosList(objs.get(obj, "host_name")), hostNames, nil)
+			selected = nagiosSelect(nagiosList(objs.get(obj, "hostgroup_name")), nil, groups, selected...)
+			if len(selected) == 0 {
+				result.warn(obj.line, "service %s: aucun hôte associé, ignoré", description)
+				continue
+			}
+			for _, hostName := range selected {
+				host, ok := hosts[hostName]
+				if !ok {
+					result.warn(obj.line, "service %s: hôte %s non défini, ignoré", description, hostName)
+					continue
+				}
+				if server, ok := objs.translate(result, obj, hostName+" - "+description, host, commands); ok {
+					server.Tags = hostGroups[hostName]
+					server.Parents = []string{hostName} // Un service dépend de son hôte
+					result.Servers = append(result.Servers, server)
+				}
+			}
+		}
+	}
+
+	// Seuls les hôtes importés peuvent servir de parents
+	imported := make(map[string]bool, len(result.Servers))
+	for _, server := range result.Servers {
+		imported[server.Name] = true
+	}
+	for i := range result.Servers {
+		server := &result.Servers[i]
+		var parents []string
+		for _, parent := range server.Parents {
+			switch {
+			case imported[parent]:
+				parents = append(parents, parent)
+			case hosts[parent] == nil:
+				result.warn(server.Line, "%s: parent %s non défini, dépendance ignorée", server.Name, parent)
+			case !strings.HasPrefix(server.Name, parent+" - "):
+				// Les services d'un hôte non vérifié restent sans parent, sans avertissement
+				result.warn(server.Line, "%s: parent %s non importé, dépendance ignorée", server.Name, parent)
+			}
+		}
+		server.Parents = parents
+	}
+	return result, nil
+}
+
+// parseNagiosObjects - Découpe un fichier en définitions d'objets
+func parseNagiosObjects(data []byte) (*nagiosObjects, error) {
+	objs := &nagiosObjects{templates: make(map[string]*nagiosObject)}
+	var (
+		errs    ConfigErrors
+		current *nagiosObject
+		pending string // Ligne continuée par "\"
+		start   int
+	)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		raw := scanner.Text()
+		if pending == "" {
+			start = lineNo
+		}
+		if strings.HasSuffix(raw, `\`) {
+			pending += strings.TrimSuffix(raw, `\`)
+			continue
+		}
+		line := strings.TrimSpace(nagiosStripComment(pending + raw))
+		pending = ""
+		if line == "" {
+			continue
+		}
+
+		if current == nil {
+			if !strings.HasPrefix(line, "define") {
+				errs = append(errs, ConfigError{Line: start, Message: fmt.Sprintf("directive hors d'une définition: %s", line)})
+				continue
+			}
+			kind, rest, _ := strings.Cut(strings.TrimSpace(strings.TrimPrefix(line, "define")), "{")
+			kind = strings.TrimSpace(kind)
+			if kind == "" || strings.TrimSpace(rest) != "" {
+				errs = append(errs, ConfigError{Line: start, Message: "définition invalide: define <type> { attendu"})
+				continue
+			}
+			current = &nagiosObject{kind: kind, line: start, attrs: make(map[string]string)}
+			continue
+		}
+
+		closing := strings.HasSuffix(line, "}")
+		line = strings.TrimSpace(strings.TrimSuffix(line, "}"))
+		if line != "" {
+			key, value := line, ""
+			if i := strings.IndexAny(line, " \t"); i >= 0 {
+				key, value = line[:i], line[i+1:]
+			}
+			current.attrs[key] = strings.TrimSpace(value)
+		}
+		if closing {
+			objs.add(current)
+			current = nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if current != nil {
+		errs = append(errs, ConfigError{Line: current.line, Message: fmt.Sprintf("définition %s non terminée (} manquante)", current.kind)})
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return objs, nil
+}
+
+// nagiosStripComment - Retire le commentaire d'une ligne (# ou ; hors "\;")
+func nagiosStripComment(line string) string {
+	if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";") {
+		return ""
+	}
+	for i := 0; i < len(line); i++ {
+		if line[i] == ';' && (i == 0 || line[i-1] != '\\') {
+			line = line[:i]
+			break
+		}
+	}
+	return strings.ReplaceAll(line, `\;`, ";")
+}
+
+// add - Enregistre un objet; "name" le rend utilisable comme modèle et
+// "register 0" en fait un modèle seulement
+func (o *nagiosObjects) add(obj *nagiosObject) {
+	if name := obj.attrs["name"]; name != "" {
+		o.templates[obj.kind+"/"+name] = obj
+	}
+	if obj.attrs["register"] != "0" {
+		o.objects = append(o.objects, obj)
+	}
+}
+
+// get - Directive d'un objet, héritée de ses modèles si absente
+func (o *nagiosObjects) get(obj *nagiosObject, key string) string {
+	return o.lookup(obj, key, 0)
+}
+
+func (o *nagiosObjects) lookup(obj *nagiosObject, key string, depth int) string {
+	if value, ok := obj.attrs[key]; ok {
+		return strings.TrimPrefix(value, "+")
+	}
+	if depth > 20 { // Héritage circulaire
+		return ""
+	}
+	for _, name := range nagiosList(obj.attrs["use"]) {
+		if template, ok := o.templates[obj.kind+"/"+name]; ok {
+			if value := o.lookup(template, key, depth+1); value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// translate - Serveur équivalent au check d'un hôte ou d'un service
+func (o *nagiosObjects) translate(result *ForeignImport, obj *nagiosObject, name string, host *nagiosObject, commands map[string]string) (ForeignServer, bool) {
+	hostName := o.get(host, "host_name")
+	address := o.get(host, "address")
+	if address == "" {
+		address = hostName
+	}
+	check := o.get(obj, "check_command")
+	if check == "" {
+		result.warn(obj.line, "%s: check_command manquant, ignoré", name)
+		return ForeignServer{}, false
+	}
+
+	// Ligne de commande: définition de la commande avec ses arguments $ARGn$
+	parts := strings.Split(check, "!")
+	commandLine, ok := commands[parts[0]]
+	if !ok {
+		commandLine = strings.Join(parts, " ")
+	}
+	macros := map[string]string{"HOSTADDRESS": address, "HOSTNAME": hostName}
+	for i, arg := range parts[1:] {
+		macros["ARG"+strconv.Itoa(i+1)] = arg
+	}
+	commandLine = nagiosMacroRe.ReplaceAllStringFunc(commandLine, func(macro string) string {
+		// Macros inconnues vides, dont $USER1$ (chemin des plugins)
+		return macros[strings.Trim(macro, "$")]
+	})
+	args := nagiosSplitArgs(commandLine)
+	if len(args) == 0 {
+		result.warn(obj.line, "%s: commande %s vide, ignoré", name, parts[0])
+		return ForeignServer{}, false
+	}
+	plugin := path.Base(args[0])
+	flags := nagiosFlags(args[1:])
+	target := flags["-H"]
+	if target == "" {
+		target = address
+	}
+
+	server := ForeignServer{Line: obj.line, Name: name}
+	interval := o.get(obj, "check_interval")
+	if interval == "" {
+		interval = o.get(obj, "normal_check_interval")
+	}
+	minutes, err := strconv.ParseFloat(interval, 64)
+	if err != nil {
+		minutes = 5 // check_interval par défaut de Nagios
+	}
+	server.Interval = formatSeconds(minutes * nagiosIntervalLength)
+	seconds, err := strconv.ParseFloat(flags["-t"], 64)
+	if err != nil {
+		seconds = 10 // Timeout par défaut des plugins
+	}
+	server.Timeout = formatSeconds(seconds)
+
+	switch {
+	case plugin == "check_ping" || plugin == "check_icmp" || plugin == "check_fping" ||
+		plugin == "check_host" || plugin == "check-host-alive":
+		server.Type, server.URL = "ping", target
+	case plugin == "check_http" || plugin == "check_https" || plugin == "check_curl":
+		host := flags["-H"]
+		if host == "" {
+			host = flags["-I"]
+		}
+		if host == "" {
+			host = address
+		}
+		_, ssl := flags["-S"]
+		_, cert := flags["-C"]
+		scheme, defaultPort := "http", 80
+		if ssl || cert || plugin == "check_https" {
+			scheme, defaultPort = "https", 443
+		}
+		if port, err := strconv.Atoi(flags["-p"]); err == nil && port != defaultPort {
+			host = hostPort(host, port)
+		}
+		uri := flags["-u"]
+		if !strings.HasPrefix(uri, "/") {
+			uri = "/" + uri
+		}
+		server.Type, server.URL = "http", scheme+"://"+host+uri
+	case plugin == "check_tcp":
+		port, err := strconv.Atoi(flags["-p"])
+		if err != nil {
+			result.warn(obj.line, "%s: check_tcp sans port, ignoré", name)
+			return ForeignServer{}, false
+		}
+		server.Type, server.URL = "tcp", hostPort(target, port)
+	case nagiosPorts[plugin] != 0:
+		port := nagiosPorts[plugin]
+		portFlag := flags["-p"]
+		if plugin == "check_mysql" || plugin == "check_pgsql" {
+			portFlag = flags["-P"]
+		}
+		if p, err := strconv.Atoi(portFlag); err == nil {
+			port = p
+		}
+		server.Type, server.URL = "tcp", hostPort(target, port)
+	case plugin == "check_dns" || plugin == "check_dig":
+		server.Type, server.URL = "ping", address
+		result.warn(obj.line, "%s: %s importé en ping de %s (résolution DNS non vérifiée)", name, plugin, address)
+	default:
+		result.warn(obj.line, "%s: %s non pris en charge, ignoré", name, plugin)
+		return ForeignServer{}, false
+	}
+	// Checks actifs désactivés dans Nagios: importé en pause
+	server.Paused = o.get(obj, "active_checks_enabled") == "0"
+	return server, true
+}
+
+// nagiosList - Éléments d'une liste séparée par des virgules
+func nagiosList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// nagiosSelect - Ajoute à selected les hôtes désignés par une liste d'hôtes
+// (all = tous les hôtes pour "*") ou de groupes (groups non nil); les éléments
+// préfixés par "!" sont exclus
+func nagiosSelect(items, all []string, groups map[string][]string, selected ...string) []string {
+	members := func(item string) []string {
+		if groups != nil {
+			return groups[item]
+		}
+		if item == "*" {
+			return all
+		}
+		return []string{item}
+	}
+	excluded := make(map[string]bool)
+	for _, item := range items {
+		if strings.HasPrefix(item, "!") {
+			for _, member := range members(strings.TrimPrefix(item, "!")) {
+				excluded[member] = true
+			}
+		}
+	}
+	seen := make(map[string]bool)
+	for _, name := range selected {
+		seen[name] = true
+	}
+	for _, item := range items {
+		if strings.HasPrefix(item, "!") {
+			continue
+		}
+		for _, member := range members(item) {
+			if !seen[member] && !excluded[member] {
+				seen[member] = true
+				selected = append(selected, member)
+			}
+		}
+	}
+	return selected
+}
+
+// nagiosSplitArgs - Découpe une ligne de commande (guillemets simples et doubles)
+func nagiosSplitArgs(line string) []string {
+	var (
+		args    []string
+		current strings.Builder
+		quote   rune
+		inArg   bool
+	)
+	for _, r := range line {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args
+}
+
+// nagiosFlags - Options d'un plugin, sous leur forme courte (-H, -p...)
+// Les options sans valeur sont présentes avec une valeur vide
+func nagiosFlags(args []string) map[string]string {
+	long := map[string]string{
+		"--hostname": "-H", "--IP-address": "-I", "--port": "-p", "--url": "-u",
+		"--timeout": "-t", "--ssl": "-S", "--certificate": "-C",
+	}
+	flags := make(map[string]string)
+	for i := 0; i < len(args); i++ {
+		flag, value, inline := strings.Cut(args[i], "=")
+		if !strings.HasPrefix(flag, "-") {
+			continue
+		}
+		if !inline && nagiosValueFlags[flag] && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		if short, ok := long[flag]; ok {
+			flag = short
+		}
+		flags[flag] = value
+	}
+	return flags
+}
@@ -0,0 +1,195 @@
+package backend
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// nagiosSample - Définitions d'objets couvrant modèles, hostgroups, parents et commandes
+const nagiosSample = `# Commandes
+define command {
+    command_name    check_http_url
+    command_line    $USER1$/check_http -H $HOSTADDRESS$ -u $ARG1$ -S -p 8443
+}
+define command {
+    command_name    check_tcp_port
+    command_line    $USER1$/check_tcp -H $HOSTADDRESS$ -p $ARG1$ -t 30
+}
+
+define host {
+    name                  generic-host ; modèle seulement
+    check_command         check-host-alive
+    check_interval        2
+    register              0
+}
+define host {
+    use         generic-host
+    host_name   router
+    address     10.0.0.1
+}
+define host {
+    use         generic-host
+    host_name   web1
+    address     10.0.0.10
+    parents     router
+    hostgroups  web-servers
+}
+define host {
+    host_name   passive
+    address     10.0.0.99
+}
+define hostgroup {
+    hostgroup_name  prod
+    members         web1
+}
+define service {
+    host_name            web1
+    service_description  HTTPS
+    check_command        check_http_url!/health
+}
+define service {
+    hostgroup_name       web-servers
+    service_description  SSH
+    check_command        check_ssh
+    active_checks_enabled 0
+}
+define service {
+    host_name            web1, router
+    service_description  Postgres
+    check_command        check_tcp_port!5432
+}
+define service {
+    host_name            web1
+    service_description  Disk
+    check_command        check_nrpe!check_disk
+}
+`
+
+func TestParseNagios(t *testing.T) {
+	result, err := ParseNagios([]byte(nagiosSample))
+	if err != nil {
+		t.Fatalf("erreur inattendue: %s", err)
+	}
+	want := []ForeignServer{
+		{Line: 17, Name: "router", URL: "10.0.0.1", Type: "ping", Interval: "2m", Timeout: "10s"},
+		{Line: 22, Name: "web1", URL: "10.0.0.10", Type: "ping", Interval: "2m", Timeout: "10s",
+			Tags: []string{"prod", "web-servers"}, Parents: []string{"router"}},
+		{Line: 37, Name: "web1 - HTTPS", URL: "https://10.0.0.10:8443/health", Type: "http", Interval: "5m", Timeout: "10s",
+			Tags: []string{"prod", "web-servers"}, Parents: []string{"web1"}},
+		{Line: 42, Name: "web1 - SSH", URL: "10.0.0.10:22", Type: "tcp", Interval: "5m", Timeout: "10s",
+			Tags: []string{"prod", "web-servers"}, Parents: []string{"web1"}, Paused: true},
+		{Line: 48, Name: "web1 - Postgres", URL: "10.0.0.10:5432", Type: "tcp", Interval: "5m", Timeout: "30s",
+			Tags: []string{"prod", "web-servers"}, Parents: []string{"web1"}},
+		{Line: 48, Name: "router - Postgres", URL: "10.0.0.1:5432", Type: "tcp", Interval: "5m", Timeout: "30s",
+			Parents: []string{"router"}},
+	}
+	if len(result.Servers) != len(want) {
+		t.Fatalf("%d serveurs, attendu %d: %+v", len(result.Servers), len(want), result.Servers)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(result.Servers[i], want[i]) {
+			t.Errorf("serveur %d:\nobtenu  %+v\nattendu %+v", i, result.Servers[i], want[i])
+		}
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "ligne 53: web1 - Disk: check_nrpe non pris en charge") {
+		t.Errorf("avertissements: %q", result.Warnings)
+	}
+}
+
+func TestParseNagiosCommands(t *testing.T) {
+	tests := []struct {
+		name    string
+		check   string
+		typ     string
+		url     string
+		warning string
+	}{
+		{"ping", "check_ping!100,20%!500,60%", "ping", "192.0.2.1", ""},
+		{"http par défaut", "check_http", "http", "http://192.0.2.1/", ""},
+		{"http, options longues", "check_http --hostname=www.example.com --url=/status --ssl", "http", "https://www.example.com/status", ""},
+		{"http, port non standard", "check_http -H example.com -p 8080 -u 'path with space'", "http", "http://example.com:8080/path with space", ""},
+		{"tcp", "check_tcp -p 6379", "tcp", "192.0.2.1:6379", ""},
+		{"mysql, port -P", "check_mysql -P 3307", "tcp", "192.0.2.1:3307", ""},
+		{"dns", "check_dns -H example.com", "ping", "192.0.2.1", "résolution DNS non vérifiée"},
+		{"tcp sans port", "check_tcp", "", "", "check_tcp sans port, ignoré"},
+		{"plugin inconnu", "check_load", "", "", "check_load non pris en charge"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := "define host {\n  host_name h\n  address 192.0.2.1\n  check_command " + tt.check + "\n}\n"
+			result, err := ParseNagios([]byte(src))
+			if err != nil {
+				t.Fatalf("erreur inattendue: %s", err)
+			}
+			if tt.typ == "" {
+				if len(result.Servers) != 0 {
+					t.Errorf("serveur inattendu: %+v", result.Servers)
+				}
+			} else if len(result.Servers) != 1 || result.Servers[0].Type != tt.typ || result.Servers[0].URL != tt.url {
+				t.Errorf("obtenu %+v, attendu %s %s", result.Servers, tt.typ, tt.url)
+			}
+			if tt.warning != "" && (len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], tt.warning)) {
+				t.Errorf("avertissements %q, attendu %q", result.Warnings, tt.warning)
+			}
+		})
+	}
+}
+
+func TestParseNagiosSyntax(t *testing.T) {
+	// Lignes continuées, commentaires en fin de ligne et ";" échappé
+	src := "define host {\n  host_name h ; commentaire\n  address 192.0.2.1\n  check_command check_http -u /a\\;b \\\n    -p 8080\n}\n"
+	result, err := ParseNagios([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Servers) != 1 || result.Servers[0].URL != "http://192.0.2.1:8080/a;b" {
+		t.Errorf("obtenu %+v", result.Servers)
+	}
+}
+
+func TestParseNagiosErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		lines []int
+	}{
+		{"directive hors définition", "host_name h\n", []int{1}},
+		{"type manquant", "define {\n}\n", []int{1, 2}},
+		{"accolade manquante", "\ndefine host\n", []int{2}},
+		{"définition non terminée", "define host {\n  host_name h\n", []int{1}},
+		{"plusieurs erreurs", "foo\ndefine host {\n}\nbar\n", []int{1, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseNagios([]byte(tt.src))
+			var errs ConfigErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("ConfigErrors attendues, obtenu %v", err)
+			}
+			var lines []int
+			for _, e := range errs {
+				lines = append(lines, e.Line)
+			}
+			if !reflect.DeepEqual(lines, tt.lines) {
+				t.Errorf("lignes %v, attendu %v (%s)", lines, tt.lines, errs)
+			}
+		})
+	}
+}
+
+func TestParseNagiosServiceWithoutHost(t *testing.T) {
+	src := "define service {\n  service_description Orphan\n  check_command check_ping\n}\n" +
+		"define service {\n  host_name ghost\n  service_description Lost\n  check_command check_ping\n}\n"
+	result, err := ParseNagios([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Servers) != 0 || len(result.Warnings) != 2 {
+		t.Fatalf("obtenu %+v / %q", result.Servers, result.Warnings)
+	}
+	if !strings.Contains(result.Warnings[0], "aucun hôte associé") || !strings.Contains(result.Warnings[1], "hôte ghost non défini") {
+		t.Errorf("avertissements: %q", result.Warnings)
+	}
+}
@@ -5,6 +5,7 @@ package backend
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -19,6 +20,64 @@ type NotificationManager struct {
 	Cooldown time.Duration                   // Délai minimum entre notifications
 	mutex    sync.RWMutex                    // Mutex pour accès concurrent
 	enabled  bool                            // Notifications activées ou non
+
+	maintenance map[string]string // identifiant du serveur -> fenêtre de maintenance en cours
+	digest      []DeferredAlert   // alertes retenues pendant les heures calmes
+}
+
+// CriticalPolicy - Seuils de déclenchement des alertes critiques
+// Une valeur nulle désactive le critère correspondant
+type CriticalPolicy struct {
+	AfterFailures int           // Échecs consécutifs avant la première alerte critique
+	RepeatEvery   int           // Répétition de l'alerte tous les N échecs (multiples de N)
+	AfterDowntime time.Duration // Durée de panne avant alerte critique
+}
+
+// CriticalDisabled - Valeur d'une surcharge par serveur désactivant le critère
+// (0 reprend le réglage global)
+const CriticalDisabled = -1
+
+// Merge - Applique une surcharge (par serveur) à la politique globale
+// Les champs positifs de la surcharge remplacent les valeurs globales,
+// CriticalDisabled désactive le critère, 0 garde la valeur globale
+func (p CriticalPolicy) Merge(override CriticalPolicy) CriticalPolicy {
+	switch {
+	case override.AfterFailures > 0:
+		p.AfterFailures = override.AfterFailures
+	case override.AfterFailures == CriticalDisabled:
+		p.AfterFailures = 0
+	}
+	switch {
+	case override.RepeatEvery > 0:
+		p.RepeatEvery = override.RepeatEvery
+	case override.RepeatEvery == CriticalDisabled:
+		p.RepeatEvery = 0
+	}
+	switch {
+	case override.AfterDowntime > 0:
+		p.AfterDowntime = override.AfterDowntime
+	case override.AfterDowntime == CriticalDisabled:
+		p.AfterDowntime = 0
+	}
+	return p
+}
+
+// ShouldEscalate - Indique si le N-ième échec consécutif déclenche une alerte critique
+// Première alerte au seuil, puis à chaque multiple de RepeatEvery au-delà,
+// comme avant les seuils configurables (3, puis 5, 10, 15… par défaut)
+func (p CriticalPolicy) ShouldEscalate(failures int) bool {
+	if p.AfterFailures <= 0 || failures < p.AfterFailures {
+		return false
+	}
+	if failures == p.AfterFailures {
+		return true
+	}
+	return p.RepeatEvery > 0 && failures%p.RepeatEvery == 0
+}
+
+// DowntimeExceeded - Indique si la durée de panne dépasse le seuil critique
+func (p CriticalPolicy) DowntimeExceeded(downFor time.Duration) bool {
+	return p.AfterDowntime > 0 && downFor >= p.AfterDowntime
 }
 
 // NewNotificationManager - Constructeur du gestionnaire de notifications
@@ -29,6 +88,8 @@ func NewNotificationManager(cooldownMinutes int) *NotificationManager {
 		Cooldown: time.Duration(cooldownMinutes) * time.Minute, // Conversion en durée
 		mutex:    sync.RWMutex{},                       // Mutex initialisé
 		enabled:  true,                                 // Notifications activées par défaut
+
+		maintenance: make(map[string]string), // Aucun serveur en maintenance
 	}
 }
 
@@ -64,7 +125,7 @@ func (n *NotificationManager) ShouldNotify(serverName, notificationType string)
 // Send envoie une notification avec un meilleur formatage
 func (n *NotificationManager) Send(serverName, status string) {
 	if !n.ShouldNotify(serverName, status) {
-		fmt.Printf("Notification bloquée par le cooldown pour %s (%s)\n", serverName, status)
+		n.logBlocked(serverName, status)
 		return
 	}
 
@@ -93,17 +154,48 @@ func (n *NotificationManager) Send(serverName, status string) {
 	}
 }
 
-// SendCritical envoie une notification critique (plus persistante)
-func (n *NotificationManager) SendCritical(serverName, status string) {
-	// Les notifications critiques ignorent le cooldown normal
+// SendRootCause envoie l'alerte DOWN d'un serveur dont d'autres dépendent:
+// ses dépendants sont injoignables et leurs propres alertes suspendues
+func (n *NotificationManager) SendRootCause(serverName string, dependents []string) {
+	if !n.ShouldNotify(serverName, "DOWN") {
+		n.logBlocked(serverName, "DOWN")
+		return
+	}
+
+	title := "🔴 Panne en cascade"
+	message := fmt.Sprintf("Le serveur '%s' ne répond plus: %d serveur(s) dépendant(s) injoignable(s)", serverName, len(dependents))
+
+	err := beeep.Notify(title, message, "../build/Icons-green.icns")
+	if err != nil {
+		fmt.Printf("Erreur d'envoi de notification pour %s: %v\n", serverName, err)
+	} else {
+		fmt.Printf("Notification envoyée: %s - %s\n", title, message)
+	}
+}
+
+// logBlocked trace une notification bloquée par le cooldown
+func (n *NotificationManager) logBlocked(serverName, status string) {
+	fmt.Printf("Notification bloquée par le cooldown pour %s (%s)\n", serverName, status)
+}
+
+// ShouldNotifyCritical - Vérifie si une notification critique peut être envoyée
+// Les notifications critiques ignorent le cooldown mais l'envoi est enregistré
+func (n *NotificationManager) ShouldNotifyCritical(serverName string) bool {
 	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
 	if n.LastSent[serverName] == nil {
 		n.LastSent[serverName] = make(map[string]time.Time)
 	}
 	n.LastSent[serverName]["CRITICAL"] = time.Now()
-	n.mutex.Unlock()
 
-	if !n.enabled {
+	return n.enabled
+}
+
+// SendCritical envoie une notification critique (plus persistante)
+func (n *NotificationManager) SendCritical(serverName, status string) {
+	// Les notifications critiques ignorent le cooldown normal
+	if !n.ShouldNotifyCritical(serverName) {
 		return
 	}
 
@@ -145,6 +237,96 @@ func (n *NotificationManager) SendSummary(downServers []string) {
 	}
 }
 
+// SetMaintenance indique la fenêtre de maintenance en cours d'un serveur,
+// par identifiant (vide: fin de la maintenance); ses alertes sont suspendues
+// pendant la fenêtre
+func (n *NotificationManager) SetMaintenance(serverID, window string) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if window == "" {
+		delete(n.maintenance, serverID)
+		return
+	}
+	n.maintenance[serverID] = window
+}
+
+// MaintenanceFor retourne la fenêtre de maintenance en cours d'un serveur,
+// par identifiant (vide si aucune)
+func (n *NotificationManager) MaintenanceFor(serverID string) string {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+	return n.maintenance[serverID]
+}
+
+// Defer retient une alerte jusqu'à la fin des heures calmes (résumé)
+// Les alertes répétées d'un serveur (même type, même résumé) sont regroupées
+// en une seule entrée: un serveur instable ne remplit pas la mémoire
+func (n *NotificationManager) Defer(alert DeferredAlert) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	for i := range n.digest {
+		held := &n.digest[i]
+		if held.Server == alert.Server && held.Kind == alert.Kind && held.Release.Equal(alert.Release) {
+			held.Count++
+			held.Last, held.Message = alert.Time, alert.Message
+			return
+		}
+	}
+	alert.Count, alert.Last = 1, alert.Time
+	n.digest = append(n.digest, alert)
+}
+
+// TakeDigest retire et retourne les alertes retenues dont les heures calmes
+// sont terminées à l'instant now, dans l'ordre chronologique
+func (n *NotificationManager) TakeDigest(now time.Time) []DeferredAlert {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	var due, pending []DeferredAlert
+	for _, alert := range n.digest {
+		if alert.Release.After(now) {
+			pending = append(pending, alert)
+		} else {
+			due = append(due, alert)
+		}
+	}
+	n.digest = pending
+	return due
+}
+
+// PendingDigest retourne les alertes retenues en attente du résumé
+func (n *NotificationManager) PendingDigest() []DeferredAlert {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+	return append([]DeferredAlert{}, n.digest...)
+}
+
+// SendDigest envoie le résumé des alertes retenues pendant les heures calmes
+func (n *NotificationManager) SendDigest(alerts []DeferredAlert) {
+	if len(alerts) == 0 || !n.IsEnabled() {
+		return
+	}
+
+	// Serveurs concernés, dans l'ordre des alertes
+	var servers []string
+	seen := make(map[string]bool)
+	for _, alert := range alerts {
+		if !seen[alert.Server] {
+			seen[alert.Server] = true
+			servers = append(servers, alert.Server)
+		}
+	}
+
+	title := "🌅 Résumé des heures calmes"
+	message := fmt.Sprintf("%d alerte(s) pendant les heures calmes: %s", len(alerts), strings.Join(servers, ", "))
+
+	err := beeep.Notify(title, message, "../build/Icons-green.icns")
+	if err != nil {
+		fmt.Printf("Erreur d'envoi du résumé des heures calmes: %v\n", err)
+	} else {
+		fmt.Printf("Notification envoyée: %s - %s\n", title, message)
+	}
+}
+
 // SetEnabled active ou désactive les notifications
 func (n *NotificationManager) SetEnabled(enabled bool) {
 	n.mutex.Lock()
@@ -0,0 +1,104 @@
+package backend
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCriticalPolicyMerge(t *testing.T) {
+	global := CriticalPolicy{AfterFailures: 3, RepeatEvery: 5, AfterDowntime: 10 * time.Minute}
+	tests := []struct {
+		name     string
+		override CriticalPolicy
+		want     CriticalPolicy
+	}{
+		{
+			name: "sans surcharge",
+			want: global,
+		},
+		{
+			name:     "surcharge complète",
+			override: CriticalPolicy{AfterFailures: 1, RepeatEvery: 2, AfterDowntime: time.Minute},
+			want:     CriticalPolicy{AfterFailures: 1, RepeatEvery: 2, AfterDowntime: time.Minute},
+		},
+		{
+			name:     "surcharge partielle",
+			override: CriticalPolicy{RepeatEvery: 20},
+			want:     CriticalPolicy{AfterFailures: 3, RepeatEvery: 20, AfterDowntime: 10 * time.Minute},
+		},
+		{
+			name:     "répétitions désactivées",
+			override: CriticalPolicy{RepeatEvery: CriticalDisabled},
+			want:     CriticalPolicy{AfterFailures: 3, AfterDowntime: 10 * time.Minute},
+		},
+		{
+			name:     "alerte sur échecs désactivée",
+			override: CriticalPolicy{AfterFailures: CriticalDisabled},
+			want:     CriticalPolicy{RepeatEvery: 5, AfterDowntime: 10 * time.Minute},
+		},
+		{
+			name:     "alerte sur durée de panne désactivée",
+			override: CriticalPolicy{AfterDowntime: CriticalDisabled},
+			want:     CriticalPolicy{AfterFailures: 3, RepeatEvery: 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := global.Merge(tt.override); got != tt.want {
+				t.Errorf("Merge = %+v, attendu %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCriticalPolicyShouldEscalate(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy CriticalPolicy
+		want   []int // Échecs déclenchant une alerte critique, de 1 à 20
+	}{
+		{
+			name:   "réglages par défaut",
+			policy: DefaultSettings().CriticalPolicy(),
+			want:   []int{3, 5, 10, 15, 20},
+		},
+		{
+			name:   "seuil multiple de la répétition",
+			policy: CriticalPolicy{AfterFailures: 4, RepeatEvery: 4},
+			want:   []int{4, 8, 12, 16, 20},
+		},
+		{
+			name:   "seuil au-delà de la première répétition",
+			policy: CriticalPolicy{AfterFailures: 7, RepeatEvery: 5},
+			want:   []int{7, 10, 15, 20},
+		},
+		{
+			name:   "sans répétition",
+			policy: CriticalPolicy{AfterFailures: 3},
+			want:   []int{3},
+		},
+		{
+			name:   "répétitions désactivées par le serveur",
+			policy: DefaultSettings().CriticalPolicy().Merge(CriticalPolicy{RepeatEvery: CriticalDisabled}),
+			want:   []int{3},
+		},
+		{
+			name:   "alerte sur échecs désactivée",
+			policy: CriticalPolicy{RepeatEvery: 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			for failures := 1; failures <= 20; failures++ {
+				if tt.policy.ShouldEscalate(failures) {
+					got = append(got, failures)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("alertes aux échecs %v, attendu %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+// Package backend - Jetons OAuth2 pour l'authentification SMTP XOAUTH2
+// Ce fichier renouvelle les jetons d'accès à partir d'un refresh token
+// (Microsoft 365, Gmail) et les conserve en mémoire jusqu'à leur expiration
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OAuth2Config - Paramètres de renouvellement du jeton d'accès XOAUTH2
+type OAuth2Config struct {
+	TokenURL     string `json:"token_url"`               // Point de terminaison des jetons
+	ClientID     string `json:"client_id,omitempty"`     // Identifiant de l'application
+	ClientSecret string `json:"client_secret,omitempty"` // Secret de l'application (optionnel)
+	RefreshToken string `json:"refresh_token,omitempty"` // Jeton de renouvellement
+	Scopes       string `json:"scopes,omitempty"`        // Portées demandées (séparées par des espaces)
+}
+
+// cachedToken - Jeton d'accès en mémoire
+type cachedToken struct {
+	accessToken  string
+	refreshToken string // Dernier refresh token reçu (rotation)
+	expiresAt    time.Time
+}
+
+// tokenCache - Jetons d'accès par configuration OAuth2
+var (
+	tokenCache   = make(map[string]cachedToken)
+	tokenCacheMu sync.Mutex
+)
+
+// tokenExpiryMargin - Marge avant expiration pour renouveler le jeton
+const tokenExpiryMargin = time.Minute
+
+// refreshTokenRotated - Appelée (hors verrou, dans une goroutine) quand le
+// fournisseur remplace le refresh token de la configuration previous
+var (
+	refreshTokenRotated   func(previous OAuth2Config, refreshToken string)
+	refreshTokenRotatedMu sync.Mutex
+)
+
+// OnRefreshTokenRotated - Enregistre la fonction qui persiste un refresh token
+// renouvelé: l'ancien peut être révoqué par le fournisseur et ne permettrait
+// plus de s'authentifier au prochain démarrage
+func OnRefreshTokenRotated(fn func(previous OAuth2Config, refreshToken string)) {
+	refreshTokenRotatedMu.Lock()
+	defer refreshTokenRotatedMu.Unlock()
+	refreshTokenRotated = fn
+}
+
+// Enabled - Indique si le renouvellement automatique est configuré
+func (c *OAuth2Config) Enabled() bool {
+	return c != nil && c.TokenURL != "" && c.ClientID != "" && c.RefreshToken != ""
+}
+
+// AccessToken - Renvoie un jeton d'accès valide, renouvelé si nécessaire
+func (c *OAuth2Config) AccessToken() (string, error) {
+	if !c.Enabled() {
+		return "", fmt.Errorf("configuration OAuth2 incomplète (token_url, client_id, refresh_token)")
+	}
+	key := c.TokenURL + "|" + c.ClientID + "|" + c.RefreshToken
+
+	tokenCacheMu.Lock()
+	defer tokenCacheMu.Unlock()
+
+	cached, ok := tokenCache[key]
+	if ok && time.Now().Add(tokenExpiryMargin).Before(cached.expiresAt) {
+		return cached.accessToken, nil
+	}
+
+	refreshToken := c.RefreshToken
+	if ok && cached.refreshToken != "" {
+		refreshToken = cached.refreshToken
+	}
+	token, err := c.refresh(refreshToken)
+	if err != nil {
+		return "", err
+	}
+	tokenCache[key] = token
+
+	if token.refreshToken != "" && token.refreshToken != refreshToken {
+		// Jeton valable aussi pour la configuration mise à jour avec le nouveau refresh token
+		tokenCache[c.TokenURL+"|"+c.ClientID+"|"+token.refreshToken] = token
+		refreshTokenRotatedMu.Lock()
+		notify := refreshTokenRotated
+		refreshTokenRotatedMu.Unlock()
+		if notify != nil {
+			go notify(*c, token.refreshToken)
+		}
+	}
+	return token.accessToken, nil
+}
+
+// refresh - Demande un nouveau jeton d'accès (grant_type=refresh_token)
+func (c *OAuth2Config) refresh(refreshToken string) (cachedToken, error) {
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+		"client_id":     {c.ClientID},
+	}
+	if c.ClientSecret != "" {
+		form.Set("client_secret", c.ClientSecret)
+	}
+	if c.Scopes != "" {
+		form.Set("scope", c.Scopes)
+	}
+
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.PostForm(c.TokenURL, form)
+	if err != nil {
+		return cachedToken{}, fmt.Errorf("renouvellement du jeton OAuth2 impossible: %s", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		AccessToken      string `json:"access_token"`
+		RefreshToken     string `json:"refresh_token"`
+		ExpiresIn        int    `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return cachedToken{}, fmt.Errorf("réponse OAuth2 illisible (HTTP %d): %s", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || body.AccessToken == "" {
+		detail := strings.TrimSpace(body.Error + " " + body.ErrorDescription)
+		if detail == "" {
+			detail = resp.Status
+		}
+		return cachedToken{}, fmt.Errorf("renouvellement du jeton OAuth2 refusé: %s", detail)
+	}
+
+	expiresIn := time.Duration(body.ExpiresIn) * time.Second
+	if expiresIn <= 0 {
+		expiresIn = time.Hour
+	}
+	return cachedToken{
+		accessToken:  body.AccessToken,
+		refreshToken: body.RefreshToken,
+		expiresAt:    time.Now().Add(expiresIn),
+	}, nil
+}
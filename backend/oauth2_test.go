@@ -0,0 +1,60 @@
+package backend
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestOAuth2RefreshTokenRotation(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		if got := r.FormValue("refresh_token"); got != "old-refresh" {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"access-%d","refresh_token":"new-refresh","expires_in":3600}`, n)
+	}))
+	defer server.Close()
+
+	rotated := make(chan string, 1)
+	OnRefreshTokenRotated(func(previous OAuth2Config, refreshToken string) {
+		if previous.RefreshToken != "old-refresh" {
+			t.Errorf("configuration précédente: %q", previous.RefreshToken)
+		}
+		rotated <- refreshToken
+	})
+	defer OnRefreshTokenRotated(nil)
+
+	config := &OAuth2Config{TokenURL: server.URL, ClientID: "client", RefreshToken: "old-refresh"}
+	token, err := config.AccessToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "access-1" {
+		t.Errorf("jeton d'accès %q", token)
+	}
+
+	select {
+	case refreshToken := <-rotated:
+		if refreshToken != "new-refresh" {
+			t.Errorf("nouveau refresh token %q", refreshToken)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("rotation du refresh token non signalée")
+	}
+
+	// Configuration mise à jour avec le nouveau refresh token: jeton en cache
+	updated := &OAuth2Config{TokenURL: server.URL, ClientID: "client", RefreshToken: "new-refresh"}
+	if token, err := updated.AccessToken(); err != nil || token != "access-1" {
+		t.Errorf("AccessToken après rotation = %q, %v", token, err)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("%d appels au fournisseur, attendu 1", n)
+	}
+}
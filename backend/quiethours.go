@@ -0,0 +1,241 @@
+// Package backend - Heures calmes
+// Pendant les heures calmes (nuits, week-ends...), seules les alertes
+// critiques sont envoyées, ou bien toutes les alertes sont retenues puis
+// regroupées dans un résumé envoyé à la fin de la période. Le réglage global
+// peut être surchargé par type de check et par serveur
+package backend
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Modes des heures calmes
+const (
+	QuietModeCritical = "critical" // Seules les alertes critiques sont envoyées
+	QuietModeDigest   = "digest"   // Alertes regroupées dans un résumé à la fin des heures calmes
+)
+
+// maxQuietHoursSpan - Report maximal d'un résumé (heures calmes sans fin)
+const maxQuietHoursSpan = 8 * 24 * time.Hour
+
+// quietCheckTypes - Types de check pouvant surcharger les heures calmes
+var quietCheckTypes = map[string]bool{"http": true, "tcp": true, "ping": true, "email-heartbeat": true, "push": true}
+
+// QuietPeriod - Plage horaire calme sur certains jours de la semaine
+type QuietPeriod struct {
+	Days []string `json:"days,omitempty"` // Jours ("mon" à "sun"), vide = tous les jours
+	From string   `json:"from"`           // Début (ex: "22:00")
+	To   string   `json:"to"`             // Fin, le lendemain si elle précède le début (égale au début: 24 h)
+}
+
+// QuietHours - Heures calmes et traitement des alertes pendant celles-ci
+type QuietHours struct {
+	Enabled  bool          `json:"enabled"`
+	Mode     string        `json:"mode,omitempty"`     // "critical" (défaut) ou "digest"
+	Timezone string        `json:"timezone,omitempty"` // Fuseau IANA (ex: "Europe/Paris"), vide = fuseau local
+	Periods  []QuietPeriod `json:"periods,omitempty"`  // Plages calmes (ex: nuits, week-end)
+}
+
+// QuietPolicy - Heures calmes globales et surcharges par type de check,
+// compilées une fois pour toutes (NewQuietPolicy)
+type QuietPolicy struct {
+	global    *QuietSchedule
+	byType    map[string]*QuietSchedule
+	overrides *quietOverrides // Surcharges des serveurs, compilées à la première alerte
+}
+
+// quietOverrides - Heures calmes propres à chaque serveur, déjà compilées
+type quietOverrides struct {
+	mutex   sync.Mutex
+	servers map[string]quietOverride // identifiant du serveur -> surcharge
+}
+
+// quietOverride - Surcharge d'un serveur et sa version compilée
+type quietOverride struct {
+	hours    QuietHours
+	schedule *QuietSchedule
+}
+
+// NewQuietPolicy - Compile les heures calmes globales et par type de check
+func NewQuietPolicy(global QuietHours, byType map[string]QuietHours) QuietPolicy {
+	policy := QuietPolicy{
+		global:    global.Schedule(),
+		byType:    make(map[string]*QuietSchedule, len(byType)),
+		overrides: &quietOverrides{servers: make(map[string]quietOverride)},
+	}
+	for checkType, quiet := range byType {
+		policy.byType[checkType] = quiet.Schedule()
+	}
+	return policy
+}
+
+// QuietPolicy renvoie les heures calmes définies dans les settings
+func (s Settings) QuietPolicy() QuietPolicy {
+	return NewQuietPolicy(s.QuietHours, s.QuietHoursByType)
+}
+
+// For - Heures calmes effectives d'un serveur: sa propre surcharge, sinon
+// celle de son type de check, sinon le réglage global (nil: aucune)
+func (p QuietPolicy) For(serverID, checkType string, override *QuietHours) *QuietSchedule {
+	if override != nil {
+		return p.overrides.schedule(serverID, *override)
+	}
+	if schedule, ok := p.byType[checkType]; ok {
+		return schedule
+	}
+	return p.global
+}
+
+// schedule - Surcharge compilée d'un serveur, recompilée si elle a changé
+func (o *quietOverrides) schedule(serverID string, hours QuietHours) *QuietSchedule {
+	if o == nil {
+		return hours.Schedule()
+	}
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if cached, ok := o.servers[serverID]; ok && reflect.DeepEqual(cached.hours, hours) {
+		return cached.schedule
+	}
+	schedule := hours.Schedule()
+	o.servers[serverID] = quietOverride{hours: hours, schedule: schedule}
+	return schedule
+}
+
+// Forget - Oublie la surcharge compilée d'un serveur supprimé
+func (p QuietPolicy) Forget(serverID string) {
+	if p.overrides == nil {
+		return
+	}
+	p.overrides.mutex.Lock()
+	defer p.overrides.mutex.Unlock()
+	delete(p.overrides.servers, serverID)
+}
+
+// Validate - Vérifie le mode, le fuseau et les plages des heures calmes
+func (q QuietHours) Validate() error {
+	_, err := q.compile()
+	return err
+}
+
+// compile - Plages calmes prêtes à être évaluées
+func (q QuietHours) compile() ([]*maintenanceRule, error) {
+	switch q.Mode {
+	case "", QuietModeCritical, QuietModeDigest:
+	default:
+		return nil, &maintenanceError{"mode", fmt.Sprintf("heures calmes: mode inconnu: %s (critical ou digest)", q.Mode)}
+	}
+	location := time.Local
+	if q.Timezone != "" {
+		var err error
+		if location, err = time.LoadLocation(q.Timezone); err != nil {
+			return nil, &maintenanceError{"timezone", fmt.Sprintf("heures calmes: fuseau horaire inconnu: %s", q.Timezone)}
+		}
+	}
+	if q.Enabled && len(q.Periods) == 0 {
+		return nil, &maintenanceError{"periods", "heures calmes: au moins une plage horaire requise"}
+	}
+
+	rules := make([]*maintenanceRule, 0, len(q.Periods))
+	for i, period := range q.Periods {
+		prefix := fmt.Sprintf("periods[%d].", i)
+		from, ok := parseClock(period.From)
+		if !ok {
+			return nil, &maintenanceError{prefix + "from", fmt.Sprintf("heures calmes: heure de début invalide (HH:MM attendu): %s", period.From)}
+		}
+		to, ok := parseClock(period.To)
+		if !ok {
+			return nil, &maintenanceError{prefix + "to", fmt.Sprintf("heures calmes: heure de fin invalide (HH:MM attendu): %s", period.To)}
+		}
+		if to <= from {
+			to += 24 * 60 // Plage passant minuit, ou journée entière
+		}
+		rule := &maintenanceRule{location: location, from: from, duration: time.Duration(to-from) * time.Minute}
+		for j, day := range period.Days {
+			weekday, ok := parseWeekday(day)
+			if !ok {
+				return nil, &maintenanceError{fmt.Sprintf("%sdays[%d]", prefix, j), fmt.Sprintf("heures calmes: jour inconnu: %s (mon à sun)", day)}
+			}
+			rule.days[weekday] = true
+		}
+		if len(period.Days) == 0 {
+			rule.days = [7]bool{true, true, true, true, true, true, true}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// QuietSchedule - Heures calmes compilées, prêtes à être évaluées
+// nil: pas d'heures calmes (désactivées ou invalides)
+type QuietSchedule struct {
+	mode  string
+	rules []*maintenanceRule
+}
+
+// Schedule - Heures calmes compilées, nil si désactivées ou invalides
+func (q QuietHours) Schedule() *QuietSchedule {
+	if !q.Enabled {
+		return nil
+	}
+	rules, err := q.compile()
+	if err != nil {
+		return nil
+	}
+	return &QuietSchedule{mode: q.Mode, rules: rules}
+}
+
+// DigestMode - Indique si les alertes sont regroupées dans un résumé
+func (s *QuietSchedule) DigestMode() bool {
+	return s != nil && s.mode == QuietModeDigest
+}
+
+// ActiveAt - Indique si l'instant t tombe dans les heures calmes
+func (s *QuietSchedule) ActiveAt(t time.Time) bool {
+	if s == nil {
+		return false
+	}
+	for _, rule := range s.rules {
+		if rule.active(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// EndAfter - Fin des heures calmes en cours à l'instant t (t hors heures calmes)
+// La fin de chaque plage en cours découle de son début et de sa durée; une
+// plage qui commence avant la fin d'une autre la prolonge. Des plages
+// couvrant toute la semaine sont bornées à maxQuietHoursSpan
+func (s *QuietSchedule) EndAfter(t time.Time) time.Time {
+	if s == nil {
+		return t
+	}
+	end, limit := t, t.Add(maxQuietHoursSpan)
+	for extended := true; extended && end.Before(limit); {
+		extended = false
+		for _, rule := range s.rules {
+			if start, ok := rule.lastStart(end); ok {
+				end, extended = start.Add(rule.duration), true
+			}
+		}
+	}
+	if end.After(limit) {
+		return limit
+	}
+	return end
+}
+
+// DeferredAlert - Alerte retenue pendant les heures calmes, envoyée dans le résumé
+type DeferredAlert struct {
+	Server  string    `json:"server"`            // Nom du serveur
+	Kind    string    `json:"kind"`              // DOWN, UP ou CRITICAL
+	Message string    `json:"message,omitempty"` // Détail de l'alerte
+	Time    time.Time `json:"time"`              // Horodatage de l'alerte
+	Release time.Time `json:"release"`           // Fin des heures calmes: envoi du résumé
+
+	Count int       `json:"count,omitempty"` // Alertes identiques regroupées (serveur et type)
+	Last  time.Time `json:"last"`            // Horodatage de la dernière d'entre elles
+}
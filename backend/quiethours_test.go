@@ -0,0 +1,91 @@
+package backend
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuietScheduleEndAfter(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, time.UTC) // Lundi 19 octobre 2026
+	}
+	nights := QuietPeriod{From: "22:00", To: "07:00"}
+	weekend := QuietPeriod{Days: []string{"sat", "sun"}, From: "00:00", To: "00:00"}
+	tests := []struct {
+		name    string
+		periods []QuietPeriod
+		at      time.Time
+		active  bool
+		end     time.Time
+	}{
+		{"nuit, avant minuit", []QuietPeriod{nights}, at(19, 23, 10), true, at(20, 7, 0)},
+		{"nuit, après minuit", []QuietPeriod{nights}, at(20, 6, 59), true, at(20, 7, 0)},
+		{"journée", []QuietPeriod{nights}, at(20, 7, 0), false, at(20, 7, 0)},
+		// Les plages qui se suivent ou se chevauchent prolongent les heures calmes
+		{"week-end prolongé par les nuits", []QuietPeriod{nights, weekend}, at(23, 23, 0), true, at(26, 7, 0)},
+		{"toute la semaine", []QuietPeriod{{From: "00:00", To: "00:00"}}, at(19, 12, 0), true, at(19, 12, 0).Add(maxQuietHoursSpan)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := QuietHours{Enabled: true, Timezone: "UTC", Periods: tt.periods}.Schedule()
+			if schedule == nil {
+				t.Fatal("heures calmes invalides")
+			}
+			if active := schedule.ActiveAt(tt.at); active != tt.active {
+				t.Errorf("ActiveAt = %v, attendu %v", active, tt.active)
+			}
+			if end := schedule.EndAfter(tt.at); !end.Equal(tt.end) {
+				t.Errorf("EndAfter = %s, attendu %s", end, tt.end)
+			}
+		})
+	}
+}
+
+func TestQuietPolicyFor(t *testing.T) {
+	nights := QuietHours{Enabled: true, Periods: []QuietPeriod{{From: "22:00", To: "07:00"}}}
+	digest := nights
+	digest.Mode = QuietModeDigest
+	policy := NewQuietPolicy(nights, map[string]QuietHours{"push": {Enabled: false}})
+
+	if policy.For("a", "http", nil) == nil || policy.For("a", "http", nil).DigestMode() {
+		t.Error("réglage global attendu")
+	}
+	if policy.For("a", "push", nil) != nil {
+		t.Error("heures calmes désactivées pour les checks push")
+	}
+	first := policy.For("a", "http", &digest)
+	if !first.DigestMode() || policy.For("a", "http", &digest) != first {
+		t.Error("surcharge du serveur compilée une seule fois")
+	}
+	changed := digest
+	changed.Mode = QuietModeCritical
+	if policy.For("a", "http", &changed).DigestMode() {
+		t.Error("surcharge modifiée non prise en compte")
+	}
+	if (QuietHours{Enabled: true, Mode: "loud"}).Schedule() != nil {
+		t.Error("heures calmes invalides ignorées")
+	}
+}
+
+func TestDeferCollapsesRepeatedAlerts(t *testing.T) {
+	n := NewNotificationManager(0)
+	release := time.Date(2026, 10, 20, 7, 0, 0, 0, time.UTC)
+	start := release.Add(-8 * time.Hour)
+	for i := 0; i < 100; i++ {
+		at := start.Add(time.Duration(i) * time.Minute)
+		n.Defer(DeferredAlert{Server: "web", Kind: "DOWN", Message: "timeout", Time: at, Release: release})
+		n.Defer(DeferredAlert{Server: "web", Kind: "UP", Time: at, Release: release})
+	}
+	n.Defer(DeferredAlert{Server: "db", Kind: "DOWN", Time: start, Release: release})
+
+	pending := n.PendingDigest()
+	if len(pending) != 3 {
+		t.Fatalf("%d alertes retenues, attendu 3", len(pending))
+	}
+	if down := pending[0]; down.Count != 100 || !down.Time.Equal(start) || !down.Last.Equal(start.Add(99*time.Minute)) {
+		t.Errorf("regroupement: %+v", down)
+	}
+	if due := n.TakeDigest(release); len(due) != 3 || len(n.PendingDigest()) != 0 {
+		t.Errorf("résumé: %d alertes dues", len(due))
+	}
+}
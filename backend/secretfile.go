@@ -0,0 +1,212 @@
+// Package backend - Fichier de secrets chiffré
+// Ce fichier implémente le stockage de repli des secrets lorsque le trousseau
+// du système est indisponible: clé dérivée de la phrase de passe maîtresse
+// (Argon2id) et chiffrement authentifié XChaCha20-Poly1305
+package backend
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// Paramètres de dérivation de la clé (recommandations RFC 9106)
+const (
+	secretKDFTime    = 3
+	secretKDFMemory  = 64 * 1024 // en Kio
+	secretKDFThreads = 4
+)
+
+// secretFileVersion - Version du format du fichier de secrets
+const secretFileVersion = 1
+
+// minPassphraseLength - Longueur minimale d'une nouvelle phrase de passe
+const minPassphraseLength = 8
+
+// secretFileAAD - Données associées authentifiées avec le contenu
+var secretFileAAD = []byte("monitoring_serv secrets v1")
+
+// secretFile - Format du fichier de secrets sur le disque
+type secretFile struct {
+	Version int    `json:"version"`
+	KDF     string `json:"kdf"` // "argon2id"
+	Salt    []byte `json:"salt"`
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"` // Secrets chiffrés (JSON clé → valeur)
+}
+
+// encryptedFileStore - Secrets chiffrés par une phrase de passe maîtresse
+type encryptedFileStore struct {
+	path    string
+	mutex   sync.Mutex
+	key     []byte            // Clé dérivée (nil = verrouillé)
+	header  secretFile        // Paramètres de dérivation en cours
+	secrets map[string]string // Secrets déchiffrés
+}
+
+// secretsFilePath - Chemin du fichier de secrets chiffré
+func secretsFilePath() (string, error) {
+	return ConfigFilePath("secrets.enc")
+}
+
+// newEncryptedFileStore - Constructeur du stockage chiffré (verrouillé)
+func newEncryptedFileStore(path string) *encryptedFileStore {
+	return &encryptedFileStore{path: path}
+}
+
+// Exists - Indique si le fichier de secrets a déjà été créé
+func (f *encryptedFileStore) Exists() bool {
+	_, err := os.Stat(f.path)
+	return err == nil
+}
+
+// Locked - Indique si la phrase de passe n'a pas encore été fournie
+func (f *encryptedFileStore) Locked() bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.key == nil
+}
+
+// Unlock - Dérive la clé et déchiffre le fichier existant
+// Sans fichier, la phrase de passe devient celle du futur fichier
+func (f *encryptedFileStore) Unlock(passphrase string) error {
+	data, err := os.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		if len(passphrase) < minPassphraseLength {
+			return fmt.Errorf("phrase de passe trop courte (%d caractères minimum)", minPassphraseLength)
+		}
+		header := secretFile{
+			Version: secretFileVersion,
+			KDF:     "argon2id",
+			Salt:    make([]byte, 16),
+			Time:    secretKDFTime,
+			Memory:  secretKDFMemory,
+			Threads: secretKDFThreads,
+		}
+		if _, err := rand.Read(header.Salt); err != nil {
+			return err
+		}
+		f.mutex.Lock()
+		f.header = header
+		f.key = deriveSecretKey(passphrase, header)
+		f.secrets = make(map[string]string)
+		f.mutex.Unlock()
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var header secretFile
+	if err := json.Unmarshal(data, &header); err != nil {
+		return fmt.Errorf("fichier de secrets invalide: %s", err)
+	}
+	if header.Version != secretFileVersion || header.KDF != "argon2id" {
+		return fmt.Errorf("format de fichier de secrets non supporté (version %d, %s)", header.Version, header.KDF)
+	}
+	// Paramètres bornés: un fichier modifié ne doit pas épuiser la mémoire
+	if header.Time < 1 || header.Time > 16 || header.Memory < 8*1024 || header.Memory > 1024*1024 || header.Threads < 1 {
+		return fmt.Errorf("paramètres de dérivation du fichier de secrets invalides")
+	}
+
+	key := deriveSecretKey(passphrase, header)
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return err
+	}
+	plain, err := aead.Open(nil, header.Nonce, header.Data, secretFileAAD)
+	if err != nil {
+		return fmt.Errorf("phrase de passe incorrecte ou fichier de secrets corrompu")
+	}
+	secrets := make(map[string]string)
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return fmt.Errorf("contenu du fichier de secrets invalide: %s", err)
+	}
+
+	f.mutex.Lock()
+	f.header = header
+	f.key = key
+	f.secrets = secrets
+	f.mutex.Unlock()
+	return nil
+}
+
+// Get - Lit un secret déchiffré
+func (f *encryptedFileStore) Get(key string) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.key == nil {
+		return "", ErrSecretStoreLocked
+	}
+	value, ok := f.secrets[key]
+	if !ok {
+		return "", ErrSecretNotFound
+	}
+	return value, nil
+}
+
+// Set - Enregistre un secret et réécrit le fichier
+func (f *encryptedFileStore) Set(key, value string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.key == nil {
+		return ErrSecretStoreLocked
+	}
+	f.secrets[key] = value
+	return f.save()
+}
+
+// Delete - Supprime un secret et réécrit le fichier
+func (f *encryptedFileStore) Delete(key string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.key == nil {
+		return ErrSecretStoreLocked
+	}
+	if _, ok := f.secrets[key]; !ok {
+		return nil
+	}
+	delete(f.secrets, key)
+	return f.save()
+}
+
+// save - Chiffre les secrets avec un nouveau nonce (appelant verrouillé)
+func (f *encryptedFileStore) save() error {
+	plain, err := json.Marshal(f.secrets)
+	if err != nil {
+		return err
+	}
+	aead, err := chacha20poly1305.NewX(f.key)
+	if err != nil {
+		return err
+	}
+	header := f.header
+	header.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(header.Nonce); err != nil {
+		return err
+	}
+	header.Data = aead.Seal(nil, header.Nonce, plain, secretFileAAD)
+
+	data, err := json.MarshalIndent(header, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := WriteFileAtomic(f.path, data, 0o600); err != nil {
+		return err
+	}
+	f.header = header
+	return nil
+}
+
+// deriveSecretKey - Dérive la clé de chiffrement de la phrase de passe
+func deriveSecretKey(passphrase string, header secretFile) []byte {
+	return argon2.IDKey([]byte(passphrase), header.Salt, header.Time, header.Memory, header.Threads, chacha20poly1305.KeySize)
+}
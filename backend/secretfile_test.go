@@ -0,0 +1,182 @@
+package backend
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testPassphrase - Phrase de passe maîtresse des tests
+const testPassphrase = "correct horse battery"
+
+func TestEncryptedFileStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+	store := newEncryptedFileStore(path)
+	if _, err := store.Get("smtp.password"); !errors.Is(err, ErrSecretStoreLocked) {
+		t.Fatalf("Get verrouillé = %v, attendu ErrSecretStoreLocked", err)
+	}
+	if err := store.Set("smtp.password", "hunter2"); !errors.Is(err, ErrSecretStoreLocked) {
+		t.Fatalf("Set verrouillé = %v, attendu ErrSecretStoreLocked", err)
+	}
+	if err := store.Unlock("court"); err == nil {
+		t.Fatal("phrase de passe trop courte acceptée")
+	}
+
+	if err := store.Unlock(testPassphrase); err != nil {
+		t.Fatal(err)
+	}
+	if store.Exists() {
+		t.Error("fichier créé avant le premier secret")
+	}
+	if err := store.Set("smtp.password", "hunter2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set("smtp.oauth2.refresh_token", "1//token"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete("smtp.oauth2.refresh_token"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "hunter2") {
+		t.Error("secret en clair dans le fichier chiffré")
+	}
+	if err := checkLegacySecrets(data); err != nil {
+		t.Errorf("en-tête du fichier invalide: %v", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("fichier de secrets non réservé à l'utilisateur: %v", err)
+	}
+
+	reopened := newEncryptedFileStore(path)
+	if !reopened.Locked() {
+		t.Fatal("nouveau stockage déjà déverrouillé")
+	}
+	if err := reopened.Unlock(testPassphrase); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := reopened.Get("smtp.password"); err != nil || value != "hunter2" {
+		t.Errorf("Get = %q, %v; attendu hunter2", value, err)
+	}
+	if _, err := reopened.Get("smtp.oauth2.refresh_token"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("secret supprimé: %v, attendu ErrSecretNotFound", err)
+	}
+}
+
+func TestEncryptedFileStoreWrongPassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+	store := newEncryptedFileStore(path)
+	if err := store.Unlock(testPassphrase); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set("smtp.password", "hunter2"); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened := newEncryptedFileStore(path)
+	if err := reopened.Unlock("wrong passphrase"); err == nil {
+		t.Fatal("mauvaise phrase de passe acceptée")
+	}
+	if !reopened.Locked() {
+		t.Error("stockage déverrouillé après une mauvaise phrase de passe")
+	}
+
+	// Contenu altéré: l'authentification échoue même avec la bonne phrase de passe
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := strings.Replace(string(data), `"data": "`, `"data": "AA`, 1)
+	if err := os.WriteFile(path, []byte(tampered), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := newEncryptedFileStore(path).Unlock(testPassphrase); err == nil {
+		t.Error("fichier altéré accepté")
+	}
+}
+
+func TestSecretVaultReferences(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+	v := newSecretVault(newEncryptedFileStore(path))
+
+	s := DefaultSettings()
+	s.SMTPConfig.Password = "hunter2"
+	s.SMTPConfig.OAuth2 = &OAuth2Config{TokenURL: "https://oauth.example.com/token", RefreshToken: "1//token"}
+
+	// Coffre verrouillé: les références sont écrites, les secrets gardés en mémoire
+	stored, err := v.externalize(s)
+	if err != nil {
+		t.Fatalf("externalize verrouillé: %v", err)
+	}
+	if want := secretRef(SecretStoreFile, "smtp.password"); stored.SMTPConfig.Password != want {
+		t.Errorf("password = %q, attendu %q", stored.SMTPConfig.Password, want)
+	}
+	if s.SMTPConfig.OAuth2.RefreshToken != "1//token" {
+		t.Error("settings d'origine modifiés")
+	}
+	if len(v.unsaved) != 2 {
+		t.Errorf("%d secrets en attente, attendu 2", len(v.unsaved))
+	}
+
+	// Relecture du fichier avant le déverrouillage: secrets repris de la mémoire
+	reloaded := stored
+	oauth := *stored.SMTPConfig.OAuth2
+	reloaded.SMTPConfig.OAuth2 = &oauth
+	if v.resolve(&reloaded) {
+		t.Error("références signalées comme secrets en clair")
+	}
+	if reloaded.SMTPConfig.Password != "hunter2" || reloaded.SMTPConfig.OAuth2.RefreshToken != "1//token" {
+		t.Errorf("secrets non résolus: %+v", reloaded.SMTPConfig)
+	}
+
+	// Déverrouillage: les secrets en attente sont enregistrés dans le fichier
+	if err := v.unlock(testPassphrase, &reloaded); err != nil {
+		t.Fatal(err)
+	}
+	if len(v.unsaved) != 0 {
+		t.Errorf("%d secrets encore en attente après déverrouillage", len(v.unsaved))
+	}
+
+	// Nouveau démarrage: références non résolues tant que le fichier est verrouillé
+	restarted := newSecretVault(newEncryptedFileStore(path))
+	fromDisk := stored
+	oauth = *stored.SMTPConfig.OAuth2
+	fromDisk.SMTPConfig.OAuth2 = &oauth
+	restarted.resolve(&fromDisk)
+	if fromDisk.SMTPConfig.Password != "" || len(restarted.pending) != 2 {
+		t.Fatalf("coffre verrouillé: password %q, %d en attente", fromDisk.SMTPConfig.Password, len(restarted.pending))
+	}
+
+	// Une sauvegarde pendant le verrouillage conserve les références
+	again, err := restarted.externalize(fromDisk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.SMTPConfig.Password != stored.SMTPConfig.Password {
+		t.Errorf("référence perdue: %q", again.SMTPConfig.Password)
+	}
+
+	if err := restarted.unlock(testPassphrase, &fromDisk); err != nil {
+		t.Fatal(err)
+	}
+	if fromDisk.SMTPConfig.Password != "hunter2" || fromDisk.SMTPConfig.OAuth2.RefreshToken != "1//token" {
+		t.Errorf("secrets après déverrouillage: %+v", fromDisk.SMTPConfig)
+	}
+	if len(restarted.pending) != 0 {
+		t.Errorf("%d références encore en attente", len(restarted.pending))
+	}
+
+	// Référence vers un stockage inconnu: conservée pour ne pas perdre le secret
+	broken := DefaultSettings()
+	broken.SMTPConfig.Password = "secret://nowhere/smtp.password"
+	restarted.resolve(&broken)
+	if broken.SMTPConfig.Password != "" || restarted.pending["smtp.password"] != "secret://nowhere/smtp.password" {
+		t.Errorf("référence invalide: password %q, pending %v", broken.SMTPConfig.Password, restarted.pending)
+	}
+}
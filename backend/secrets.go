@@ -0,0 +1,340 @@
+// Package backend - Stockage des secrets (mots de passe SMTP, jetons OAuth2)
+// Ce fichier range les secrets des settings dans le trousseau du système
+// (Secret Service, Trousseau macOS, Gestionnaire d'identification Windows)
+// ou, à défaut, dans un fichier chiffré par une phrase de passe maîtresse.
+// settings.json ne contient plus que des références "secret://<stockage>/<clé>"
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Erreurs communes des stockages de secrets
+var (
+	ErrSecretNotFound    = errors.New("secret introuvable")
+	ErrSecretStoreLocked = errors.New("coffre de secrets verrouillé: phrase de passe maîtresse requise")
+)
+
+// Stockages de secrets disponibles
+const (
+	SecretStoreKeyring = "keyring" // Trousseau du système d'exploitation
+	SecretStoreFile    = "file"    // Fichier chiffré (phrase de passe maîtresse)
+)
+
+// secretRefPrefix - Préfixe des références de secrets dans settings.json
+const secretRefPrefix = "secret://"
+
+// secretServiceName - Nom de l'application dans le trousseau du système
+const secretServiceName = "monitoring_serv"
+
+// masterPassphraseEnv - Variable d'environnement déverrouillant le fichier chiffré au démarrage
+const masterPassphraseEnv = "MONITORING_SERV_PASSPHRASE"
+
+// SecretStore - Stockage de secrets par clé
+type SecretStore interface {
+	Get(key string) (string, error) // ErrSecretNotFound si absent
+	Set(key, value string) error
+	Delete(key string) error // Sans erreur si absent
+}
+
+// SecretStoreStatus - État du stockage des secrets (affiché dans les paramètres)
+type SecretStoreStatus struct {
+	Backend     string `json:"backend"`     // keyring | file
+	Locked      bool   `json:"locked"`      // Fichier chiffré en attente de la phrase de passe
+	Initialized bool   `json:"initialized"` // Fichier chiffré déjà créé
+	Pending     int    `json:"pending"`     // Secrets référencés mais pas encore déchiffrés
+	Unsaved     int    `json:"unsaved"`     // Secrets saisis en attente du déverrouillage pour être enregistrés
+}
+
+// secretVault - Stockages disponibles et secrets non résolus
+type secretVault struct {
+	mutex   sync.Mutex
+	keyring SecretStore         // nil si le trousseau du système est indisponible
+	file    *encryptedFileStore // Repli chiffré, toujours disponible
+	pending map[string]string   // clé → référence non résolue (coffre verrouillé)
+	unsaved map[string]string   // clé → secret saisi, enregistré au déverrouillage
+}
+
+var (
+	vault     *secretVault
+	vaultOnce sync.Once
+)
+
+// getVault - Initialise les stockages au premier accès
+func getVault() *secretVault {
+	vaultOnce.Do(func() {
+		path, err := secretsFilePath()
+		if err != nil {
+			log.Printf("⚠️ Chemin du fichier de secrets indisponible: %s", err)
+		}
+		vault = newSecretVault(newEncryptedFileStore(path))
+		if passphrase := os.Getenv(masterPassphraseEnv); passphrase != "" {
+			if err := vault.file.Unlock(passphrase); err != nil {
+				log.Printf("⚠️ Déverrouillage du fichier de secrets impossible (%s): %s", masterPassphraseEnv, err)
+			}
+		}
+
+		keyring, err := newKeyringStore()
+		if err != nil {
+			log.Printf("🔐 Trousseau du système indisponible (%s), repli sur le fichier chiffré", err)
+			return
+		}
+		vault.keyring = keyring
+	})
+	return vault
+}
+
+// newSecretVault - Coffre utilisant le fichier chiffré (trousseau ajouté ensuite)
+func newSecretVault(file *encryptedFileStore) *secretVault {
+	return &secretVault{
+		file:    file,
+		pending: make(map[string]string),
+		unsaved: make(map[string]string),
+	}
+}
+
+// primary - Stockage utilisé pour les nouveaux secrets
+func (v *secretVault) primary() (string, SecretStore) {
+	if v.keyring != nil {
+		return SecretStoreKeyring, v.keyring
+	}
+	return SecretStoreFile, v.file
+}
+
+// store - Stockage désigné par une référence
+func (v *secretVault) store(name string) (SecretStore, error) {
+	switch name {
+	case SecretStoreKeyring:
+		if v.keyring == nil {
+			return nil, fmt.Errorf("trousseau du système indisponible")
+		}
+		return v.keyring, nil
+	case SecretStoreFile:
+		return v.file, nil
+	}
+	return nil, fmt.Errorf("stockage de secrets inconnu: %s", name)
+}
+
+// secretSlot - Champ secret des settings
+type secretSlot struct {
+	key   string  // Clé dans le stockage (ex: "smtp.password")
+	value *string // Valeur en clair ou référence
+}
+
+// secretSlots - Champs secrets des settings
+func secretSlots(s *Settings) []secretSlot {
+	slots := []secretSlot{{"smtp.password", &s.SMTPConfig.Password}}
+	if s.SMTPConfig.OAuth2 != nil {
+		slots = append(slots,
+			secretSlot{"smtp.oauth2.client_secret", &s.SMTPConfig.OAuth2.ClientSecret},
+			secretSlot{"smtp.oauth2.refresh_token", &s.SMTPConfig.OAuth2.RefreshToken},
+		)
+	}
+	return slots
+}
+
+// WithoutSecrets - Copie des settings sans aucun secret (export)
+func (s Settings) WithoutSecrets() Settings {
+	if s.SMTPConfig.OAuth2 != nil {
+		oauth := *s.SMTPConfig.OAuth2
+		s.SMTPConfig.OAuth2 = &oauth
+	}
+	for _, slot := range secretSlots(&s) {
+		*slot.value = ""
+	}
+	return s
+}
+
+// KeepSecrets - Copie des settings dont les secrets vides reprennent ceux de
+// current (import de settings exportés sans leurs secrets)
+func (s Settings) KeepSecrets(current Settings) Settings {
+	if s.SMTPConfig.OAuth2 != nil {
+		oauth := *s.SMTPConfig.OAuth2
+		s.SMTPConfig.OAuth2 = &oauth
+	}
+	known := make(map[string]string)
+	for _, slot := range secretSlots(&current) {
+		known[slot.key] = *slot.value
+	}
+	for _, slot := range secretSlots(&s) {
+		if *slot.value == "" {
+			*slot.value = known[slot.key]
+		}
+	}
+	return s
+}
+
+// secretRef - Référence d'un secret rangé dans un stockage
+func secretRef(store, key string) string {
+	return secretRefPrefix + store + "/" + key
+}
+
+// parseSecretRef - Décompose une référence "secret://<stockage>/<clé>"
+func parseSecretRef(value string) (store, key string, ok bool) {
+	rest, found := strings.CutPrefix(value, secretRefPrefix)
+	if !found {
+		return "", "", false
+	}
+	return strings.Cut(rest, "/")
+}
+
+// resolveSecrets - Remplace les références des settings par les secrets
+// Renvoie true si des secrets en clair (anciennes versions) restent à déplacer
+func resolveSecrets(s *Settings) bool {
+	return getVault().resolve(s)
+}
+
+// resolve - Remplace les références de s par les secrets des stockages
+func (v *secretVault) resolve(s *Settings) bool {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	plaintext := false
+	for _, slot := range secretSlots(s) {
+		value := *slot.value
+		storeName, key, ok := parseSecretRef(value)
+		if !ok {
+			plaintext = plaintext || value != ""
+			continue
+		}
+
+		*slot.value = ""
+		if secret, ok := v.unsaved[slot.key]; ok {
+			*slot.value = secret // Saisi pendant que le coffre était verrouillé
+			continue
+		}
+		store, err := v.store(storeName)
+		if err == nil {
+			var secret string
+			if secret, err = store.Get(key); err == nil {
+				*slot.value = secret
+				delete(v.pending, slot.key)
+				continue
+			}
+		}
+		if !errors.Is(err, ErrSecretStoreLocked) {
+			log.Printf("⚠️ Secret %s illisible: %s", slot.key, err)
+		}
+		// Conserver la référence pour ne pas perdre le secret à la prochaine sauvegarde
+		v.pending[slot.key] = value
+	}
+	return plaintext
+}
+
+// externalizeSecrets - Range les secrets dans le stockage et renvoie une copie
+// des settings ne contenant que les références (à écrire sur le disque)
+func externalizeSecrets(s Settings) (Settings, error) {
+	return getVault().externalize(s)
+}
+
+// externalize - Range les secrets de s dans le stockage principal
+// Coffre verrouillé: le secret est gardé en mémoire et enregistré au
+// déverrouillage, le fichier reçoit déjà sa future référence
+func (v *secretVault) externalize(s Settings) (Settings, error) {
+	if s.SMTPConfig.OAuth2 != nil {
+		oauth := *s.SMTPConfig.OAuth2
+		s.SMTPConfig.OAuth2 = &oauth
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	storeName, store := v.primary()
+	for _, slot := range secretSlots(&s) {
+		value := *slot.value
+		if _, _, ok := parseSecretRef(value); ok {
+			continue
+		}
+		if value == "" {
+			if ref, ok := v.pending[slot.key]; ok {
+				*slot.value = ref // Secret encore verrouillé: référence conservée
+				continue
+			}
+			delete(v.unsaved, slot.key)
+			if err := store.Delete(slot.key); err != nil && !errors.Is(err, ErrSecretStoreLocked) {
+				log.Printf("⚠️ Suppression du secret %s impossible: %s", slot.key, err)
+			}
+			continue
+		}
+
+		if current, err := store.Get(slot.key); err != nil || current != value {
+			err := store.Set(slot.key, value)
+			if errors.Is(err, ErrSecretStoreLocked) {
+				if v.unsaved[slot.key] != value {
+					log.Printf("🔐 Secret %s gardé en mémoire jusqu'au déverrouillage du fichier de secrets", slot.key)
+				}
+				v.unsaved[slot.key] = value
+				delete(v.pending, slot.key)
+				*slot.value = secretRef(storeName, slot.key)
+				continue
+			}
+			if err != nil {
+				return s, fmt.Errorf("enregistrement du secret %s impossible: %w", slot.key, err)
+			}
+		}
+		delete(v.unsaved, slot.key)
+		delete(v.pending, slot.key)
+		*slot.value = secretRef(storeName, slot.key)
+	}
+	return s, nil
+}
+
+// GetSecretStoreStatus - État du stockage des secrets
+func GetSecretStoreStatus() SecretStoreStatus {
+	v := getVault()
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	backend, _ := v.primary()
+	return SecretStoreStatus{
+		Backend:     backend,
+		Locked:      v.file.Locked(),
+		Initialized: v.file.Exists(),
+		Pending:     len(v.pending),
+		Unsaved:     len(v.unsaved),
+	}
+}
+
+// UnlockSecrets - Déverrouille le fichier chiffré avec la phrase de passe maîtresse,
+// y enregistre les secrets saisis entre-temps et complète les secrets encore
+// référencés dans s. Le fichier est créé au premier secret enregistré si la
+// phrase de passe est nouvelle
+func UnlockSecrets(passphrase string, s *Settings) error {
+	return getVault().unlock(passphrase, s)
+}
+
+// unlock - Déverrouille le fichier chiffré et complète s
+func (v *secretVault) unlock(passphrase string, s *Settings) error {
+	if err := v.file.Unlock(passphrase); err != nil {
+		return err
+	}
+
+	v.mutex.Lock()
+	keys := make([]string, 0, len(v.unsaved))
+	for key := range v.unsaved {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := v.file.Set(key, v.unsaved[key]); err != nil {
+			v.mutex.Unlock()
+			return fmt.Errorf("enregistrement du secret %s impossible: %w", key, err)
+		}
+		delete(v.unsaved, key)
+	}
+	for _, slot := range secretSlots(s) {
+		if ref, ok := v.pending[slot.key]; ok && *slot.value == "" {
+			*slot.value = ref
+		}
+	}
+	v.mutex.Unlock()
+
+	v.resolve(s)
+	return nil
+}
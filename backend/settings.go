@@ -5,7 +5,15 @@ package backend
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/mail"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Settings contient toutes les préférences utilisateur que l’on persiste
@@ -15,16 +23,72 @@ type Settings struct {
 	NotificationCooldown int        `json:"notificationCooldown"` // en minutes
 	RefreshInterval      int        `json:"refreshInterval"`      // en secondes
 	UserEmail            string     `json:"userEmail"`            // adresse email pour les notifications
+	CriticalFailures     int        `json:"criticalFailures"`     // échecs consécutifs avant alerte critique
+	CriticalRepeat       int        `json:"criticalRepeat"`       // répétition de l'alerte critique tous les N échecs (0 = jamais)
+	CriticalAfterMinutes int        `json:"criticalAfterMinutes"` // minutes de panne avant alerte critique (0 = désactivé)
 	SMTPConfig           SMTPConfig `json:"smtp_config"`
+
+	Recipients         RecipientList            `json:"recipients"`                   // destinataires supplémentaires (To, CC, BCC)
+	SeverityRecipients map[string]RecipientList `json:"severityRecipients,omitempty"` // destinataires spécifiques par type d'alerte
+	EmailTemplates     map[string]EmailTemplate `json:"emailTemplates,omitempty"`     // modèles d'email personnalisés par type
+	RelayAllowList     []string                 `json:"relayAllowList,omitempty"`     // adresses ou domaines autorisés en plus des destinataires
+	HeartbeatSMTPAddr  string                   `json:"heartbeatSmtpAddr,omitempty"`  // adresse d'écoute SMTP des emails de heartbeat (ex: "0.0.0.0:2525", vide = désactivé)
+	PushListenAddr     string                   `json:"pushListenAddr,omitempty"`     // adresse d'écoute HTTP des pings des checks push (ex: "0.0.0.0:8099", vide = désactivé)
+	PushBaseURL        string                   `json:"pushBaseUrl,omitempty"`        // URL publique des pings (ex: "https://monitor.example.com"), déduite de PushListenAddr si vide
+
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"` // fenêtres de maintenance (alertes suspendues)
+
+	QuietHours       QuietHours            `json:"quietHours"`                 // heures calmes (nuits, week-ends): critiques seules ou résumé
+	QuietHoursByType map[string]QuietHours `json:"quietHoursByType,omitempty"` // surcharge des heures calmes par type de check (http, tcp, ping...)
 }
 
 type SMTPConfig struct {
-	Host     string `json:"host"`
-	Port     int    `json:"port"`
-	Username string `json:"username"`
-	Password string `json:"password"`
-	From     string `json:"from"`
-	TLS      bool   `json:"tls"`
+	Host     string        `json:"host"`
+	Port     int           `json:"port"`
+	Username string        `json:"username"`
+	Password string        `json:"password"` // mot de passe, ou jeton d'accès en xoauth2 sans renouvellement
+	From     string        `json:"from"`
+	Auth     string        `json:"auth,omitempty"`   // "plain" (défaut) | "login" | "cram-md5" | "xoauth2" | "none"
+	OAuth2   *OAuth2Config `json:"oauth2,omitempty"` // renouvellement automatique du jeton xoauth2
+
+	TLSMode    string `json:"tls_mode"`              // "none" | "opportunistic" | "starttls" (défaut) | "implicit"
+	CACert     string `json:"ca_cert,omitempty"`     // autorité de certification (PEM ou chemin du fichier)
+	SkipVerify bool   `json:"skip_verify,omitempty"` // ne pas vérifier le certificat du serveur
+}
+
+// AuthMechanism renvoie le mécanisme d'authentification effectif
+func (c SMTPConfig) AuthMechanism() string {
+	if c.Auth == "" {
+		return SMTPAuthPlain
+	}
+	return c.Auth
+}
+
+// ErrSMTPNotConfigured - Relais vers le serveur externe impossible en l'état
+var ErrSMTPNotConfigured = errors.New("configuration SMTP incomplète, email non envoyé")
+
+// CheckRelay vérifie que la configuration suffit à relayer un email: serveur
+// renseigné et, sauf sans authentification, identifiant renseigné
+func (c SMTPConfig) CheckRelay() error {
+	if c.Host == "" || (c.Username == "" && c.AuthMechanism() != SMTPAuthNone) {
+		return ErrSMTPNotConfigured
+	}
+	return nil
+}
+
+// Secret renvoie le mot de passe ou, en xoauth2, un jeton d'accès valide
+func (c SMTPConfig) Secret() (string, error) {
+	if c.AuthMechanism() == SMTPAuthXOAUTH2 && c.OAuth2.Enabled() {
+		return c.OAuth2.AccessToken()
+	}
+	return c.Password, nil
+}
+
+// ValidateSMTPConfig vérifie l'authentification et le chiffrement SMTP
+func ValidateSMTPConfig(c SMTPConfig) error {
+	v := &settingsValidator{}
+	v.smtp("smtp_config", c, false)
+	return v.err()
 }
 
 // defaultSettings renvoie une struct Settings avec les valeurs par défaut
@@ -35,47 +99,210 @@ func DefaultSettings() Settings {
 		NotificationCooldown: 10,
 		RefreshInterval:      60,
 		UserEmail:            "",
+		CriticalFailures:     3,
+		CriticalRepeat:       5,
+		CriticalAfterMinutes: 0,
+	}
+}
+
+// CriticalPolicy renvoie les seuils globaux d'alerte critique définis dans les settings
+func (s Settings) CriticalPolicy() CriticalPolicy {
+	return CriticalPolicy{
+		AfterFailures: s.CriticalFailures,
+		RepeatEvery:   s.CriticalRepeat,
+		AfterDowntime: time.Duration(s.CriticalAfterMinutes) * time.Minute,
 	}
 }
 
 // settingsFilePath returns the path to the settings file
 func settingsFilePath() (string, error) {
-	return "./settings.json", nil
+	return ConfigFilePath("settings.json")
 }
 
 // loadSettings lit le fichier JSON si présent, sinon renvoie les valeurs par défaut
 func LoadSettings() (Settings, error) {
-	path, err := settingsFilePath()
-	if err != nil {
-		return DefaultSettings(), err
-	}
-
-	// Si le fichier n'existe pas, on renvoie les valeurs par défaut
-	if _, err := os.Stat(path); os.IsNotExist(err) {
+	s, err := loadSettings(ConfigBackups)
+	if os.IsNotExist(err) {
+		// Si le fichier n'existe pas, on renvoie les valeurs par défaut
 		return DefaultSettings(), nil
 	}
+	return s, err
+}
+
+// ReloadSettings relit settings.json modifié hors de l'application
+// Sans repli sur les sauvegardes: un fichier absent ou invalide est signalé
+// et la configuration en cours doit être conservée
+func ReloadSettings() (Settings, error) {
+	return loadSettings(0)
+}
 
-	data, err := os.ReadFile(path)
+// loadSettings lit settings.json (ou l'une de ses keep sauvegardes)
+func loadSettings(keep int) (Settings, error) {
+	path, err := settingsFilePath()
 	if err != nil {
 		return DefaultSettings(), err
 	}
 
+	// Partir des valeurs par défaut pour que les champs absents du fichier
+	// gardent une valeur cohérente. Fichier corrompu: reprendre la sauvegarde
+	// valide la plus récente; ancien schéma: mise à niveau
 	var s Settings
-	if err := json.Unmarshal(data, &s); err != nil {
+	err = LoadConfigFile(path, ConfigKindSettings, keep, func(data json.RawMessage) error {
+		s = DefaultSettings()
+		return json.Unmarshal(data, &s)
+	})
+	if err != nil {
 		return DefaultSettings(), err
 	}
+
+	// Remplacer les références par les secrets (trousseau ou fichier chiffré)
+	if resolveSecrets(&s) {
+		// Secrets en clair d'une ancienne version: les déplacer hors du fichier
+		if err := SaveSettings(s); err != nil {
+			log.Printf("⚠️ Secrets laissés en clair dans %s: %s", path, err)
+		} else {
+			log.Printf("🔐 Secrets déplacés hors de %s", path)
+		}
+	}
 	return s, nil
 }
 
 // saveSettings écrit les préférences dans le fichier JSON
+// Les secrets sont rangés à part, le fichier ne contient que leurs références
 func SaveSettings(s Settings) error {
 	path, err := settingsFilePath()
 	if err != nil {
 		return err
 	}
-	data, err := json.MarshalIndent(s, "", "  ")
+	stored, err := externalizeSecrets(s)
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, data, 0o644)
+	data, err := EncodeConfig(ConfigKindSettings, stored)
+	if err != nil {
+		return err
+	}
+	// Écriture atomique avec sauvegardes: un arrêt brutal ne corrompt pas les réglages
+	return WriteFileWithBackups(path, data, 0o600, ConfigBackups)
+}
+
+// RecipientList - Destinataires d'un email (To, CC, BCC)
+type RecipientList struct {
+	To  []string `json:"to"`
+	Cc  []string `json:"cc,omitempty"`
+	Bcc []string `json:"bcc,omitempty"`
+}
+
+// IsEmpty indique si la liste ne contient aucune adresse
+func (r RecipientList) IsEmpty() bool {
+	return len(r.To) == 0 && len(r.Cc) == 0 && len(r.Bcc) == 0
+}
+
+// RecipientsFor renvoie les destinataires d'un type d'email (DOWN, UP, CRITICAL, SUMMARY, DIGEST)
+// Une liste spécifique au type remplace la liste globale ; l'adresse principale
+// (UserEmail) est toujours ajoutée aux destinataires globaux
+func (s Settings) RecipientsFor(kind string) RecipientList {
+	if specific, ok := s.SeverityRecipients[kind]; ok && !specific.IsEmpty() {
+		return specific
+	}
+
+	recipients := RecipientList{
+		Cc:  s.Recipients.Cc,
+		Bcc: s.Recipients.Bcc,
+	}
+	if s.UserEmail != "" {
+		recipients.To = append(recipients.To, s.UserEmail)
+	}
+	for _, addr := range s.Recipients.To {
+		if !strings.EqualFold(addr, s.UserEmail) {
+			recipients.To = append(recipients.To, addr)
+		}
+	}
+	return recipients
+}
+
+// validateListenAddr vérifie une adresse d'écoute "hôte:port" (vide = désactivé)
+func validateListenAddr(label, addr string) error {
+	if addr == "" {
+		return nil
+	}
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("adresse d'écoute %s invalide %q: %s", label, addr, err)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n <= 0 || n > 65535 {
+		return fmt.Errorf("port d'écoute %s invalide: %s", label, port)
+	}
+	return nil
+}
+
+// PushURLBase renvoie l'URL de base des pings des checks push (sans "/" final)
+// Sans URL publique configurée, elle est déduite de l'adresse d'écoute
+func (s Settings) PushURLBase() string {
+	if s.PushBaseURL != "" {
+		return strings.TrimRight(s.PushBaseURL, "/")
+	}
+	host, port, err := net.SplitHostPort(s.PushListenAddr)
+	if err != nil {
+		return ""
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	return "http://" + net.JoinHostPort(host, port)
+}
+
+// RelayAllowed indique si le relais SMTP embarqué peut transmettre à une adresse
+// Sont autorisés les destinataires configurés et les entrées de RelayAllowList
+// (adresse complète, "@domaine.com" ou "domaine.com")
+func (s Settings) RelayAllowed(addr string) bool {
+	addr = strings.ToLower(strings.TrimSpace(addr))
+	if addr == "" {
+		return false
+	}
+
+	allowed := []string{s.UserEmail}
+	lists := []RecipientList{s.Recipients}
+	for _, list := range s.SeverityRecipients {
+		lists = append(lists, list)
+	}
+	for _, list := range lists {
+		allowed = append(allowed, list.To...)
+		allowed = append(allowed, list.Cc...)
+		allowed = append(allowed, list.Bcc...)
+	}
+	for _, candidate := range allowed {
+		if strings.EqualFold(strings.TrimSpace(candidate), addr) {
+			return true
+		}
+	}
+
+	domain := addr[strings.LastIndex(addr, "@")+1:]
+	for _, entry := range s.RelayAllowList {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "":
+			continue
+		case strings.HasPrefix(entry, "@"):
+			if domain == entry[1:] {
+				return true
+			}
+		case strings.Contains(entry, "@"):
+			if addr == entry {
+				return true
+			}
+		case domain == entry:
+			return true
+		}
+	}
+	return false
+}
+
+// validateAddress vérifie qu'une chaîne est une adresse email simple
+func validateAddress(addr string) error {
+	parsed, err := mail.ParseAddress(addr)
+	if err != nil || parsed.Address != strings.TrimSpace(addr) {
+		return fmt.Errorf("adresse email invalide: %q", addr)
+	}
+	return nil
 }
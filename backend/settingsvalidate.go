@@ -0,0 +1,287 @@
+// Package backend - Validation des paramètres utilisateur
+// Ce fichier vérifie des Settings complets avant leur application et renvoie
+// une erreur par champ (chemin JSON + message) que l'interface affiche à côté
+// de la saisie correspondante
+package backend
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"net/url"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Bornes des réglages numériques (identiques aux champs de l'interface)
+const (
+	minRefreshInterval  = 10   // secondes
+	maxRefreshInterval  = 3600 // secondes
+	maxCooldownMinutes  = 1440
+	maxCriticalCount    = 1000
+	maxCriticalDowntime = 1440 // minutes
+)
+
+// FieldError - Erreur de validation d'un champ des settings
+type FieldError struct {
+	Field   string `json:"field"`   // Chemin JSON du champ (ex: "smtp_config.port", "recipients.cc[1]")
+	Message string `json:"message"` // Message affiché à côté du champ
+}
+
+// ValidationError - Settings refusés, avec le détail par champ
+type ValidationError struct {
+	Fields []FieldError `json:"fields"`
+}
+
+// Error - Messages de tous les champs invalides
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		messages = append(messages, field.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// settingsValidator - Accumule les erreurs de validation par champ
+type settingsValidator struct {
+	fields []FieldError
+}
+
+// add - Ajoute une erreur sur un champ
+func (v *settingsValidator) add(field, format string, args ...any) {
+	v.fields = append(v.fields, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// err - Erreur regroupant les champs invalides (nil si tout est valide)
+func (v *settingsValidator) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: v.fields}
+}
+
+// ValidateSettings - Vérifie tous les champs des settings
+// Renvoie une liste vide si la configuration peut être appliquée
+func ValidateSettings(s Settings) []FieldError {
+	v := &settingsValidator{}
+	v.settings(s)
+	if v.fields == nil {
+		return []FieldError{}
+	}
+	return v.fields
+}
+
+// ValidateMaintenanceWindows - Vérifie des fenêtres de maintenance seules
+// (chemins des champs relatifs à "maintenanceWindows")
+func ValidateMaintenanceWindows(windows []MaintenanceWindow) []FieldError {
+	v := &settingsValidator{}
+	v.maintenanceWindows("maintenanceWindows", windows)
+	if v.fields == nil {
+		return []FieldError{}
+	}
+	return v.fields
+}
+
+// Validate - Erreur *ValidationError si les settings ne peuvent pas être appliqués
+func (s Settings) Validate() error {
+	v := &settingsValidator{}
+	v.settings(s)
+	return v.err()
+}
+
+// settings - Vérifie les settings complets
+func (v *settingsValidator) settings(s Settings) {
+	switch s.Theme {
+	case "auto", "light", "dark":
+	default:
+		v.add("theme", "thème inconnu: %q", s.Theme)
+	}
+	switch s.NotificationMode {
+	case "inapp", "email", "none":
+	default:
+		v.add("notificationMode", "mode de notification inconnu: %q", s.NotificationMode)
+	}
+
+	v.intRange("notificationCooldown", "délai entre notifications", s.NotificationCooldown, 0, maxCooldownMinutes)
+	v.intRange("refreshInterval", "intervalle de rafraîchissement", s.RefreshInterval, minRefreshInterval, maxRefreshInterval)
+	v.intRange("criticalFailures", "échecs avant alerte critique", s.CriticalFailures, 0, maxCriticalCount)
+	v.intRange("criticalRepeat", "répétition de l'alerte critique", s.CriticalRepeat, 0, maxCriticalCount)
+	v.intRange("criticalAfterMinutes", "durée de panne avant alerte critique", s.CriticalAfterMinutes, 0, maxCriticalDowntime)
+
+	if s.UserEmail != "" {
+		v.address("userEmail", s.UserEmail)
+	} else if s.NotificationMode == "email" && len(s.Recipients.To) == 0 {
+		v.add("userEmail", "adresse email requise pour les notifications par email")
+	}
+	v.recipients("recipients", s.Recipients)
+	kinds := make([]string, 0, len(s.SeverityRecipients))
+	for kind := range s.SeverityRecipients {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		field := "severityRecipients." + kind
+		if _, ok := DefaultEmailTemplates()[kind]; !ok {
+			v.add(field, "type d'alerte inconnu pour les destinataires: %s", kind)
+			continue
+		}
+		list := s.SeverityRecipients[kind]
+		v.recipients(field, list)
+		// Une liste spécifique remplace la liste globale: sans destinataire visible,
+		// l'email partirait uniquement en copie cachée
+		if len(list.To) == 0 && len(list.Cc) == 0 && len(list.Bcc) > 0 {
+			v.add(field+".to", "au moins un destinataire To ou CC est requis en plus des copies cachées")
+		}
+	}
+	for i, entry := range s.RelayAllowList {
+		field := fmt.Sprintf("relayAllowList[%d]", i)
+		entry = strings.TrimSpace(entry)
+		if entry == "" || strings.ContainsAny(entry, " ,;") {
+			v.add(field, "entrée de liste blanche invalide: %q", entry)
+		} else if at := strings.Index(entry, "@"); at > 0 {
+			v.address(field, entry)
+		}
+	}
+
+	if err := validateListenAddr("SMTP", s.HeartbeatSMTPAddr); err != nil {
+		v.add("heartbeatSmtpAddr", "%s", err)
+	}
+	if err := validateListenAddr("HTTP", s.PushListenAddr); err != nil {
+		v.add("pushListenAddr", "%s", err)
+	}
+	if s.PushBaseURL != "" {
+		u, err := url.Parse(s.PushBaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			v.add("pushBaseUrl", "URL publique des pings invalide: %s", s.PushBaseURL)
+		}
+	}
+
+	v.smtp("smtp_config", s.SMTPConfig, s.NotificationMode == "email")
+	v.emailTemplates("emailTemplates", s.EmailTemplates)
+	v.maintenanceWindows("maintenanceWindows", s.MaintenanceWindows)
+	v.quietHours("quietHours", s.QuietHours)
+	v.quietHoursByType("quietHoursByType", s.QuietHoursByType)
+}
+
+// maintenanceWindows - Vérifie la planification de chaque fenêtre de maintenance
+func (v *settingsValidator) maintenanceWindows(field string, windows []MaintenanceWindow) {
+	names := make(map[string]bool, len(windows))
+	for i, window := range windows {
+		prefix := fmt.Sprintf("%s[%d]", field, i)
+		if _, err := window.compile(); err != nil {
+			e := err.(*maintenanceError)
+			v.add(prefix+"."+e.field, "%s", e.message)
+			continue
+		}
+		name := strings.ToLower(strings.TrimSpace(window.Name))
+		if names[name] {
+			v.add(prefix+".name", "fenêtre de maintenance en double: %s", window.Name)
+		}
+		names[name] = true
+	}
+}
+
+// quietHours - Vérifie des heures calmes (global ou surcharge par type)
+func (v *settingsValidator) quietHours(field string, quiet QuietHours) {
+	if err := quiet.Validate(); err != nil {
+		e := err.(*maintenanceError)
+		v.add(field+"."+e.field, "%s", e.message)
+	}
+}
+
+// quietHoursByType - Vérifie les surcharges des heures calmes par type de check
+func (v *settingsValidator) quietHoursByType(field string, byType map[string]QuietHours) {
+	types := make([]string, 0, len(byType))
+	for checkType := range byType {
+		types = append(types, checkType)
+	}
+	sort.Strings(types)
+	for _, checkType := range types {
+		if !quietCheckTypes[checkType] {
+			v.add(field+"."+checkType, "type de check inconnu pour les heures calmes: %s", checkType)
+			continue
+		}
+		v.quietHours(field+"."+checkType, byType[checkType])
+	}
+}
+
+// intRange - Vérifie qu'un entier est compris entre min et max
+func (v *settingsValidator) intRange(field, label string, value, min, max int) {
+	if value < min || value > max {
+		v.add(field, "%s hors limites: %d (de %d à %d)", label, value, min, max)
+	}
+}
+
+// address - Vérifie une adresse email simple
+func (v *settingsValidator) address(field, addr string) {
+	if err := validateAddress(addr); err != nil {
+		v.add(field, "%s", err)
+	}
+}
+
+// recipients - Vérifie les adresses d'une liste de destinataires
+func (v *settingsValidator) recipients(field string, list RecipientList) {
+	groups := []struct {
+		name  string
+		addrs []string
+	}{{"to", list.To}, {"cc", list.Cc}, {"bcc", list.Bcc}}
+	for _, group := range groups {
+		for i, addr := range group.addrs {
+			v.address(fmt.Sprintf("%s.%s[%d]", field, group.name, i), addr)
+		}
+	}
+}
+
+// smtp - Vérifie la configuration SMTP; serveur et port ne sont requis que
+// lorsque les notifications partent par email
+func (v *settingsValidator) smtp(field string, c SMTPConfig, required bool) {
+	if c.Host == "" && required {
+		v.add(field+".host", "serveur SMTP requis pour les notifications par email")
+	}
+	if (c.Host != "" || required) && (c.Port <= 0 || c.Port > 65535) {
+		v.add(field+".port", "port SMTP invalide: %d", c.Port)
+	}
+	if c.From != "" {
+		v.address(field+".from", c.From)
+	}
+	if !validSMTPAuth(c.Auth) {
+		v.add(field+".auth", "authentification SMTP inconnue: %s", c.Auth)
+	}
+	if !validTLSMode(c.TLSMode) {
+		v.add(field+".tls_mode", "mode TLS inconnu: %s", c.TLSMode)
+	} else if _, err := c.TLSConfig(); err != nil {
+		v.add(field+".ca_cert", "%s", err)
+	}
+	// Renouvellement commencé mais incomplet (le préréglage ne fournit que token_url)
+	if c.AuthMechanism() == SMTPAuthXOAUTH2 && c.OAuth2 != nil && !c.OAuth2.Enabled() &&
+		(c.OAuth2.ClientID != "" || c.OAuth2.RefreshToken != "") {
+		v.add(field+".oauth2", "OAuth2: token_url, client_id et refresh_token sont requis")
+	}
+}
+
+// emailTemplates - Vérifie la syntaxe des modèles personnalisés
+func (v *settingsValidator) emailTemplates(field string, templates map[string]EmailTemplate) {
+	defaults := DefaultEmailTemplates()
+	kinds := make([]string, 0, len(templates))
+	for kind := range templates {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		tpl := templates[kind]
+		prefix := field + "." + kind
+		if _, ok := defaults[kind]; !ok {
+			v.add(prefix, "type de modèle inconnu: %s", kind)
+			continue
+		}
+		if _, err := texttemplate.New("subject").Funcs(templateFuncs).Parse(tpl.Subject); err != nil {
+			v.add(prefix+".subject", "modèle %s, sujet invalide: %s", kind, err)
+		}
+		if _, err := texttemplate.New("text").Funcs(templateFuncs).Parse(tpl.Text); err != nil {
+			v.add(prefix+".text", "modèle %s, texte invalide: %s", kind, err)
+		}
+		if _, err := htmltemplate.New("html").Funcs(templateFuncs).Parse(tpl.HTML); err != nil {
+			v.add(prefix+".html", "modèle %s, HTML invalide: %s", kind, err)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package backend
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateSettings(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(s *Settings)
+		fields []string // Chemins des champs en erreur, dans l'ordre
+	}{
+		{name: "réglages par défaut", modify: func(s *Settings) {}},
+		{
+			name:   "délai entre notifications négatif",
+			modify: func(s *Settings) { s.NotificationCooldown = -1 },
+			fields: []string{"notificationCooldown"},
+		},
+		{
+			name:   "intervalle de rafraîchissement nul",
+			modify: func(s *Settings) { s.RefreshInterval = 0 },
+			fields: []string{"refreshInterval"},
+		},
+		{
+			name:   "mode de notification inconnu",
+			modify: func(s *Settings) { s.NotificationMode = "sms" },
+			fields: []string{"notificationMode"},
+		},
+		{
+			name:   "adresse de l'utilisateur invalide",
+			modify: func(s *Settings) { s.UserEmail = "ops@" },
+			fields: []string{"userEmail"},
+		},
+		{
+			name: "email sans destinataire",
+			modify: func(s *Settings) {
+				s.NotificationMode = "email"
+				s.SMTPConfig.Host, s.SMTPConfig.Port = "smtp.example.com", 587
+			},
+			fields: []string{"userEmail"},
+		},
+		{
+			name: "destinataires invalides",
+			modify: func(s *Settings) {
+				s.Recipients = RecipientList{To: []string{"ops@example.com"}, Cc: []string{"ok@example.com", "pas une adresse"}, Bcc: []string{"a@b@c"}}
+			},
+			fields: []string{"recipients.cc[1]", "recipients.bcc[0]"},
+		},
+		{
+			name:   "port SMTP invalide",
+			modify: func(s *Settings) { s.SMTPConfig.Host, s.SMTPConfig.Port = "smtp.example.com", 70000 },
+			fields: []string{"smtp_config.port"},
+		},
+		{
+			name: "port SMTP manquant en mode email",
+			modify: func(s *Settings) {
+				s.NotificationMode, s.UserEmail, s.SMTPConfig.Host = "email", "ops@example.com", "smtp.example.com"
+			},
+			fields: []string{"smtp_config.port"},
+		},
+		{
+			name:   "mode TLS inconnu",
+			modify: func(s *Settings) { s.SMTPConfig.TLSMode = "ssl3" },
+			fields: []string{"smtp_config.tls_mode"},
+		},
+		{
+			name:   "authentification SMTP inconnue",
+			modify: func(s *Settings) { s.SMTPConfig.Auth = "kerberos" },
+			fields: []string{"smtp_config.auth"},
+		},
+		{
+			name:   "adresse d'écoute des heartbeats sans port",
+			modify: func(s *Settings) { s.HeartbeatSMTPAddr = "0.0.0.0" },
+			fields: []string{"heartbeatSmtpAddr"},
+		},
+		{
+			name:   "port d'écoute des pings hors limites",
+			modify: func(s *Settings) { s.PushListenAddr = ":99999" },
+			fields: []string{"pushListenAddr"},
+		},
+		{
+			name: "plusieurs erreurs",
+			modify: func(s *Settings) {
+				s.NotificationCooldown, s.RefreshInterval, s.PushListenAddr = -5, 5, "localhost:http"
+			},
+			fields: []string{"notificationCooldown", "refreshInterval", "pushListenAddr"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := DefaultSettings()
+			tt.modify(&s)
+
+			var fields []string
+			for _, field := range ValidateSettings(s) {
+				fields = append(fields, field.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("champs en erreur %q, attendu %q", fields, tt.fields)
+			}
+			if err := s.Validate(); (err != nil) != (len(tt.fields) > 0) {
+				t.Errorf("Validate = %v, attendu une erreur: %v", err, len(tt.fields) > 0)
+			}
+		})
+	}
+}
+
+func TestValidateSettingsSeverityRecipients(t *testing.T) {
+	tests := []struct {
+		name    string
+		list    RecipientList
+		invalid bool
+	}{
+		{name: "To", list: RecipientList{To: []string{"ops@example.com"}}},
+		{name: "Cc et copie cachée", list: RecipientList{Cc: []string{"ops@example.com"}, Bcc: []string{"boss@example.com"}}},
+		{name: "copie cachée uniquement", list: RecipientList{Bcc: []string{"boss@example.com"}}, invalid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := DefaultSettings()
+			s.SeverityRecipients = map[string]RecipientList{EmailTemplateCritical: tt.list}
+
+			var found bool
+			for _, field := range ValidateSettings(s) {
+				if field.Field == "severityRecipients.CRITICAL.to" {
+					found = true
+				}
+			}
+			if found != tt.invalid {
+				t.Errorf("erreur sur severityRecipients.CRITICAL.to = %v, attendu %v", found, tt.invalid)
+			}
+		})
+	}
+}
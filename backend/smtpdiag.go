@@ -0,0 +1,498 @@
+// Package backend - Diagnostic de la configuration SMTP
+// Ce fichier déroule pas à pas la connexion au serveur SMTP externe
+// (DNS, TCP, TLS, EHLO, STARTTLS, authentification, envoi de test) et
+// produit un rapport détaillé avec des conseils pour chaque échec
+package backend
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	netmail "net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/wneessen/go-mail"
+	"github.com/wneessen/go-mail/smtp"
+)
+
+// Statuts d'une étape de diagnostic
+const (
+	DiagStatusOK      = "ok"      // Étape réussie
+	DiagStatusWarning = "warning" // Réussie, mais à surveiller
+	DiagStatusError   = "error"   // Échec (les étapes suivantes sont ignorées)
+	DiagStatusSkipped = "skipped" // Non exécutée
+)
+
+// SMTPDiagStep - Résultat d'une étape du diagnostic
+type SMTPDiagStep struct {
+	ID         string `json:"id"`               // config | dns | tcp | tls | ehlo | starttls | auth | send
+	Label      string `json:"label"`            // Libellé affiché
+	Status     string `json:"status"`           // ok | warning | error | skipped
+	Detail     string `json:"detail,omitempty"` // Ce qui a été observé
+	Hint       string `json:"hint,omitempty"`   // Conseil pour corriger l'erreur
+	Code       int    `json:"code,omitempty"`   // Code de réponse SMTP en cas d'erreur
+	DurationMs int64  `json:"duration_ms"`      // Durée de l'étape
+}
+
+// SMTPDiagReport - Rapport complet du diagnostic SMTP
+type SMTPDiagReport struct {
+	Host      string         `json:"host"`
+	Port      int            `json:"port"`
+	TLSMode   string         `json:"tls_mode"`
+	Auth      string         `json:"auth"`
+	Success   bool           `json:"success"` // Toutes les étapes exécutées sans erreur
+	Steps     []SMTPDiagStep `json:"steps"`
+	StartedAt time.Time      `json:"started_at"`
+}
+
+// smtpDiagTimeout - Délai maximum de chaque étape réseau
+const smtpDiagTimeout = 15 * time.Second
+
+// smtpDiagExtensions - Extensions EHLO listées dans le rapport
+var smtpDiagExtensions = []string{"STARTTLS", "AUTH", "SIZE", "8BITMIME", "SMTPUTF8", "PIPELINING", "ENHANCEDSTATUSCODES", "DSN", "CHUNKING"}
+
+// smtpDiagnosis - État du diagnostic en cours
+type smtpDiagnosis struct {
+	cfg    SMTPConfig
+	to     string
+	conn   net.Conn
+	client *smtp.Client
+}
+
+// diagStep - Étape planifiée du diagnostic
+type diagStep struct {
+	id    string
+	label string
+	run   func(step *SMTPDiagStep) error
+}
+
+// DiagnoseSMTP - Diagnostique une configuration SMTP étape par étape
+// Un email de test est envoyé à to s'il est renseigné. Le diagnostic
+// s'arrête à la première erreur, les étapes restantes sont marquées ignorées
+func DiagnoseSMTP(cfg SMTPConfig, to string) SMTPDiagReport {
+	report := SMTPDiagReport{
+		Host:      cfg.Host,
+		Port:      cfg.Port,
+		TLSMode:   cfg.EffectiveTLSMode(),
+		Auth:      cfg.AuthMechanism(),
+		StartedAt: time.Now(),
+	}
+	d := &smtpDiagnosis{cfg: cfg, to: strings.TrimSpace(to)}
+	defer d.close()
+
+	steps := []diagStep{
+		{"config", "Configuration", d.validate},
+		{"dns", "Résolution DNS", d.resolve},
+		{"tcp", "Connexion TCP", d.connect},
+	}
+	if report.TLSMode == TLSModeImplicit {
+		steps = append(steps, diagStep{"tls", "Négociation TLS", d.handshake})
+	}
+	steps = append(steps, diagStep{"ehlo", "Bannière et EHLO", d.hello})
+	if report.TLSMode == TLSModeOpportunistic || report.TLSMode == TLSModeSTARTTLS {
+		steps = append(steps, diagStep{"starttls", "STARTTLS", d.startTLS})
+	}
+	steps = append(steps,
+		diagStep{"auth", "Authentification", d.authenticate},
+		diagStep{"send", "Envoi de test", d.send},
+	)
+
+	failed := false
+	for _, planned := range steps {
+		step := SMTPDiagStep{ID: planned.id, Label: planned.label, Status: DiagStatusOK}
+		if failed {
+			step.Status = DiagStatusSkipped
+			step.Detail = "Ignorée après l'échec d'une étape précédente"
+			report.Steps = append(report.Steps, step)
+			continue
+		}
+
+		start := time.Now()
+		if err := planned.run(&step); err != nil {
+			step.Status = DiagStatusError
+			step.Detail = err.Error()
+			step.Code, step.Hint = smtpErrorHint(cfg, planned.id, err)
+			failed = true
+		}
+		step.DurationMs = time.Since(start).Milliseconds()
+		report.Steps = append(report.Steps, step)
+	}
+	report.Success = !failed
+	return report
+}
+
+// validate - Vérifie la cohérence de la configuration avant toute connexion
+func (d *smtpDiagnosis) validate(step *SMTPDiagStep) error {
+	if d.cfg.Host == "" {
+		return fmt.Errorf("serveur SMTP non renseigné")
+	}
+	if d.cfg.Port <= 0 || d.cfg.Port > 65535 {
+		return fmt.Errorf("port invalide: %d", d.cfg.Port)
+	}
+	if err := ValidateSMTPConfig(d.cfg); err != nil {
+		return err
+	}
+	step.Detail = fmt.Sprintf("%s:%d, TLS %s, authentification %s",
+		d.cfg.Host, d.cfg.Port, d.cfg.EffectiveTLSMode(), d.cfg.AuthMechanism())
+	return nil
+}
+
+// resolve - Résout le nom du serveur SMTP
+func (d *smtpDiagnosis) resolve(step *SMTPDiagStep) error {
+	if net.ParseIP(d.cfg.Host) != nil {
+		step.Detail = "Adresse IP, aucune résolution nécessaire"
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), smtpDiagTimeout)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupHost(ctx, d.cfg.Host)
+	if err != nil {
+		return err
+	}
+	step.Detail = fmt.Sprintf("%s → %s", d.cfg.Host, strings.Join(addrs, ", "))
+	return nil
+}
+
+// connect - Ouvre la connexion TCP vers le serveur
+func (d *smtpDiagnosis) connect(step *SMTPDiagStep) error {
+	address := net.JoinHostPort(d.cfg.Host, strconv.Itoa(d.cfg.Port))
+	conn, err := net.DialTimeout("tcp", address, smtpDiagTimeout)
+	if err != nil {
+		return err
+	}
+	d.conn = conn
+	step.Detail = fmt.Sprintf("Connecté à %s", conn.RemoteAddr())
+	return nil
+}
+
+// handshake - Négocie TLS dès la connexion (mode implicite, SMTPS)
+func (d *smtpDiagnosis) handshake(step *SMTPDiagStep) error {
+	tlsConfig, err := d.cfg.TLSConfig()
+	if err != nil {
+		return err
+	}
+	tlsConn := tls.Client(d.conn, tlsConfig)
+	ctx, cancel := context.WithTimeout(context.Background(), smtpDiagTimeout)
+	defer cancel()
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return err
+	}
+	d.conn = tlsConn
+	d.describeTLS(step, tlsConn.ConnectionState())
+	return nil
+}
+
+// hello - Lit la bannière du serveur et envoie EHLO
+func (d *smtpDiagnosis) hello(step *SMTPDiagStep) error {
+	d.conn.SetDeadline(time.Now().Add(smtpDiagTimeout))
+	client, err := smtp.NewClient(d.conn, d.cfg.Host)
+	if err != nil {
+		return err
+	}
+	d.client = client
+	if err := client.Hello("localhost"); err != nil {
+		return err
+	}
+	step.Detail = "Extensions: " + d.extensions()
+	return nil
+}
+
+// startTLS - Chiffre la connexion avec STARTTLS
+func (d *smtpDiagnosis) startTLS(step *SMTPDiagStep) error {
+	if ok, _ := d.client.Extension("STARTTLS"); !ok {
+		if d.cfg.EffectiveTLSMode() == TLSModeOpportunistic {
+			step.Status = DiagStatusWarning
+			step.Detail = "STARTTLS non proposé: la connexion reste en clair"
+			return nil
+		}
+		return fmt.Errorf("le serveur ne propose pas STARTTLS")
+	}
+
+	tlsConfig, err := d.cfg.TLSConfig()
+	if err != nil {
+		return err
+	}
+	d.conn.SetDeadline(time.Now().Add(smtpDiagTimeout))
+	if err := d.client.StartTLS(tlsConfig); err != nil {
+		return err
+	}
+	if state, ok := d.client.TLSConnectionState(); ok {
+		d.describeTLS(step, state)
+	}
+	step.Detail += "; extensions: " + d.extensions()
+	return nil
+}
+
+// authenticate - S'authentifie avec le mécanisme configuré
+func (d *smtpDiagnosis) authenticate(step *SMTPDiagStep) error {
+	mechanism := d.cfg.AuthMechanism()
+	if mechanism == SMTPAuthNone {
+		step.Status = DiagStatusSkipped
+		step.Detail = "Authentification désactivée"
+		return nil
+	}
+
+	ok, advertised := d.client.Extension("AUTH")
+	if !ok {
+		return fmt.Errorf("le serveur ne propose pas l'authentification (AUTH)")
+	}
+	if !containsFold(strings.Fields(advertised), mechanism) {
+		return fmt.Errorf("mécanisme %s non proposé par le serveur (proposés: %s)", strings.ToUpper(mechanism), advertised)
+	}
+
+	secret, err := d.cfg.Secret()
+	if err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	switch mechanism {
+	case SMTPAuthPlain:
+		auth = smtp.PlainAuth("", d.cfg.Username, secret, d.cfg.Host, false)
+	case SMTPAuthLogin:
+		auth = smtp.LoginAuth(d.cfg.Username, secret, d.cfg.Host, false)
+	case SMTPAuthCramMD5:
+		auth = smtp.CRAMMD5Auth(d.cfg.Username, secret)
+	case SMTPAuthXOAUTH2:
+		auth = smtp.XOAuth2Auth(d.cfg.Username, secret)
+	default:
+		return fmt.Errorf("authentification SMTP inconnue: %s", mechanism)
+	}
+
+	d.conn.SetDeadline(time.Now().Add(smtpDiagTimeout))
+	if err := d.client.Auth(auth); err != nil {
+		return err
+	}
+	step.Detail = fmt.Sprintf("Authentifié en tant que %s (%s)", d.cfg.Username, strings.ToUpper(mechanism))
+	return nil
+}
+
+// send - Envoie un email de test sur la connexion établie
+func (d *smtpDiagnosis) send(step *SMTPDiagStep) error {
+	if d.to == "" {
+		step.Status = DiagStatusSkipped
+		step.Detail = "Aucun destinataire de test renseigné"
+		return nil
+	}
+
+	from := d.cfg.From
+	if from == "" {
+		from = d.cfg.Username
+	}
+	sender, err := netmail.ParseAddress(from)
+	if err != nil {
+		return fmt.Errorf("adresse expéditeur invalide: %s", from)
+	}
+	recipient, err := netmail.ParseAddress(d.to)
+	if err != nil {
+		return fmt.Errorf("adresse destinataire invalide: %s", d.to)
+	}
+
+	m := mail.NewMsg()
+	if err := m.From(from); err != nil {
+		return fmt.Errorf("adresse expéditeur invalide: %s", err)
+	}
+	if err := m.To(d.to); err != nil {
+		return fmt.Errorf("adresse destinataire invalide: %s", err)
+	}
+	m.Subject("🩺 Diagnostic SMTP - Monitoring App")
+	m.SetBodyString(mail.TypeTextPlain, fmt.Sprintf(`Diagnostic de la configuration email
+
+Toutes les étapes du diagnostic ont réussi: votre configuration SMTP est opérationnelle.
+
+- Serveur SMTP: %s:%d
+- TLS: %s
+- Authentification: %s
+
+---
+Envoyé le %s`,
+		d.cfg.Host, d.cfg.Port, d.cfg.EffectiveTLSMode(), d.cfg.AuthMechanism(),
+		time.Now().Format("15:04:05 - 02/01/2006")))
+
+	d.conn.SetDeadline(time.Now().Add(smtpDiagTimeout))
+	if err := d.client.Mail(sender.Address); err != nil {
+		return fmt.Errorf("expéditeur refusé: %w", err)
+	}
+	if err := d.client.Rcpt(recipient.Address); err != nil {
+		return fmt.Errorf("destinataire refusé: %w", err)
+	}
+	w, err := d.client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := m.WriteTo(w); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("message refusé: %w", err)
+	}
+	step.Detail = fmt.Sprintf("Email de test accepté pour %s", recipient.Address)
+	return nil
+}
+
+// close - Termine proprement la session SMTP
+func (d *smtpDiagnosis) close() {
+	if d.client != nil {
+		d.conn.SetDeadline(time.Now().Add(5 * time.Second))
+		if err := d.client.Quit(); err != nil {
+			d.client.Close()
+		}
+		return
+	}
+	if d.conn != nil {
+		d.conn.Close()
+	}
+}
+
+// describeTLS - Décrit la session TLS négociée (version, certificat)
+func (d *smtpDiagnosis) describeTLS(step *SMTPDiagStep, state tls.ConnectionState) {
+	detail := tls.VersionName(state.Version)
+	if len(state.PeerCertificates) > 0 {
+		cert := state.PeerCertificates[0]
+		detail += fmt.Sprintf(", certificat %s (expire le %s)", cert.Subject.CommonName, cert.NotAfter.Format("02/01/2006"))
+	}
+	step.Detail = detail
+	if d.cfg.SkipVerify {
+		step.Status = DiagStatusWarning
+		step.Detail += "; certificat non vérifié"
+		step.Hint = "La vérification du certificat est désactivée: ajoutez plutôt le certificat CA du serveur"
+	}
+}
+
+// extensions - Liste des extensions annoncées par le serveur
+func (d *smtpDiagnosis) extensions() string {
+	var found []string
+	for _, name := range smtpDiagExtensions {
+		if ok, param := d.client.Extension(name); ok {
+			found = append(found, strings.TrimSpace(name+" "+param))
+		}
+	}
+	if len(found) == 0 {
+		return "aucune"
+	}
+	return strings.Join(found, ", ")
+}
+
+// containsFold - Recherche une valeur sans tenir compte de la casse
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// smtpErrorHint - Code SMTP et conseil lisible pour une erreur de diagnostic
+func smtpErrorHint(cfg SMTPConfig, stepID string, err error) (int, string) {
+	host := strings.ToLower(cfg.Host)
+	mode := cfg.EffectiveTLSMode()
+
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) {
+		return protoErr.Code, smtpCodeHint(protoErr.Code, stepID, host)
+	}
+
+	var dnsErr *net.DNSError
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidCert x509.CertificateInvalidError
+	var recordErr tls.RecordHeaderError
+	var netErr net.Error
+
+	switch {
+	case errors.As(err, &dnsErr):
+		if dnsErr.IsNotFound {
+			return 0, "Nom d'hôte introuvable: vérifiez l'orthographe du serveur SMTP"
+		}
+		return 0, "Résolution DNS impossible: vérifiez la connexion réseau et les serveurs DNS"
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return 0, "Connexion refusée: aucun service SMTP sur ce port. Ports usuels: 587 (STARTTLS), 465 (TLS implicite), 25"
+	case errors.As(err, &unknownAuthority):
+		return 0, "Certificat signé par une autorité inconnue: renseignez le certificat CA du serveur (ou désactivez la vérification, déconseillé)"
+	case errors.As(err, &hostnameErr):
+		return 0, "Le certificat ne correspond pas au nom du serveur: utilisez le nom d'hôte exact présent dans le certificat"
+	case errors.As(err, &invalidCert):
+		return 0, "Certificat du serveur expiré ou invalide"
+	case errors.As(err, &recordErr):
+		if mode == TLSModeImplicit {
+			return 0, "Le serveur ne parle pas TLS dès la connexion sur ce port: choisissez STARTTLS (port 587)"
+		}
+		return 0, "Réponse TLS inattendue du serveur"
+	case errors.Is(err, smtp.ErrUnencrypted):
+		return 0, "Le mot de passe serait envoyé en clair: choisissez STARTTLS obligatoire ou TLS implicite"
+	case errors.As(err, &netErr) && netErr.Timeout(), errors.Is(err, io.EOF):
+		if stepID == "ehlo" && mode != TLSModeImplicit && cfg.Port == 465 {
+			return 0, "Le port 465 attend TLS dès la connexion: choisissez TLS implicite"
+		}
+		if stepID == "tcp" {
+			return 0, "Délai dépassé: le port est probablement filtré par un pare-feu ou votre fournisseur d'accès (le port 25 est souvent bloqué, essayez 587 ou 465)"
+		}
+		return 0, "Le serveur ne répond pas ou a fermé la connexion: vérifiez le port et le mode TLS"
+	}
+
+	switch stepID {
+	case "starttls":
+		return 0, "Le serveur ne propose pas STARTTLS sur ce port: choisissez TLS implicite (port 465) ou STARTTLS opportuniste"
+	case "auth":
+		if cfg.AuthMechanism() == SMTPAuthXOAUTH2 {
+			return 0, "Vérifiez l'URL des jetons, le client ID et le refresh token OAuth2"
+		}
+		return 0, "Choisissez un mécanisme d'authentification proposé par le serveur"
+	}
+	return 0, ""
+}
+
+// smtpCodeHint - Conseil associé à un code de réponse SMTP
+func smtpCodeHint(code int, stepID, host string) string {
+	gmail := strings.Contains(host, "gmail") || strings.Contains(host, "google")
+	microsoft := strings.Contains(host, "office365") || strings.Contains(host, "outlook")
+
+	switch code {
+	case 535:
+		switch {
+		case gmail:
+			return "Identifiants refusés: Gmail exige un mot de passe d'application (16 caractères) lorsque la validation en deux étapes est active"
+		case microsoft:
+			return "Identifiants refusés: Microsoft 365 désactive souvent l'authentification basique, utilisez OAuth2 (XOAUTH2) ou activez SMTP AUTH pour la boîte"
+		}
+		return "Identifiants refusés: vérifiez le nom d'utilisateur et le mot de passe"
+	case 534:
+		return "Le serveur exige un mot de passe d'application ou une authentification plus forte (validation en deux étapes active): générez un mot de passe d'application ou utilisez OAuth2"
+	case 530:
+		if stepID == "auth" || stepID == "send" {
+			return "Authentification ou chiffrement requis: activez STARTTLS et l'authentification"
+		}
+		return "Le serveur exige STARTTLS avant cette commande"
+	case 538:
+		return "Le serveur exige le chiffrement avant l'authentification: choisissez STARTTLS ou TLS implicite"
+	case 504:
+		return "Mécanisme d'authentification non supporté: choisissez-en un autre"
+	case 454:
+		return "Échec temporaire du serveur (TLS ou authentification indisponible): réessayez plus tard"
+	case 421:
+		return "Service indisponible: le serveur ferme la connexion (trop de connexions ou adresse IP bloquée)"
+	case 550, 553:
+		if stepID == "send" {
+			return "Adresse refusée: l'expéditeur doit correspondre au compte authentifié (ou à un alias autorisé) et le destinataire doit exister"
+		}
+		return "Commande refusée par le serveur"
+	case 552:
+		return "Message trop volumineux pour le serveur"
+	case 554:
+		return "Transaction refusée: relais non autorisé ou message rejeté par le filtre anti-spam"
+	}
+	if code >= 400 && code < 500 {
+		return "Erreur temporaire du serveur: réessayez plus tard"
+	}
+	return ""
+}
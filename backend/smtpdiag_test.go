@@ -0,0 +1,233 @@
+package backend
+
+import (
+	"io"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/emersion/go-sasl"
+	gosmtp "github.com/emersion/go-smtp"
+)
+
+// diagTestBackend - Serveur SMTP local acceptant ops / hunter2 en AUTH PLAIN
+type diagTestBackend struct {
+	mu       sync.Mutex
+	received []string // Messages acceptés
+}
+
+func (b *diagTestBackend) NewSession(*gosmtp.Conn) (gosmtp.Session, error) {
+	return &diagTestSession{backend: b}, nil
+}
+
+type diagTestSession struct {
+	backend *diagTestBackend
+}
+
+func (s *diagTestSession) AuthMechanisms() []string { return []string{sasl.Plain} }
+
+func (s *diagTestSession) Auth(mech string) (sasl.Server, error) {
+	return sasl.NewPlainServer(func(identity, username, password string) error {
+		if username != "ops" || password != "hunter2" {
+			return gosmtp.ErrAuthFailed
+		}
+		return nil
+	}), nil
+}
+
+func (s *diagTestSession) Mail(from string, opts *gosmtp.MailOptions) error { return nil }
+func (s *diagTestSession) Rcpt(to string, opts *gosmtp.RcptOptions) error   { return nil }
+func (s *diagTestSession) Reset()                                           {}
+func (s *diagTestSession) Logout() error                                    { return nil }
+
+func (s *diagTestSession) Data(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.backend.mu.Lock()
+	s.backend.received = append(s.backend.received, string(data))
+	s.backend.mu.Unlock()
+	return nil
+}
+
+// startDiagSMTPServer - Démarre un serveur SMTP en clair sur la boucle locale
+func startDiagSMTPServer(t *testing.T) (int, *diagTestBackend) {
+	t.Helper()
+	backend := &diagTestBackend{}
+	server := gosmtp.NewServer(backend)
+	server.Domain = "localhost"
+	server.AllowInsecureAuth = true
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+	return listener.Addr().(*net.TCPAddr).Port, backend
+}
+
+// closedPort - Port de la boucle locale sur lequel rien n'écoute
+func closedPort(t *testing.T) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+	return port
+}
+
+// plainBannerPort - Serveur répondant en clair par une bannière SMTP
+func plainBannerPort(t *testing.T) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Write([]byte("220 localhost ESMTP\r\n"))
+			io.Copy(io.Discard, conn)
+			conn.Close()
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestDiagnoseSMTP(t *testing.T) {
+	smtpPort, received := startDiagSMTPServer(t)
+	tlsServer := httptest.NewTLSServer(nil)
+	defer tlsServer.Close()
+	tlsPort := tlsServer.Listener.Addr().(*net.TCPAddr).Port
+
+	local := func(port int, auth, password string) SMTPConfig {
+		return SMTPConfig{
+			Host: "127.0.0.1", Port: port, TLSMode: TLSModeNone, Auth: auth,
+			Username: "ops", Password: password, From: "ops@example.com",
+		}
+	}
+	tests := []struct {
+		name     string
+		cfg      SMTPConfig
+		to       string
+		failed   string // Étape en échec (vide: diagnostic réussi)
+		code     int    // Code SMTP attendu pour l'échec
+		hint     string // Extrait du conseil attendu (un conseil est toujours exigé)
+		received int    // Emails de test reçus par le serveur
+	}{
+		{
+			name:   "configuration incomplète",
+			cfg:    SMTPConfig{Port: 587},
+			failed: "config",
+		},
+		{
+			name:   "nom d'hôte introuvable",
+			cfg:    SMTPConfig{Host: "smtp.example.invalid", Port: 587, TLSMode: TLSModeNone, Auth: SMTPAuthNone},
+			failed: "dns", // Conseil selon le résolveur: nom introuvable ou DNS injoignable
+		},
+		{
+			name:   "connexion refusée",
+			cfg:    local(closedPort(t), SMTPAuthNone, ""),
+			failed: "tcp",
+			hint:   "Connexion refusée",
+		},
+		{
+			name:   "TLS implicite sur un port en clair",
+			cfg:    SMTPConfig{Host: "127.0.0.1", Port: plainBannerPort(t), TLSMode: TLSModeImplicit, Auth: SMTPAuthNone},
+			failed: "tls",
+			hint:   "ne parle pas TLS dès la connexion",
+		},
+		{
+			name:   "certificat d'une autorité inconnue",
+			cfg:    SMTPConfig{Host: "127.0.0.1", Port: tlsPort, TLSMode: TLSModeImplicit, Auth: SMTPAuthNone},
+			failed: "tls",
+			hint:   "autorité inconnue",
+		},
+		{
+			name:   "STARTTLS obligatoire non proposé",
+			cfg:    SMTPConfig{Host: "127.0.0.1", Port: smtpPort, TLSMode: TLSModeSTARTTLS, Auth: SMTPAuthNone},
+			failed: "starttls",
+			hint:   "ne propose pas STARTTLS",
+		},
+		{
+			name:   "mot de passe refusé",
+			cfg:    local(smtpPort, SMTPAuthPlain, "wrong"),
+			failed: "auth",
+			code:   535,
+			hint:   "Identifiants refusés",
+		},
+		{
+			name:   "mécanisme non proposé",
+			cfg:    local(smtpPort, SMTPAuthCramMD5, "hunter2"),
+			failed: "auth",
+			hint:   "mécanisme d'authentification proposé",
+		},
+		{
+			name: "diagnostic complet sans envoi",
+			cfg:  local(smtpPort, SMTPAuthPlain, "hunter2"),
+		},
+		{
+			name:     "diagnostic complet avec envoi",
+			cfg:      local(smtpPort, SMTPAuthPlain, "hunter2"),
+			to:       "admin@example.com",
+			received: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received.mu.Lock()
+			before := len(received.received)
+			received.mu.Unlock()
+
+			report := DiagnoseSMTP(tt.cfg, tt.to)
+			if report.Success != (tt.failed == "") {
+				t.Errorf("Success = %v, étapes %+v", report.Success, report.Steps)
+			}
+
+			failedAt := -1
+			for i, step := range report.Steps {
+				switch {
+				case failedAt >= 0:
+					if step.Status != DiagStatusSkipped {
+						t.Errorf("étape %s après l'échec: %s, attendu %s", step.ID, step.Status, DiagStatusSkipped)
+					}
+				case step.Status == DiagStatusError:
+					failedAt = i
+					if step.ID != tt.failed {
+						t.Fatalf("échec à l'étape %s (%s), attendu %q", step.ID, step.Detail, tt.failed)
+					}
+					if step.Code != tt.code {
+						t.Errorf("code %d, attendu %d", step.Code, tt.code)
+					}
+					if step.ID != "config" && step.Hint == "" {
+						t.Errorf("aucun conseil pour l'échec %q", step.Detail)
+					}
+					if !strings.Contains(step.Hint, tt.hint) {
+						t.Errorf("conseil %q, attendu %q", step.Hint, tt.hint)
+					}
+				}
+			}
+			if tt.failed != "" && failedAt < 0 {
+				t.Fatalf("aucune étape en échec, attendu %s: %+v", tt.failed, report.Steps)
+			}
+
+			received.mu.Lock()
+			got := len(received.received) - before
+			received.mu.Unlock()
+			if got != tt.received {
+				t.Errorf("%d emails de test reçus, attendu %d", got, tt.received)
+			}
+		})
+	}
+}
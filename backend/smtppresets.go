@@ -0,0 +1,152 @@
+// Package backend - Catalogue des fournisseurs SMTP
+// Ce fichier charge les préréglages SMTP (hôte, port, TLS, authentification)
+// depuis le catalogue intégré, complété ou surchargé par un fichier local
+package backend
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// Mécanismes d'authentification SMTP supportés
+const (
+	SMTPAuthPlain   = "plain"    // AUTH PLAIN (défaut)
+	SMTPAuthLogin   = "login"    // AUTH LOGIN
+	SMTPAuthCramMD5 = "cram-md5" // AUTH CRAM-MD5
+	SMTPAuthXOAUTH2 = "xoauth2"  // Jeton OAuth2 (Microsoft 365, Gmail)
+	SMTPAuthNone    = "none"     // Pas d'authentification (relais interne)
+)
+
+// SMTPPreset - Préréglage SMTP d'un fournisseur
+type SMTPPreset struct {
+	ID       string        `json:"id"`                 // Identifiant unique (ex: "gmail")
+	Name     string        `json:"name"`               // Nom affiché
+	Host     string        `json:"host"`               // Serveur SMTP
+	Port     int           `json:"port"`               // Port SMTP
+	TLSMode  string        `json:"tls_mode"`           // Mode de chiffrement (starttls, implicit...)
+	Auth     string        `json:"auth"`               // Mécanisme d'authentification
+	Username string        `json:"username,omitempty"` // Identifiant imposé (ex: "apikey")
+	OAuth2   *OAuth2Config `json:"oauth2,omitempty"`   // Point de renouvellement du jeton (xoauth2)
+	Notes    string        `json:"notes,omitempty"`    // Conseils de configuration
+}
+
+// builtinSMTPPresets - Catalogue intégré à l'application
+//
+//go:embed data/smtp_presets.json
+var builtinSMTPPresets []byte
+
+// smtpPresetsFilePath - Fichier local de préréglages supplémentaires
+func smtpPresetsFilePath() (string, error) {
+	return ConfigFilePath("smtp_presets.json")
+}
+
+// LoadSMTPPresets - Charge le catalogue des préréglages SMTP
+// Les entrées du fichier local remplacent celles du catalogue intégré
+// de même identifiant et s'ajoutent aux autres
+func LoadSMTPPresets() ([]SMTPPreset, error) {
+	var presets []SMTPPreset
+	if err := json.Unmarshal(builtinSMTPPresets, &presets); err != nil {
+		return nil, fmt.Errorf("catalogue SMTP intégré invalide: %s", err)
+	}
+
+	path, err := smtpPresetsFilePath()
+	if err != nil {
+		return presets, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return presets, nil // Pas de préréglages locaux, c'est normal
+		}
+		return presets, err
+	}
+	var custom []SMTPPreset
+	if err := json.Unmarshal(data, &custom); err != nil {
+		return presets, fmt.Errorf("fichier %s invalide: %s", path, err)
+	}
+
+	for _, preset := range custom {
+		if err := validateSMTPPreset(preset); err != nil {
+			log.Printf("⚠️ Préréglage SMTP ignoré: %s", err)
+			continue
+		}
+		replaced := false
+		for i := range presets {
+			if presets[i].ID == preset.ID {
+				presets[i] = preset
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			presets = append(presets, preset)
+		}
+	}
+	return presets, nil
+}
+
+// FindSMTPPreset - Recherche un préréglage par identifiant
+func FindSMTPPreset(id string) (SMTPPreset, error) {
+	presets, err := LoadSMTPPresets()
+	if err != nil && len(presets) == 0 {
+		return SMTPPreset{}, err
+	}
+	for _, preset := range presets {
+		if preset.ID == id {
+			return preset, nil
+		}
+	}
+	return SMTPPreset{}, fmt.Errorf("fournisseur SMTP inconnu: %s", id)
+}
+
+// Apply - Applique le préréglage à une configuration SMTP
+// Les identifiants déjà saisis sont conservés
+func (p SMTPPreset) Apply(cfg SMTPConfig) SMTPConfig {
+	cfg.Host = p.Host
+	cfg.Port = p.Port
+	cfg.TLSMode = p.TLSMode
+	cfg.Auth = p.Auth
+	if p.Username != "" {
+		cfg.Username = p.Username
+	}
+	if p.OAuth2 != nil {
+		oauth := OAuth2Config{}
+		if cfg.OAuth2 != nil {
+			oauth = *cfg.OAuth2
+		}
+		oauth.TokenURL = p.OAuth2.TokenURL
+		oauth.Scopes = p.OAuth2.Scopes
+		cfg.OAuth2 = &oauth
+	}
+	return cfg
+}
+
+// validateSMTPPreset - Vérifie un préréglage du fichier local
+func validateSMTPPreset(p SMTPPreset) error {
+	if strings.TrimSpace(p.ID) == "" || p.Host == "" {
+		return fmt.Errorf("identifiant et serveur requis (%q)", p.Name)
+	}
+	if p.Port <= 0 || p.Port > 65535 {
+		return fmt.Errorf("port invalide pour %s: %d", p.ID, p.Port)
+	}
+	if !validSMTPAuth(p.Auth) {
+		return fmt.Errorf("authentification inconnue pour %s: %s", p.ID, p.Auth)
+	}
+	if !validTLSMode(p.TLSMode) {
+		return fmt.Errorf("mode TLS inconnu pour %s: %s", p.ID, p.TLSMode)
+	}
+	return nil
+}
+
+// validSMTPAuth - Indique si un mécanisme d'authentification est supporté
+func validSMTPAuth(auth string) bool {
+	switch auth {
+	case "", SMTPAuthPlain, SMTPAuthLogin, SMTPAuthCramMD5, SMTPAuthXOAUTH2, SMTPAuthNone:
+		return true
+	}
+	return false
+}
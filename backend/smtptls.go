@@ -0,0 +1,69 @@
+// Package backend - Chiffrement des connexions SMTP
+// Ce fichier définit les modes TLS (aucun, STARTTLS opportuniste ou
+// obligatoire, TLS implicite) et la vérification des certificats
+package backend
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Modes de chiffrement de la connexion SMTP
+const (
+	TLSModeNone          = "none"          // Connexion en clair
+	TLSModeOpportunistic = "opportunistic" // STARTTLS si le serveur le propose
+	TLSModeSTARTTLS      = "starttls"      // STARTTLS obligatoire (défaut)
+	TLSModeImplicit      = "implicit"      // TLS dès la connexion (SMTPS, port 465)
+)
+
+// EffectiveTLSMode renvoie le mode TLS appliqué (STARTTLS obligatoire par défaut)
+func (c SMTPConfig) EffectiveTLSMode() string {
+	if c.TLSMode == "" {
+		return TLSModeSTARTTLS
+	}
+	return c.TLSMode
+}
+
+// TLSConfig construit la configuration TLS du client SMTP
+// L'autorité de certification personnalisée s'ajoute aux autorités du système
+func (c SMTPConfig) TLSConfig() (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName:         c.Host,
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: c.SkipVerify,
+	}
+	if strings.TrimSpace(c.CACert) == "" {
+		return config, nil
+	}
+
+	pem := []byte(c.CACert)
+	if !strings.HasPrefix(strings.TrimSpace(c.CACert), "-----BEGIN") {
+		data, err := os.ReadFile(c.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("certificat CA illisible: %s", err)
+		}
+		pem = data
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("certificat CA invalide (PEM attendu)")
+	}
+	config.RootCAs = pool
+	return config, nil
+}
+
+// validTLSMode indique si un mode TLS est supporté
+func validTLSMode(mode string) bool {
+	switch mode {
+	case "", TLSModeNone, TLSModeOpportunistic, TLSModeSTARTTLS, TLSModeImplicit:
+		return true
+	}
+	return false
+}
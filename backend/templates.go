@@ -0,0 +1,259 @@
+// Package backend - Modèles d'emails personnalisables
+// Ce fichier gère les modèles (sujet, texte brut, HTML) utilisés pour les
+// emails d'alerte DOWN, UP, CRITICAL, les résumés de pannes et les résumés
+// des heures calmes
+package backend
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// Types de modèles d'email disponibles
+const (
+	EmailTemplateDown     = "DOWN"
+	EmailTemplateUp       = "UP"
+	EmailTemplateCritical = "CRITICAL"
+	EmailTemplateSummary  = "SUMMARY"
+	EmailTemplateDigest   = "DIGEST"
+)
+
+// EmailTemplate - Modèle d'email personnalisable
+// Sujet et texte utilisent text/template, le HTML utilise html/template
+// Un champ vide reprend le modèle par défaut
+type EmailTemplate struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+}
+
+// RenderedEmail - Email généré à partir d'un modèle
+type RenderedEmail struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+}
+
+// templateFuncs - Fonctions disponibles dans les modèles
+var templateFuncs = map[string]any{
+	// datetime formate un horodatage au format de l'application
+	"datetime": func(t time.Time) string {
+		return t.Format("15:04:05 - 02/01/2006")
+	},
+	// duration arrondit une durée à la seconde
+	"duration": func(d time.Duration) string {
+		return d.Round(time.Second).String()
+	},
+	"upper": strings.ToUpper,
+}
+
+// emailHTMLLayout - Habillage HTML commun aux modèles par défaut
+func emailHTMLLayout(title, color, content string) string {
+	return `<!DOCTYPE html>
+<html>
+<body style="margin:0;padding:24px;background:#f3f4f6;font-family:-apple-system,Helvetica,Arial,sans-serif;color:#111827">
+  <div style="max-width:560px;margin:0 auto;background:#ffffff;border-radius:12px;overflow:hidden">
+    <div style="background:` + color + `;color:#ffffff;padding:16px 24px;font-size:18px;font-weight:600">` + title + `</div>
+    <div style="padding:24px;font-size:14px;line-height:1.5">
+` + content + `
+    </div>
+    <div style="padding:12px 24px;font-size:12px;color:#6b7280;border-top:1px solid #e5e7eb">Envoyé par votre app de monitoring</div>
+  </div>
+</body>
+</html>`
+}
+
+// emailHTMLServerDetails - Détails du serveur et historique récent (HTML)
+const emailHTMLServerDetails = `      <table style="border-collapse:collapse;margin-bottom:16px">
+        <tr><td style="padding:2px 12px 2px 0;color:#6b7280">Serveur</td><td><strong>{{.Server.Name}}</strong></td></tr>
+        {{if .Server.URL}}<tr><td style="padding:2px 12px 2px 0;color:#6b7280">Adresse</td><td>{{.Server.URL}}</td></tr>{{end}}
+        <tr><td style="padding:2px 12px 2px 0;color:#6b7280">Heure</td><td>{{datetime .Time}}</td></tr>
+        {{if .Error}}<tr><td style="padding:2px 12px 2px 0;color:#6b7280">Erreur</td><td style="color:#dc2626">{{.Error}}</td></tr>{{end}}
+      </table>
+      {{if .History}}
+      <div style="font-weight:600;margin-bottom:4px">Historique récent</div>
+      <table style="border-collapse:collapse;font-size:12px">
+        {{range .History}}<tr>
+          <td style="padding:2px 12px 2px 0">{{datetime .LastCheck}}</td>
+          <td style="padding:2px 12px 2px 0;color:{{if .IsUp}}#16a34a{{else}}#dc2626{{end}}">{{if .IsUp}}UP{{else}}DOWN{{end}}</td>
+          <td style="padding:2px 12px 2px 0">{{.ResponseTime}} ms</td>
+          <td style="color:#6b7280">{{.LastError}}</td>
+        </tr>{{end}}
+      </table>
+      {{end}}`
+
+// DefaultEmailTemplates - Modèles d'email par défaut pour chaque type
+func DefaultEmailTemplates() map[string]EmailTemplate {
+	return map[string]EmailTemplate{
+		EmailTemplateDown: {
+			Subject: "ALERTE: {{.Server.Name}} est DOWN",
+			Text: `ALERTE SERVEUR
+
+Serveur: {{.Server.Name}}
+{{if .Server.URL}}Adresse: {{.Server.URL}}
+{{end}}Statut: HORS LIGNE
+Heure: {{datetime .Time}}
+{{if .Error}}Erreur: {{.Error}}
+{{end}}
+Votre serveur {{.Server.Name}} ne repond plus.
+{{if .Dependents}}
+Serveurs dependants injoignables ({{len .Dependents}}), alertes suspendues:
+{{range .Dependents}}- {{.}}
+{{end}}{{end}}
+---
+Envoye par votre app de monitoring
+`,
+			HTML: emailHTMLLayout("🔴 Serveur hors ligne", "#dc2626",
+				`      <p>Votre serveur <strong>{{.Server.Name}}</strong> ne répond plus.</p>
+      {{if .Dependents}}<p>Serveurs dépendants injoignables ({{len .Dependents}}), alertes suspendues :</p>
+      <ul>
+        {{range .Dependents}}<li>{{.}}</li>{{end}}
+      </ul>{{end}}
+`+emailHTMLServerDetails),
+		},
+		EmailTemplateUp: {
+			Subject: "RETABLI: {{.Server.Name}} est UP",
+			Text: `SERVEUR RETABLI
+
+Serveur: {{.Server.Name}}
+{{if .Server.URL}}Adresse: {{.Server.URL}}
+{{end}}Statut: EN LIGNE
+Heure: {{datetime .Time}}
+
+{{if .Downtime}}Votre serveur {{.Server.Name}} est de nouveau accessible apres {{duration .Downtime}} d'indisponibilite.{{else}}Votre serveur {{.Server.Name}} est de nouveau accessible.{{end}}
+
+---
+Envoye par votre app de monitoring
+`,
+			HTML: emailHTMLLayout("🟢 Serveur rétabli", "#16a34a",
+				`      <p>Votre serveur <strong>{{.Server.Name}}</strong> est de nouveau accessible{{if .Downtime}} après <strong>{{duration .Downtime}}</strong> d'indisponibilité{{end}}.</p>
+`+emailHTMLServerDetails),
+		},
+		EmailTemplateCritical: {
+			Subject: "CRITIQUE: {{.Server.Name}} est toujours DOWN",
+			Text: `ALERTE CRITIQUE
+
+Serveur: {{.Server.Name}}
+{{if .Server.URL}}Adresse: {{.Server.URL}}
+{{end}}Statut: HORS LIGNE
+Heure: {{datetime .Time}}
+{{if .Error}}Erreur: {{.Error}}
+{{end}}
+Votre serveur {{.Server.Name}} ne repond toujours pas ({{.Message}}).
+Echecs consecutifs: {{.Status.ConsecutiveFailures}} - Duree de la panne: {{duration .Downtime}}
+
+---
+Envoye par votre app de monitoring
+`,
+			HTML: emailHTMLLayout("🚨 Alerte critique", "#7f1d1d",
+				`      <p>Votre serveur <strong>{{.Server.Name}}</strong> ne répond toujours pas ({{.Message}}).</p>
+      <p>Échecs consécutifs : <strong>{{.Status.ConsecutiveFailures}}</strong> — Durée de la panne : <strong>{{duration .Downtime}}</strong></p>
+`+emailHTMLServerDetails),
+		},
+		EmailTemplateSummary: {
+			Subject: "RESUME: {{len .Servers}} serveur(s) en panne",
+			Text: `RESUME DES PANNES
+
+Heure: {{datetime .Time}}
+
+{{range .Servers}}- {{.Name}} ({{.URL}}){{if .Status.LastError}}: {{.Status.LastError}}{{end}}
+{{end}}
+---
+Envoye par votre app de monitoring
+`,
+			HTML: emailHTMLLayout("📊 Résumé des pannes", "#1f2937",
+				`      <p>{{len .Servers}} serveur(s) en panne au {{datetime .Time}} :</p>
+      <ul>
+        {{range .Servers}}<li><strong>{{.Name}}</strong> ({{.URL}}){{if .Status.LastError}} — <span style="color:#dc2626">{{.Status.LastError}}</span>{{end}}</li>{{end}}
+      </ul>`),
+		},
+		EmailTemplateDigest: {
+			Subject: "RESUME: {{len .Alerts}} alerte(s) pendant les heures calmes",
+			Text: `RESUME DES HEURES CALMES
+
+Heure: {{datetime .Time}}
+
+{{range .Alerts}}- {{datetime .Time}} {{.Server}}: {{.Kind}}{{if gt .Count 1}} x{{.Count}} (derniere: {{datetime .Last}}){{end}}{{if .Message}} ({{.Message}}){{end}}
+{{end}}
+---
+Envoye par votre app de monitoring
+`,
+			HTML: emailHTMLLayout("🌅 Résumé des heures calmes", "#1f2937",
+				`      <p>{{len .Alerts}} alerte(s) retenue(s) pendant les heures calmes :</p>
+      <ul>
+        {{range .Alerts}}<li>{{datetime .Time}} — <strong>{{.Server}}</strong> : {{.Kind}}{{if gt .Count 1}} ×{{.Count}} (dernière : {{datetime .Last}}){{end}}{{if .Message}} ({{.Message}}){{end}}</li>{{end}}
+      </ul>`),
+		},
+	}
+}
+
+// EmailTemplateFor - Modèle effectif pour un type d'email
+// Les champs personnalisés non vides remplacent ceux du modèle par défaut
+func (s Settings) EmailTemplateFor(kind string) EmailTemplate {
+	tpl := DefaultEmailTemplates()[kind]
+	if custom, ok := s.EmailTemplates[kind]; ok {
+		if strings.TrimSpace(custom.Subject) != "" {
+			tpl.Subject = custom.Subject
+		}
+		if strings.TrimSpace(custom.Text) != "" {
+			tpl.Text = custom.Text
+		}
+		if strings.TrimSpace(custom.HTML) != "" {
+			tpl.HTML = custom.HTML
+		}
+	}
+	return tpl
+}
+
+// RenderEmailTemplate - Génère le sujet, le texte brut et le HTML d'un email
+func RenderEmailTemplate(tpl EmailTemplate, data any) (RenderedEmail, error) {
+	var rendered RenderedEmail
+
+	subject, err := renderText("subject", tpl.Subject, data)
+	if err != nil {
+		return rendered, err
+	}
+	// Le sujet doit tenir sur une seule ligne
+	rendered.Subject = strings.Join(strings.Fields(subject), " ")
+
+	if rendered.Text, err = renderText("text", tpl.Text, data); err != nil {
+		return rendered, err
+	}
+
+	if tpl.HTML != "" {
+		t, err := htmltemplate.New("html").Funcs(templateFuncs).Parse(tpl.HTML)
+		if err != nil {
+			return rendered, fmt.Errorf("modèle HTML invalide: %s", err)
+		}
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, data); err != nil {
+			return rendered, fmt.Errorf("erreur de rendu HTML: %s", err)
+		}
+		rendered.HTML = buf.String()
+	}
+	return rendered, nil
+}
+
+// ValidateEmailTemplates - Vérifie la syntaxe des modèles personnalisés
+func ValidateEmailTemplates(templates map[string]EmailTemplate) error {
+	v := &settingsValidator{}
+	v.emailTemplates("emailTemplates", templates)
+	return v.err()
+}
+
+// renderText - Exécute un modèle text/template
+func renderText(name, source string, data any) (string, error) {
+	t, err := texttemplate.New(name).Funcs(templateFuncs).Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("modèle %s invalide: %s", name, err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("erreur de rendu %s: %s", name, err)
+	}
+	return buf.String(), nil
+}
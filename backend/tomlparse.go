@@ -0,0 +1,234 @@
+// Package backend - Lecture des fichiers TOML
+// Le document est validé par go-toml (spécification TOML 1.0 complète) puis
+// converti en arbre yaml.Node, numéros de ligne compris, pour être traité
+// comme un fichier YAML
+package backend
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	toml "github.com/pelletier/go-toml/v2"
+	"github.com/pelletier/go-toml/v2/unstable"
+	"gopkg.in/yaml.v3"
+)
+
+// parseTOML - Analyse un document TOML en arbre yaml.Node
+func parseTOML(data []byte) (*yaml.Node, error) {
+	var doc map[string]any
+	validErr := toml.Unmarshal(data, &doc)
+	var decodeErr *toml.DecodeError
+	if errors.As(validErr, &decodeErr) {
+		// Erreur de syntaxe: go-toml en donne la position
+		line, _ := decodeErr.Position()
+		return nil, &ConfigError{Line: line, Message: tomlMessage(decodeErr)}
+	}
+
+	b := &tomlBuilder{root: &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: 1, Column: 1}}
+	b.parser.Reset(data)
+	for b.parser.NextExpression() {
+		b.expression(b.parser.Expression())
+	}
+	if err := b.parser.Error(); err != nil {
+		return nil, &ConfigError{Message: tomlMessage(err)}
+	}
+	if validErr != nil {
+		// Erreur de sens (clé ou table redéfinie...): sans position fournie,
+		// l'expression fautive est la première dont l'ajout rend le document invalide
+		return nil, &ConfigError{Line: b.failingLine(data), Message: tomlMessage(validErr)}
+	}
+	return &yaml.Node{Kind: yaml.DocumentNode, Line: 1, Column: 1, Content: []*yaml.Node{b.root}}, nil
+}
+
+// tomlMessage - Message d'une erreur go-toml
+func tomlMessage(err error) string {
+	return "TOML invalide: " + strings.TrimPrefix(err.Error(), "toml: ")
+}
+
+// tomlBuilder - Construction de l'arbre yaml.Node d'un document TOML valide
+type tomlBuilder struct {
+	parser  unstable.Parser
+	root    *yaml.Node
+	current *yaml.Node // Table courante (dernier en-tête)
+
+	starts []int // Début de la ligne de chaque expression
+	lines  []int // Ligne de chaque expression
+}
+
+// expression - Ajoute une expression (clé = valeur, [table] ou [[tableau]]) à l'arbre
+// Une expression incompatible avec l'arbre (document invalide) est ignorée
+func (b *tomlBuilder) expression(e *unstable.Node) {
+	if b.current == nil {
+		b.current = b.root
+	}
+	keys, keyNode := b.key(e.Key())
+	line := b.line(keyNode, 1)
+	offset := b.parser.Shape(keyNode.Raw).Start.Offset
+	data := b.parser.Data()
+	for offset > 0 && data[offset-1] != '\n' {
+		offset--
+	}
+	b.starts = append(b.starts, offset)
+	b.lines = append(b.lines, line)
+
+	switch e.Kind {
+	case unstable.Table:
+		if table := b.descend(b.root, keys, line); table != nil {
+			b.current = table
+		}
+	case unstable.ArrayTable:
+		parent := b.descend(b.root, keys[:len(keys)-1], line)
+		if parent == nil {
+			return
+		}
+		last := keys[len(keys)-1]
+		array := mappingValue(parent, last)
+		if array == nil {
+			array = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Line: line}
+			addMappingValue(parent, last, array, line)
+		}
+		if array.Kind != yaml.SequenceNode {
+			return
+		}
+		table := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: line}
+		array.Content = append(array.Content, table)
+		b.current = table
+	case unstable.KeyValue:
+		b.keyValue(b.current, e, line)
+	}
+}
+
+// keyValue - Ajoute une clé (éventuellement pointée) et sa valeur à une table
+func (b *tomlBuilder) keyValue(table *yaml.Node, e *unstable.Node, line int) {
+	keys, _ := b.key(e.Key())
+	parent := b.descend(table, keys[:len(keys)-1], line)
+	if parent == nil || mappingValue(parent, keys[len(keys)-1]) != nil {
+		return
+	}
+	addMappingValue(parent, keys[len(keys)-1], b.value(e.Value(), line), line)
+}
+
+// key - Parties d'une clé pointée, et dernier nœud de la clé
+func (b *tomlBuilder) key(it unstable.Iterator) ([]string, *unstable.Node) {
+	var keys []string
+	var last *unstable.Node
+	for it.Next() {
+		last = it.Node()
+		keys = append(keys, string(last.Data))
+	}
+	return keys, last
+}
+
+// value - Nœud yaml d'une valeur TOML (line: ligne de la clé, à défaut)
+func (b *tomlBuilder) value(v *unstable.Node, line int) *yaml.Node {
+	line = b.line(v, line)
+	scalar := func(tag, value string) *yaml.Node {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value, Line: line}
+	}
+	switch v.Kind {
+	case unstable.Bool:
+		return scalar("!!bool", string(v.Data))
+	case unstable.Integer:
+		n, _ := strconv.ParseInt(strings.ReplaceAll(string(v.Data), "_", ""), 0, 64)
+		return scalar("!!int", strconv.FormatInt(n, 10))
+	case unstable.Float:
+		clean := strings.ReplaceAll(string(v.Data), "_", "")
+		return scalar("!!float", strings.NewReplacer("inf", ".inf", "nan", ".nan").Replace(clean))
+	case unstable.Array:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Line: line}
+		for it := v.Children(); it.Next(); {
+			node.Content = append(node.Content, b.value(it.Node(), line))
+		}
+		return node
+	case unstable.InlineTable:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: line}
+		for it := v.Children(); it.Next(); {
+			_, keyNode := b.key(it.Node().Key())
+			b.keyValue(node, it.Node(), b.line(keyNode, line))
+		}
+		return node
+	default:
+		// Chaînes, dates et heures (conservées telles quelles)
+		node := scalar("!!str", string(v.Data))
+		node.Style = yaml.DoubleQuotedStyle
+		return node
+	}
+}
+
+// line - Ligne d'un nœud dans le document (fallback si inconnue)
+func (b *tomlBuilder) line(n *unstable.Node, fallback int) int {
+	switch {
+	case n == nil:
+		return fallback
+	case n.Raw.Length > 0:
+		return b.parser.Shape(n.Raw).Start.Line
+	case n.Kind == unstable.Bool || n.Kind == unstable.LocalDate || n.Kind == unstable.LocalTime ||
+		n.Kind == unstable.LocalDateTime || n.Kind == unstable.DateTime:
+		// Valeurs lues directement dans le document
+		return b.parser.Shape(b.parser.Range(n.Data)).Start.Line
+	}
+	return fallback
+}
+
+// descend - Table désignée par un chemin de clés (créée si besoin), nil si
+// le chemin traverse une valeur. Dans un tableau de tables, le chemin suit le
+// dernier élément
+func (b *tomlBuilder) descend(table *yaml.Node, keys []string, line int) *yaml.Node {
+	for _, key := range keys {
+		next := mappingValue(table, key)
+		if next == nil {
+			next = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: line}
+			addMappingValue(table, key, next, line)
+		}
+		if next.Kind == yaml.SequenceNode && len(next.Content) > 0 {
+			next = next.Content[len(next.Content)-1]
+		}
+		if next.Kind != yaml.MappingNode {
+			return nil
+		}
+		table = next
+	}
+	return table
+}
+
+// failingLine - Ligne de la première expression qui rend le document invalide
+// Un préfixe invalide le reste quelle que soit la suite: recherche dichotomique
+func (b *tomlBuilder) failingLine(data []byte) int {
+	if len(b.starts) == 0 {
+		return 0
+	}
+	end := func(i int) int {
+		if i+1 < len(b.starts) {
+			return b.starts[i+1]
+		}
+		return len(data)
+	}
+	i := sort.Search(len(b.starts), func(i int) bool {
+		var doc map[string]any
+		return toml.Unmarshal(data[:end(i)], &doc) != nil
+	})
+	if i == len(b.starts) {
+		i--
+	}
+	return b.lines[i]
+}
+
+// mappingValue - Valeur d'une clé dans un nœud de type table (nil si absente)
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// addMappingValue - Ajoute une clé à un nœud de type table
+func addMappingValue(m *yaml.Node, key string, value *yaml.Node, line int) {
+	m.Content = append(m.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key, Line: line},
+		value,
+	)
+}
@@ -0,0 +1,155 @@
+package backend
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestParseTOML(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want map[string]any
+	}{
+		{
+			name: "tables et tableaux de tables",
+			src: `title = "monitoring"
+[settings]
+theme = "dark"
+[[servers]]
+name = "web"
+[[servers]]
+name = "db"
+`,
+			want: map[string]any{
+				"title":    "monitoring",
+				"settings": map[string]any{"theme": "dark"},
+				"servers":  []any{map[string]any{"name": "web"}, map[string]any{"name": "db"}},
+			},
+		},
+		{
+			name: "clés pointées et entre guillemets",
+			src:  "a.b.c = 1\n\"x.y\" = 2\n'z' = 3\n",
+			want: map[string]any{"a": map[string]any{"b": map[string]any{"c": 1}}, "x.y": 2, "z": 3},
+		},
+		{
+			name: "sous-table d'un tableau de tables",
+			src:  "[[servers]]\nname = \"web\"\n[servers.status]\nup = true\n",
+			want: map[string]any{"servers": []any{map[string]any{"name": "web", "status": map[string]any{"up": true}}}},
+		},
+		{
+			name: "entiers",
+			src:  "dec = -17\nsep = 1_000\nhex = 0xff\noct = 0o17\nbin = 0b101\n",
+			want: map[string]any{"dec": -17, "sep": 1000, "hex": 255, "oct": 15, "bin": 5},
+		},
+		{
+			name: "flottants et booléens",
+			src:  "f = 3.5\ne = 1e3\nyes = true\nno = false\n",
+			want: map[string]any{"f": 3.5, "e": 1000.0, "yes": true, "no": false},
+		},
+		{
+			name: "chaînes",
+			src:  "basic = \"a\\tb\\u00e9\"\nliteral = 'C:\\dir'\nmulti = \"\"\"\nligne 1\nligne 2\"\"\"\nraw = '''\n${VAR}'''\n",
+			want: map[string]any{"basic": "a\tbé", "literal": `C:\dir`, "multi": "ligne 1\nligne 2", "raw": "${VAR}"},
+		},
+		{
+			name: "dates conservées telles quelles",
+			src:  "day = 1979-05-27\nat = 07:32:00\nstamp = 1979-05-27T07:32:00Z\n",
+			want: map[string]any{"day": "1979-05-27", "at": "07:32:00", "stamp": "1979-05-27T07:32:00Z"},
+		},
+		{
+			name: "tableaux et tables en ligne",
+			src:  "tags = [\n  \"web\", # commentaire\n  \"prod\",\n]\nsmtp = { host = \"mail\", tls.mode = \"starttls\" }\n",
+			want: map[string]any{
+				"tags": []any{"web", "prod"},
+				"smtp": map[string]any{"host": "mail", "tls": map[string]any{"mode": "starttls"}},
+			},
+		},
+		{
+			name: "document vide",
+			src:  "# rien\n\n",
+			want: map[string]any{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parseTOML([]byte(tt.src))
+			if err != nil {
+				t.Fatalf("erreur inattendue: %s", err)
+			}
+			got := map[string]any{}
+			if err := doc.Content[0].Decode(&got); err != nil {
+				t.Fatalf("décodage: %s", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("obtenu %#v, attendu %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTOMLSpecialFloats(t *testing.T) {
+	doc, err := parseTOML([]byte("a = inf\nb = -inf\nc = nan\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct{ A, B, C float64 }
+	if err := doc.Content[0].Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if !math.IsInf(got.A, 1) || !math.IsInf(got.B, -1) || !math.IsNaN(got.C) {
+		t.Errorf("obtenu %+v", got)
+	}
+}
+
+func TestParseTOMLLines(t *testing.T) {
+	doc, err := parseTOML([]byte("# en-tête\n[[servers]]\nname = \"web\"\ntags = [\n  \"a\",\n  \"b\",\n]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	servers := mappingValue(doc.Content[0], "servers")
+	server := servers.Content[0]
+	if server.Line != 2 {
+		t.Errorf("table ligne %d, attendu 2", server.Line)
+	}
+	if name := mappingValue(server, "name"); name.Line != 3 {
+		t.Errorf("name ligne %d, attendu 3", name.Line)
+	}
+	if tags := mappingValue(server, "tags"); tags.Content[1].Line != 6 {
+		t.Errorf("deuxième tag ligne %d, attendu 6", tags.Content[1].Line)
+	}
+}
+
+func TestParseTOMLErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		line int
+	}{
+		{"clé pointée redéfinie en table", "[fruit]\napple.color = \"red\"\n[fruit.apple]\n", 3},
+		{"table définie deux fois", "[a]\nx = 1\n[b]\n[a]\n", 4},
+		{"clé définie deux fois", "a = 1\nb = 2\na = 3\n", 3},
+		{"table en ligne étendue", "a = { b = 1 }\n[a.c]\n", 2},
+		{"valeur devenue table", "a = 1\na.b = 2\n", 2},
+		{"flottant Infinity", "ok = 1\na = Infinity\n", 2},
+		{"zéro initial", "a = 01\n", 1},
+		{"chaîne non terminée", "a = 1\nb = \"abc\n", 2},
+		{"clé sans valeur", "a =\n", 1},
+		{"en-tête non fermé", "[servers\n", 1},
+		{"caractère après la valeur", "a = 1 2\n", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseTOML([]byte(tt.src))
+			var cerr *ConfigError
+			if !errors.As(err, &cerr) {
+				t.Fatalf("ConfigError attendue, obtenu %v", err)
+			}
+			if cerr.Line != tt.line {
+				t.Errorf("ligne %d, attendu %d (%s)", cerr.Line, tt.line, cerr.Message)
+			}
+		})
+	}
+}
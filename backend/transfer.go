@@ -0,0 +1,326 @@
+// Package backend - Formats d'import/export
+// Ce fichier convertit les documents échangés avec l'extérieur: JSON ↔ YAML
+// (ordre des clés conservé) et tableaux de structures ↔ CSV (une colonne par
+// champ JSON simple), avec des erreurs localisées par ligne
+package backend
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Formats d'import/export
+const (
+	TransferJSON = "json"
+	TransferYAML = "yaml"
+	TransferCSV  = "csv"
+)
+
+// csvListSeparator - Séparateur des listes de textes dans une cellule CSV
+const csvListSeparator = ";"
+
+// DetectTransferFormat - Format probable d'un contenu importé
+// Format d'un autre outil s'il est reconnu, JSON s'il commence par { ou [,
+// CSV si la première ligne est un en-tête séparé par des virgules, YAML sinon
+func DetectTransferFormat(data []byte) string {
+	if format := detectForeignFormat(data); format != "" {
+		return format
+	}
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\ufeff")))
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return TransferJSON
+	}
+	first, _, _ := bytes.Cut(trimmed, []byte("\n"))
+	if bytes.Contains(first, []byte(",")) && !bytes.Contains(first, []byte(":")) {
+		return TransferCSV
+	}
+	return TransferYAML
+}
+
+// JSONToYAML - Convertit un document JSON en YAML en conservant l'ordre des clés
+func JSONToYAML(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	node, err := jsonNode(decoder)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// jsonNode - Nœud YAML de la prochaine valeur JSON
+func jsonNode(decoder *json.Decoder) (*yaml.Node, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	scalar := func(tag, value string) *yaml.Node {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value}
+	}
+	switch t := token.(type) {
+	case json.Delim:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		if t == '{' {
+			node = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+		for decoder.More() {
+			if t == '{' {
+				key, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, scalar("!!str", fmt.Sprint(key)))
+			}
+			value, err := jsonNode(decoder)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, value)
+		}
+		if _, err := decoder.Token(); err != nil { // } ou ]
+			return nil, err
+		}
+		return node, nil
+	case string:
+		return scalar("!!str", t), nil
+	case json.Number:
+		if strings.ContainsAny(t.String(), ".eE") {
+			return scalar("!!float", t.String()), nil
+		}
+		return scalar("!!int", t.String()), nil
+	case bool:
+		return scalar("!!bool", strconv.FormatBool(t)), nil
+	}
+	return scalar("!!null", "null"), nil
+}
+
+// YAMLToJSON - Convertit un document YAML en JSON
+// Les erreurs de syntaxe et les clés en double sont localisées par ligne
+func YAMLToJSON(data []byte) ([]byte, error) {
+	root, err := parseDeclarative("import.yaml", data)
+	if err != nil {
+		var ce *ConfigError
+		if errors.As(err, &ce) {
+			return nil, ConfigErrors{*ce}
+		}
+		return nil, err
+	}
+	if root == nil {
+		return []byte("null"), nil
+	}
+	l := &declLoader{}
+	value := l.convert(root, "", nil)
+	if len(l.errors) > 0 {
+		return nil, l.errors.Sorted()
+	}
+	return json.Marshal(value)
+}
+
+// LocateJSONError - Erreur de décodage JSON complétée par sa ligne
+func LocateJSONError(data []byte, err error) error {
+	var offset int64
+	message := err.Error()
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+		message = fmt.Sprintf("%s: type %s attendu", typeErr.Field, typeErr.Type)
+	default:
+		return err
+	}
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	line := bytes.Count(data[:offset], []byte("\n")) + 1
+	return ConfigErrors{{Line: line, Message: message}}
+}
+
+// csvColumn - Colonne CSV associée à un champ de structure
+type csvColumn struct {
+	name  string
+	index int
+}
+
+// csvColumns - Colonnes CSV d'une structure: ses champs JSON de type texte,
+// nombre, booléen ou liste de textes (les autres champs sont ignorés)
+func csvColumns(t reflect.Type) []csvColumn {
+	var columns []csvColumn
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		switch field.Type.Kind() {
+		case reflect.String, reflect.Bool, reflect.Int, reflect.Int64, reflect.Float64:
+		case reflect.Slice:
+			if field.Type.Elem().Kind() != reflect.String {
+				continue
+			}
+		default:
+			continue
+		}
+		columns = append(columns, csvColumn{name: name, index: i})
+	}
+	return columns
+}
+
+// MarshalCSV - Écrit un tableau de structures en CSV (en-tête + une ligne par élément)
+func MarshalCSV(v any) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("export CSV: tableau de structures attendu")
+	}
+	columns := csvColumns(rv.Type().Elem())
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.name
+	}
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+	for i := 0; i < rv.Len(); i++ {
+		record := make([]string, len(columns))
+		for j, column := range columns {
+			field := rv.Index(i).Field(column.index)
+			if field.Kind() == reflect.Slice {
+				record[j] = strings.Join(field.Interface().([]string), csvListSeparator)
+			} else if !field.IsZero() {
+				record[j] = fmt.Sprint(field.Interface())
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+// UnmarshalCSV - Lit un CSV (avec en-tête) dans un tableau de structures
+// v est un pointeur vers le tableau; les colonnes sont associées aux champs
+// par leur nom JSON. Renvoie les numéros de ligne de chaque élément et, en cas
+// d'erreur de cellule, des ConfigErrors localisées
+func UnmarshalCSV(data []byte, v any) ([]int, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Slice || rv.Elem().Type().Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("import CSV: pointeur vers un tableau de structures attendu")
+	}
+	slice := rv.Elem()
+	itemType := slice.Type().Elem()
+	byName := make(map[string]csvColumn)
+	for _, column := range csvColumns(itemType) {
+		byName[strings.ToLower(column.name)] = column
+	}
+
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("en-tête CSV illisible: %s", err)
+	}
+	var errs ConfigErrors
+	columns := make([]*csvColumn, len(header))
+	for i, name := range header {
+		column, ok := byName[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			errs = append(errs, ConfigError{Line: 1, Field: name, Message: fmt.Sprintf("colonne inconnue: %s", name)})
+			continue
+		}
+		columns[i] = &column
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	var lines []int
+	for {
+		record, err := reader.Read()
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				errs = append(errs, ConfigError{Line: parseErr.Line, Message: parseErr.Err.Error()})
+				continue
+			}
+			break // io.EOF
+		}
+		line, _ := reader.FieldPos(0)
+		item := reflect.New(itemType).Elem()
+		for i, cell := range record {
+			if i >= len(columns) || cell == "" {
+				continue
+			}
+			if err := setCSVField(item.Field(columns[i].index), strings.TrimSpace(cell)); err != nil {
+				errs = append(errs, ConfigError{Line: line, Field: columns[i].name,
+					Message: fmt.Sprintf("colonne %s: %s", columns[i].name, err)})
+			}
+		}
+		slice.Set(reflect.Append(slice, item))
+		lines = append(lines, line)
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return lines, nil
+}
+
+// setCSVField - Affecte le contenu d'une cellule à un champ
+func setCSVField(field reflect.Value, cell string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(cell)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(cell)
+		if err != nil {
+			return fmt.Errorf("booléen attendu: %q", cell)
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(cell, 10, 64)
+		if err != nil {
+			return fmt.Errorf("nombre entier attendu: %q", cell)
+		}
+		field.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(cell, 64)
+		if err != nil {
+			return fmt.Errorf("nombre attendu: %q", cell)
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(cell, csvListSeparator) {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package backend
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// csvItem - Structure de test couvrant les types de colonnes CSV
+type csvItem struct {
+	Name    string            `json:"name"`
+	Port    int               `json:"port"`
+	Ratio   float64           `json:"ratio"`
+	Enabled bool              `json:"enabled"`
+	Tags    []string          `json:"tags,omitempty"`
+	Hidden  string            `json:"-"`
+	Labels  map[string]string `json:"labels"` // Ignoré: pas une colonne simple
+}
+
+func TestUnmarshalCSV(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		want  []csvItem
+		lines []int
+	}{
+		{
+			name: "colonnes dans le désordre et casse libre",
+			src:  "Port, NAME ,tags,enabled,ratio\n8080,web,a; b ;;c,true,0.5\n,db,,,\n",
+			want: []csvItem{
+				{Name: "web", Port: 8080, Ratio: 0.5, Enabled: true, Tags: []string{"a", "b", "c"}},
+				{Name: "db"},
+			},
+			lines: []int{2, 3},
+		},
+		{
+			name:  "BOM et cellules entre guillemets",
+			src:   "\ufeffname,tags\n\"web, prod\",\"x;y\"\n",
+			want:  []csvItem{{Name: "web, prod", Tags: []string{"x", "y"}}},
+			lines: []int{2},
+		},
+		{
+			name:  "champ sur plusieurs lignes",
+			src:   "name,port\n\"a\nb\",1\nc,2\n",
+			want:  []csvItem{{Name: "a\nb", Port: 1}, {Name: "c", Port: 2}},
+			lines: []int{2, 4},
+		},
+		{
+			name: "en-tête seul",
+			src:  "name,port\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var items []csvItem
+			lines, err := UnmarshalCSV([]byte(tt.src), &items)
+			if err != nil {
+				t.Fatalf("erreur inattendue: %s", err)
+			}
+			if !reflect.DeepEqual(items, tt.want) {
+				t.Errorf("obtenu %+v, attendu %+v", items, tt.want)
+			}
+			if !reflect.DeepEqual(lines, tt.lines) {
+				t.Errorf("lignes %v, attendu %v", lines, tt.lines)
+			}
+		})
+	}
+}
+
+func TestUnmarshalCSVErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		src    string
+		line   int
+		field  string
+		errors int
+	}{
+		{"colonne inconnue", "name,color\nweb,red\n", 1, "color", 1},
+		{"colonne ignorée", "name,labels\nweb,x\n", 1, "labels", 1},
+		{"nombre entier invalide", "name,port\nweb,http\n", 2, "port", 1},
+		{"nombre invalide", "name,ratio\nweb,1,5\n", 2, "", 1},
+		{"booléen invalide", "name,enabled\nweb,oui\n", 2, "enabled", 1},
+		{"plusieurs cellules invalides", "name,port,enabled\na,x,true\nb,2,peut-être\n", 2, "port", 2},
+		{"guillemet non fermé", "name,port\n\"web,80\n", 2, "", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var items []csvItem
+			_, err := UnmarshalCSV([]byte(tt.src), &items)
+			var errs ConfigErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("ConfigErrors attendues, obtenu %v", err)
+			}
+			if len(errs) != tt.errors {
+				t.Errorf("%d erreurs, attendu %d: %s", len(errs), tt.errors, errs)
+			}
+			if errs[0].Line != tt.line || errs[0].Field != tt.field {
+				t.Errorf("ligne %d champ %q, attendu %d %q (%s)", errs[0].Line, errs[0].Field, tt.line, tt.field, errs[0].Message)
+			}
+		})
+	}
+}
+
+func TestUnmarshalCSVInvalidTarget(t *testing.T) {
+	var notSlice csvItem
+	if _, err := UnmarshalCSV([]byte("name\n"), &notSlice); err == nil {
+		t.Error("erreur attendue pour une cible qui n'est pas un tableau")
+	}
+	var items []csvItem
+	if _, err := UnmarshalCSV(nil, &items); err == nil {
+		t.Error("erreur attendue sans en-tête")
+	}
+	if _, err := MarshalCSV(csvItem{}); err == nil {
+		t.Error("erreur attendue pour un export qui n'est pas un tableau")
+	}
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	items := []csvItem{
+		{Name: "web, \"prod\"", Port: 443, Ratio: 0.25, Enabled: true, Tags: []string{"a", "b"}, Hidden: "x"},
+		{Name: "db"},
+	}
+	data, err := MarshalCSV(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header := "name,port,ratio,enabled,tags\n"; string(data[:len(header)]) != header {
+		t.Errorf("en-tête: %q", data)
+	}
+	var decoded []csvItem
+	if _, err := UnmarshalCSV(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	items[0].Hidden = "" // Champ exclu de l'export
+	if !reflect.DeepEqual(decoded, items) {
+		t.Errorf("obtenu %+v, attendu %+v", decoded, items)
+	}
+}
+
+func TestDetectTransferFormat(t *testing.T) {
+	tests := map[string]string{
+		`{"kind": "monitoring_serv/export"}`: TransferJSON,
+		"  [\n]":                             TransferJSON,
+		"name,url,type\nweb,https://example.com,http\n": TransferCSV,
+		"servers:\n  - name: web\n":                     TransferYAML,
+		"define host {\n  host_name web\n}\n":           TransferNagios,
+		`{"version": "1.23.0", "monitorList": []}`:      TransferUptimeKuma,
+		"scrape_configs:\n  - job_name: blackbox\n":     TransferBlackbox,
+		`[{"targets": ["https://example.com"]}]`:        TransferBlackbox,
+		"\ufeffname,url\nweb,https://example.com\n":     TransferCSV,
+	}
+	for src, want := range tests {
+		if got := DetectTransferFormat([]byte(src)); got != want {
+			t.Errorf("DetectTransferFormat(%q) = %s, attendu %s", src, got, want)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+// Package backend - Import d'une sauvegarde Uptime Kuma
+// La sauvegarde JSON (Paramètres > Sauvegarde > Exporter) contient la liste
+// des moniteurs; chacun est traduit vers le check le plus proche
+package backend
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// kumaBackup - Sauvegarde Uptime Kuma (seuls les champs utiles sont lus)
+type kumaBackup struct {
+	Version     string        `json:"version"`
+	MonitorList []kumaMonitor `json:"monitorList"`
+}
+
+// kumaMonitor - Moniteur Uptime Kuma
+type kumaMonitor struct {
+	ID               int     `json:"id"`
+	Parent           *int    `json:"parent"` // Moniteur de type group contenant celui-ci
+	Name             string  `json:"name"`
+	Type             string  `json:"type"`
+	URL              string  `json:"url"`
+	Hostname         string  `json:"hostname"`
+	Port             int     `json:"port"`
+	Interval         float64 `json:"interval"` // Secondes
+	Timeout          float64 `json:"timeout"`  // Secondes
+	Active           any     `json:"active"`   // Booléen ou 0/1 selon les versions
+	PushToken        string  `json:"pushToken"`
+	ConnectionString string  `json:"databaseConnectionString"`
+	GrpcURL          string  `json:"grpcUrl"`
+	Tags             []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"tags"`
+}
+
+// kumaDatabasePorts - Port par défaut des moniteurs de bases de données
+var kumaDatabasePorts = map[string]int{
+	"postgres":  5432,
+	"mysql":     3306,
+	"mongodb":   27017,
+	"redis":     6379,
+	"sqlserver": 1433,
+}
+
+// ParseUptimeKuma - Traduit les moniteurs d'une sauvegarde Uptime Kuma
+// http, keyword et json-query deviennent des checks http, port un check tcp,
+// ping un ping, push un check push (même jeton), les bases de données et
+// gRPC un check tcp sur leur adresse; les autres types sont ignorés. Les
+// groupes deviennent le groupe des serveurs, les tags "nom:valeur" leurs tags
+func ParseUptimeKuma(data []byte) (*ForeignImport, error) {
+	var backup kumaBackup
+	if err := decodeJSON(data, &backup); err != nil {
+		return nil, err
+	}
+	if backup.MonitorList == nil {
+		return nil, fmt.Errorf("sauvegarde Uptime Kuma attendue (monitorList absent)")
+	}
+
+	byID := make(map[int]kumaMonitor, len(backup.MonitorList))
+	for _, monitor := range backup.MonitorList {
+		byID[monitor.ID] = monitor
+	}
+
+	result := &ForeignImport{}
+	for i, monitor := range backup.MonitorList {
+		row := i + 1
+		server := ForeignServer{
+			Line:     row,
+			Name:     monitor.Name,
+			Interval: formatSeconds(monitor.Interval),
+			Timeout:  formatSeconds(monitor.Timeout),
+			Group:    kumaGroupPath(monitor, byID),
+		}
+		for _, tag := range monitor.Tags {
+			if tag.Value != "" {
+				server.Tags = append(server.Tags, tag.Name+":"+tag.Value)
+			} else {
+				server.Tags = append(server.Tags, tag.Name)
+			}
+		}
+		switch monitor.Type {
+		case "http", "keyword", "json-query", "real-browser":
+			server.Type, server.URL = "http", monitor.URL
+			if monitor.Type == "keyword" || monitor.Type == "json-query" {
+				result.warn(row, "%s: contenu de la réponse non vérifié (check http simple)", monitor.Name)
+			}
+		case "port":
+			server.Type = "tcp"
+			if monitor.Hostname != "" {
+				server.URL = hostPort(monitor.Hostname, monitor.Port)
+			}
+		case "ping", "tailscale-ping":
+			server.Type, server.URL = "ping", monitor.Hostname
+		case "dns", "steam", "gamedig", "radius":
+			// Services UDP: seule la disponibilité de l'hôte est vérifiable
+			server.Type, server.URL = "ping", monitor.Hostname
+			result.warn(row, "%s: check %s importé en ping de %s", monitor.Name, monitor.Type, monitor.Hostname)
+		case "mqtt":
+			port := monitor.Port
+			if port == 0 {
+				port = 1883
+			}
+			server.Type = "tcp"
+			if monitor.Hostname != "" {
+				server.URL = hostPort(monitor.Hostname, port)
+			}
+		case "postgres", "mysql", "mongodb", "redis", "sqlserver":
+			address, ok := connectionAddress(monitor.ConnectionString, kumaDatabasePorts[monitor.Type])
+			if !ok {
+				result.warn(row, "%s: adresse introuvable dans la chaîne de connexion %s, ignoré", monitor.Name, monitor.Type)
+				continue
+			}
+			server.Type, server.URL = "tcp", address
+		case "grpc-keyword":
+			address, ok := connectionAddress(monitor.GrpcURL, 443)
+			if !ok {
+				result.warn(row, "%s: adresse gRPC invalide, ignoré", monitor.Name)
+				continue
+			}
+			server.Type, server.URL = "tcp", address
+		case "push":
+			server.Type, server.PushToken = "push", monitor.PushToken
+			result.warn(row, "%s: les pings doivent désormais viser /push/%s sur cette application", monitor.Name, monitor.PushToken)
+		case "group":
+			continue // Repris dans le groupe des moniteurs qu'il contient
+		default:
+			result.warn(row, "%s: type %s non pris en charge, ignoré", monitor.Name, monitor.Type)
+			continue
+		}
+		if server.URL == "" && server.Type != "push" {
+			result.warn(row, "%s: adresse manquante, ignoré", monitor.Name)
+			continue
+		}
+		// Moniteur en pause dans Uptime Kuma: importé en pause
+		if active, ok := monitor.Active.(bool); (ok && !active) || monitor.Active == float64(0) {
+			server.Paused = true
+		}
+		result.Servers = append(result.Servers, server)
+	}
+	return result, nil
+}
+
+// kumaGroupPath - Chemin des groupes contenant un moniteur ("parent/enfant")
+func kumaGroupPath(monitor kumaMonitor, byID map[int]kumaMonitor) string {
+	var names []string
+	seen := make(map[int]bool)
+	for monitor.Parent != nil && !seen[*monitor.Parent] {
+		seen[*monitor.Parent] = true
+		parent, ok := byID[*monitor.Parent]
+		if !ok {
+			break
+		}
+		names = append([]string{strings.ReplaceAll(parent.Name, GroupSeparator, "-")}, names...)
+		monitor = parent
+	}
+	return strings.Join(names, GroupSeparator)
+}
+
+// connectionAddress - Adresse hôte:port d'une chaîne de connexion
+// Accepte les URL (postgres://user:pass@hôte:port/base), les adresses
+// hôte:port et les chaînes clé=valeur (Server=hôte,port;Database=...)
+func connectionAddress(conn string, defaultPort int) (string, bool) {
+	conn = strings.TrimSpace(conn)
+	if conn == "" {
+		return "", false
+	}
+	if _, rest, ok := strings.Cut(conn, "://"); ok {
+		// Retirer base, paramètres et identifiants; mongodb:// peut lister
+		// plusieurs hôtes: le premier suffit
+		rest, _, _ = strings.Cut(rest, "/")
+		rest, _, _ = strings.Cut(rest, "?")
+		if i := strings.LastIndex(rest, "@"); i >= 0 {
+			rest = rest[i+1:]
+		}
+		conn, _, _ = strings.Cut(rest, ",")
+	} else if strings.Contains(conn, "=") {
+		for _, part := range strings.Split(conn, ";") {
+			key, value, _ := strings.Cut(part, "=")
+			switch strings.ToLower(strings.TrimSpace(key)) {
+			case "server", "data source", "host", "address", "addr":
+				// SQL Server: "tcp:hôte,port" ou "hôte\instance"
+				value = strings.TrimPrefix(strings.TrimSpace(value), "tcp:")
+				host, portText, found := strings.Cut(value, ",")
+				host, _, _ = strings.Cut(host, `\`)
+				port, err := strconv.Atoi(strings.TrimSpace(portText))
+				if !found || err != nil {
+					port = defaultPort
+				}
+				return hostPort(host, port), host != ""
+			}
+		}
+		return "", false
+	}
+	address, _, _ := strings.Cut(conn, "/")
+	host, portText, err := net.SplitHostPort(address)
+	if err != nil {
+		host, portText = address, ""
+	}
+	port, err := strconv.Atoi(portText)
+	if err != nil {
+		port = defaultPort
+	}
+	return hostPort(host, port), host != ""
+}
@@ -1,6 +1,6 @@
 // File: components/ServerMonitor.jsx
 import { Grid3X3 } from 'lucide-react';
-import { useEffect, useState } from 'react';
+import { useEffect, useRef, useState } from 'react';
 import toast, { Toaster } from 'react-hot-toast';
 import {
   AddServer,
@@ -8,13 +8,19 @@ import {
   GetServers,
   GetSettings,
   ManualCheck,
+  PauseServer,
+  ResumeServer,
   UpdateServer,
 } from '../wailsjs/go/main/App';
+import { EventsOn } from '../wailsjs/runtime/runtime';
 import { useTheme } from './hooks/useTheme';
 
 import ServerCard from './components/ServerCard';
+import ServerFilters from './components/ServerFilters';
 import ServerForm from './components/ServerForm';
 import ServerHeader from './components/ServerHeader';
+import ImportExport from './components/ImportExport';
+import MaintenanceWindows from './components/MaintenanceWindows';
 import Settings from './components/Settings';
 
 const ServerMonitor = () => {
@@ -23,6 +29,11 @@ const ServerMonitor = () => {
   const [editingServer, setEditingServer] = useState(null);
   const [viewMode, setViewMode] = useState('grid'); // 'grid' ou 'list'
   const [showSettings, setShowSettings] = useState(false);
+  const [showImportExport, setShowImportExport] = useState(false);
+  const [showMaintenance, setShowMaintenance] = useState(false);
+  // Filtre de la liste (groupe, tags, statut, recherche)
+  const [filter, setFilter] = useState({ group: '', tags: [], status: '', search: '' });
+  const filterRef = useRef(filter);
   const [newServer, setNewServer] = useState({
     name: '',
     url: '',
@@ -47,12 +58,46 @@ const ServerMonitor = () => {
       });
 
     const interval = setInterval(loadServers, 5000);
-    return () => clearInterval(interval);
+
+    // Fichiers de configuration modifiés sur le disque et rechargés à chaud
+    const offReload = EventsOn('config:reloaded', (event) => {
+      const file = { servers: 'servers.json', settings: 'settings.json' }[event.file]
+        ?? 'Fichier de configuration';
+      if (event.error) {
+        toast.error(`${file} ignoré: ${event.error}`);
+        return;
+      }
+      toast.success(`${file} rechargé`);
+      // Le fichier déclaratif peut modifier serveurs et settings
+      if (event.file !== 'settings') {
+        loadServers();
+      }
+      if (event.file !== 'servers') {
+        GetSettings()
+          .then((s) => {
+            if (['auto', 'light', 'dark'].includes(s.theme)) {
+              setThemeManually(s.theme);
+            }
+          })
+          .catch((err) => console.error('Impossible de recharger les settings :', err));
+      }
+    });
+
+    return () => {
+      clearInterval(interval);
+      offReload();
+    };
   }, []);
 
+  // Recharger la liste dès que le filtre change
+  useEffect(() => {
+    filterRef.current = filter;
+    loadServers();
+  }, [filter]);
+
   const loadServers = async () => {
     try {
-      const serverList = await GetServers();
+      const serverList = await GetServers(filterRef.current);
       setServers(serverList);
     } catch (error) {
       console.error('Failed to load servers: ', error);
@@ -60,7 +105,8 @@ const ServerMonitor = () => {
   };
 
   const handleAddServer = async () => {
-    if (!newServer.name || !newServer.url) return;
+    // Les checks push reçoivent leur URL de ping du backend
+    if (!newServer.name || (!newServer.url && newServer.type !== 'push')) return;
     try {
       await AddServer(newServer);
       setNewServer({ name: '', url: '', type: 'http', interval: '30s', timeout: '10s' });
@@ -84,13 +130,8 @@ const ServerMonitor = () => {
 
   const handleEditServer = (server) => {
     setEditingServer(server);
-    setNewServer({
-      name: server.name,
-      url: server.url,
-      type: server.type,
-      interval: server.interval,
-      timeout: server.timeout
-    });
+    // Copie complète pour conserver les champs avancés (seuils critiques...)
+    setNewServer({ ...server });
     setShowAddForm(true);
   };
 
@@ -121,6 +162,22 @@ const ServerMonitor = () => {
     }
   };
 
+  // Pause ou reprise du monitoring sans toucher à la configuration
+  const handleTogglePause = async (server) => {
+    try {
+      if (server.paused) {
+        await ResumeServer(server.id);
+        toast.success(`Monitoring de ${server.name} repris`);
+      } else {
+        await PauseServer(server.id);
+        toast.success(`${server.name} mis en pause`);
+      }
+      loadServers();
+    } catch (err) {
+      toast.error(`Erreur pour ${server.name}: ${err}`);
+    }
+  };
+
   const handleSettingsClose = () => {
     setShowSettings(false);
     GetSettings()
@@ -134,6 +191,10 @@ const ServerMonitor = () => {
       );
   };
 
+  const handleGroupClick = (group) => setFilter((prev) => ({ ...prev, group }));
+  const handleTagClick = (tag) => setFilter((prev) => ({ ...prev, tags: [tag] }));
+  const isFiltered = Boolean(filter.group || filter.tags.length || filter.status || filter.search);
+
   const upServers = servers.filter((s) => s.status?.is_up).length;
   const totalServers = servers.length;
 
@@ -175,10 +236,19 @@ const ServerMonitor = () => {
           totalServers={totalServers}
           onAddClick={() => setShowAddForm(true)}
           OpenSettings={() => setShowSettings(true)}
+          onImportExportClick={() => setShowImportExport(true)}
+          onMaintenanceClick={() => setShowMaintenance(true)}
           viewMode={viewMode}
           onViewModeChange={setViewMode}
         />
 
+        <ServerFilters
+          filter={filter}
+          onFilterChange={setFilter}
+          servers={servers}
+          onBulkDone={loadServers}
+        />
+
         {/* Conteneur des serveurs */}
         <div
           className={`mb-8 ${viewMode === 'grid'
@@ -193,6 +263,9 @@ const ServerMonitor = () => {
               onEdit={handleEditServer}
               onDelete={handleDeleteServer}
               onManualCheck={handleManualCheck}
+              onGroupClick={handleGroupClick}
+              onTagClick={handleTagClick}
+              onTogglePause={handleTogglePause}
               isHorizontal={viewMode === 'list'}
             />
           ))}
@@ -222,8 +295,25 @@ const ServerMonitor = () => {
         {/* Modal des réglages */}
         {showSettings && <Settings onClose={handleSettingsClose} />}
 
+        {/* Modal d'import / export */}
+        {showImportExport && (
+          <ImportExport onClose={() => setShowImportExport(false)} onImported={loadServers} />
+        )}
+
+        {/* Modal des maintenances planifiées */}
+        {showMaintenance && (
+          <MaintenanceWindows onClose={() => { setShowMaintenance(false); loadServers(); }} />
+        )}
+
+        {/* Message si aucun serveur ne correspond au filtre */}
+        {servers.length === 0 && isFiltered && (
+          <div className="text-center py-16 text-sm text-gray-500 dark:text-gray-400">
+            Aucun serveur ne correspond aux filtres
+          </div>
+        )}
+
         {/* Message si aucun serveur */}
-        {servers.length === 0 && (
+        {servers.length === 0 && !isFiltered && (
           <div className="flex items-center justify-center py-20">
             <div className="bg-white/70 dark:bg-gray-800/70 backdrop-blur-xl rounded-2xl p-10 max-w-md w-full border border-gray-200/50 dark:border-gray-700/50 shadow-xl">
               <div className="w-16 h-16 bg-gradient-to-br from-blue-400 to-blue-600 rounded-2xl flex items-center justify-center mx-auto mb-5 shadow-lg">
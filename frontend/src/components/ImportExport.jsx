@@ -0,0 +1,318 @@
+// Composant ImportExport - Import et export de la liste des serveurs
+// Export en JSON, YAML ou CSV; import (y compris depuis Uptime Kuma, Nagios
+// ou Prometheus blackbox_exporter) avec prévisualisation avant application
+
+import { AlertCircle, CheckCircle, Download, Eye, Upload, X } from 'lucide-react';
+import { useState } from 'react';
+import toast from 'react-hot-toast';
+import { ExportServers, ImportServers } from '../../wailsjs/go/main/App';
+
+// Libellés des actions du compte rendu d'import
+const ACTION_LABELS = {
+  add: 'Ajout',
+  update: 'Mise à jour',
+  unchanged: 'Inchangé',
+  skip: 'Ignoré',
+  duplicate: 'Copie',
+  error: 'Erreur',
+};
+
+const ACTION_STYLES = {
+  add: 'text-green-600 dark:text-green-400',
+  update: 'text-blue-600 dark:text-blue-400',
+  duplicate: 'text-blue-600 dark:text-blue-400',
+  error: 'text-red-600 dark:text-red-400',
+};
+
+const MIME_TYPES = {
+  json: 'application/json',
+  yaml: 'application/yaml',
+  csv: 'text/csv',
+};
+
+const selectClass = 'w-full px-2.5 py-1.5 text-sm bg-white dark:bg-gray-700 border border-gray-300 dark:border-gray-600 rounded-md text-gray-900 dark:text-white';
+const labelClass = 'block text-xs font-medium text-gray-600 dark:text-gray-400 mb-1';
+
+/**
+ * Fenêtre d'import/export des serveurs
+ * @param {Function} onClose - Fermeture de la fenêtre
+ * @param {Function} onImported - Appelée après un import appliqué (rechargement des serveurs)
+ */
+const ImportExport = ({ onClose, onImported }) => {
+  const [exportOptions, setExportOptions] = useState({ format: 'json', include_history: false, include_settings: false, include_push_tokens: false });
+  const [importOptions, setImportOptions] = useState({ format: '', mode: 'merge', match_by: 'id_url', conflict: 'skip', settings: false });
+  const [fileName, setFileName] = useState('');
+  const [content, setContent] = useState('');
+  const [report, setReport] = useState(null);
+  const [error, setError] = useState('');
+  const [busy, setBusy] = useState(false);
+
+  const csvExport = exportOptions.format === 'csv';
+
+  const handleExport = async () => {
+    try {
+      const data = await ExportServers(exportOptions);
+      const blob = new Blob([data], { type: MIME_TYPES[exportOptions.format] });
+      const link = document.createElement('a');
+      link.href = URL.createObjectURL(blob);
+      link.download = `serveurs.${exportOptions.format}`;
+      link.click();
+      URL.revokeObjectURL(link.href);
+      toast.success('Export téléchargé');
+    } catch (err) {
+      toast.error(`Export impossible: ${err}`);
+    }
+  };
+
+  const handleFile = async (e) => {
+    const file = e.target.files?.[0];
+    setReport(null);
+    setError('');
+    if (!file) return;
+    setFileName(file.name);
+    setContent(await file.text());
+    // CSV et Nagios d'après l'extension; JSON et YAML pouvant venir d'un autre
+    // outil (Uptime Kuma, Prometheus), leur format est détecté par le backend
+    const ext = file.name.split('.').pop().toLowerCase();
+    setImportOptions((prev) => ({ ...prev, format: { csv: 'csv', cfg: 'nagios' }[ext] ?? '' }));
+  };
+
+  const runImport = async (dryRun) => {
+    setBusy(true);
+    setError('');
+    try {
+      const result = await ImportServers(content, { ...importOptions, dry_run: dryRun });
+      setReport(result);
+      if (result.applied) {
+        toast.success('Import appliqué');
+        if (onImported) onImported();
+      }
+    } catch (err) {
+      setReport(null);
+      setError(String(err));
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  const counts = (report?.items ?? []).reduce((acc, item) => {
+    acc[item.action] = (acc[item.action] ?? 0) + 1;
+    return acc;
+  }, {});
+
+  return (
+    <div className="fixed inset-0 bg-black/30 backdrop-blur-sm flex items-center justify-center z-50 p-4">
+      <div className="bg-white/95 dark:bg-gray-800/95 backdrop-blur-xl w-full max-w-2xl rounded-xl shadow-2xl border border-gray-200/50 dark:border-gray-700/50 max-h-[90vh] overflow-hidden flex flex-col">
+
+        {/* Header macOS style */}
+        <div className="h-14 px-5 border-b border-gray-200/50 dark:border-gray-700/50 flex items-center justify-between bg-white/50 dark:bg-gray-800/50">
+          <h2 className="text-base font-semibold text-gray-900 dark:text-white">Import / Export</h2>
+          <button
+            onClick={onClose}
+            className="p-1.5 hover:bg-gray-100 dark:hover:bg-gray-700 rounded-lg transition-colors group"
+          >
+            <X className="w-4 h-4 text-gray-400 group-hover:text-gray-600 dark:group-hover:text-gray-300" />
+          </button>
+        </div>
+
+        <div className="flex-1 overflow-y-auto p-5 space-y-5">
+
+          {/* Export */}
+          <section className="space-y-3">
+            <h3 className="text-sm font-medium text-gray-700 dark:text-gray-300">Exporter</h3>
+            <div className="grid grid-cols-3 gap-3 items-end">
+              <div>
+                <label className={labelClass}>Format</label>
+                <select
+                  className={selectClass}
+                  value={exportOptions.format}
+                  onChange={(e) => setExportOptions({
+                    ...exportOptions,
+                    format: e.target.value,
+                    // Le CSV ne contient que les serveurs
+                    ...(e.target.value === 'csv' && { include_history: false, include_settings: false }),
+                  })}
+                >
+                  <option value="json">JSON</option>
+                  <option value="yaml">YAML</option>
+                  <option value="csv">CSV</option>
+                </select>
+              </div>
+              <label className={`flex items-center gap-2 text-sm text-gray-700 dark:text-gray-300 ${csvExport ? 'opacity-50' : ''}`}>
+                <input
+                  type="checkbox"
+                  disabled={csvExport}
+                  checked={exportOptions.include_history}
+                  onChange={(e) => setExportOptions({ ...exportOptions, include_history: e.target.checked })}
+                />
+                Historique
+              </label>
+              <label className={`flex items-center gap-2 text-sm text-gray-700 dark:text-gray-300 ${csvExport ? 'opacity-50' : ''}`}>
+                <input
+                  type="checkbox"
+                  disabled={csvExport}
+                  checked={exportOptions.include_settings}
+                  onChange={(e) => setExportOptions({ ...exportOptions, include_settings: e.target.checked })}
+                />
+                Paramètres (sans secrets)
+              </label>
+            </div>
+            <label className="flex items-start gap-2 text-sm text-gray-700 dark:text-gray-300">
+              <input
+                type="checkbox"
+                className="mt-0.5"
+                checked={exportOptions.include_push_tokens}
+                onChange={(e) => setExportOptions({ ...exportOptions, include_push_tokens: e.target.checked })}
+              />
+              <span>
+                URLs de ping des checks push
+                <span className="block text-2xs text-gray-400">
+                  Secrètes: quiconque les connaît peut signaler un serveur en ligne. Sans elles, l'import génère de nouvelles URLs
+                </span>
+              </span>
+            </label>
+            <button
+              onClick={handleExport}
+              className="inline-flex items-center gap-2 px-3 py-1.5 bg-blue-500 hover:bg-blue-600 text-white text-xs font-medium rounded-md shadow-sm"
+            >
+              <Download className="w-3.5 h-3.5" />
+              Exporter
+            </button>
+          </section>
+
+          <div className="border-t border-gray-200/50 dark:border-gray-700/50" />
+
+          {/* Import */}
+          <section className="space-y-3">
+            <h3 className="text-sm font-medium text-gray-700 dark:text-gray-300">Importer</h3>
+            <label className="flex items-center gap-2 text-sm text-gray-700 dark:text-gray-300">
+              <Upload className="w-4 h-4" />
+              <input type="file" accept=".json,.yaml,.yml,.csv,.cfg" onChange={handleFile} />
+            </label>
+            <div className="grid grid-cols-2 gap-3">
+              <div>
+                <label className={labelClass}>Format</label>
+                <select className={selectClass} value={importOptions.format} onChange={(e) => setImportOptions({ ...importOptions, format: e.target.value })}>
+                  <option value="">Détection automatique</option>
+                  <option value="json">JSON</option>
+                  <option value="yaml">YAML</option>
+                  <option value="csv">CSV</option>
+                  <option value="uptimekuma">Sauvegarde Uptime Kuma</option>
+                  <option value="nagios">Objets Nagios (.cfg)</option>
+                  <option value="blackbox">Prometheus blackbox (prometheus.yml, file_sd)</option>
+                </select>
+              </div>
+              <div>
+                <label className={labelClass}>Mode</label>
+                <select className={selectClass} value={importOptions.mode} onChange={(e) => setImportOptions({ ...importOptions, mode: e.target.value })}>
+                  <option value="merge">Fusionner (serveurs actuels conservés)</option>
+                  <option value="replace">Remplacer (serveurs absents supprimés)</option>
+                </select>
+              </div>
+              <div>
+                <label className={labelClass}>Serveur déjà présent si même</label>
+                <select className={selectClass} value={importOptions.match_by} onChange={(e) => setImportOptions({ ...importOptions, match_by: e.target.value })}>
+                  <option value="id_url">Identifiant ou URL</option>
+                  <option value="id">Identifiant</option>
+                  <option value="url">URL</option>
+                </select>
+              </div>
+              <div>
+                <label className={labelClass}>En cas de conflit</label>
+                <select className={selectClass} value={importOptions.conflict} onChange={(e) => setImportOptions({ ...importOptions, conflict: e.target.value })}>
+                  <option value="skip">Garder l'existant</option>
+                  <option value="overwrite">Remplacer par l'import</option>
+                  <option value="duplicate">Ajouter une copie</option>
+                </select>
+              </div>
+            </div>
+            <label className="flex items-center gap-2 text-sm text-gray-700 dark:text-gray-300">
+              <input
+                type="checkbox"
+                checked={importOptions.settings}
+                onChange={(e) => setImportOptions({ ...importOptions, settings: e.target.checked })}
+              />
+              Importer aussi les paramètres (secrets actuels conservés)
+            </label>
+            <div className="flex gap-2">
+              <button
+                disabled={!content || busy}
+                onClick={() => runImport(true)}
+                className="inline-flex items-center gap-2 px-3 py-1.5 bg-gray-100 dark:bg-gray-700 hover:bg-gray-200 dark:hover:bg-gray-600 text-gray-700 dark:text-gray-200 text-xs font-medium rounded-md disabled:opacity-50"
+              >
+                <Eye className="w-3.5 h-3.5" />
+                Prévisualiser
+              </button>
+              <button
+                disabled={!content || busy || !report?.dry_run || report.errors.length > 0}
+                onClick={() => runImport(false)}
+                className="inline-flex items-center gap-2 px-3 py-1.5 bg-blue-500 hover:bg-blue-600 text-white text-xs font-medium rounded-md shadow-sm disabled:opacity-50"
+                title="Prévisualisez l'import avant de l'appliquer"
+              >
+                <Upload className="w-3.5 h-3.5" />
+                Importer {fileName}
+              </button>
+            </div>
+
+            {error && (
+              <div className="p-3 rounded-lg flex items-start gap-2 text-sm bg-red-50 dark:bg-red-500/10 text-red-700 dark:text-red-300 border border-red-200 dark:border-red-500/20">
+                <AlertCircle size={14} className="mt-0.5 shrink-0" />
+                <pre className="whitespace-pre-wrap font-sans">{error}</pre>
+              </div>
+            )}
+
+            {/* Compte rendu (prévisualisation ou import appliqué) */}
+            {report && (
+              <div className="space-y-2">
+                <div className="flex items-center gap-2 text-sm text-gray-700 dark:text-gray-300">
+                  {report.errors.length > 0
+                    ? <AlertCircle size={14} className="text-red-500" />
+                    : <CheckCircle size={14} className="text-green-500" />}
+                  <span>
+                    {report.applied ? 'Import appliqué' : 'Prévisualisation'} ({report.format}) :{' '}
+                    {Object.entries(counts).map(([action, n]) => `${n} ${ACTION_LABELS[action].toLowerCase()}`).join(', ') || 'aucun serveur'}
+                    {report.removed.length > 0 && `, ${report.removed.length} suppression(s)`}
+                    {report.settings && ', paramètres'}
+                  </span>
+                </div>
+                <table className="w-full text-xs">
+                  <tbody>
+                    {report.items.map((item) => (
+                      <tr key={`${item.row}-${item.id}`} className="border-t border-gray-200/50 dark:border-gray-700/50">
+                        <td className="py-1 pr-2 text-gray-400">{item.row}</td>
+                        <td className="py-1 pr-2 text-gray-900 dark:text-white">{item.name}</td>
+                        <td className="py-1 pr-2 text-gray-500 dark:text-gray-400 truncate max-w-[12rem]">{item.url}</td>
+                        <td className={`py-1 pr-2 font-medium ${ACTION_STYLES[item.action] ?? 'text-gray-500 dark:text-gray-400'}`}>
+                          {ACTION_LABELS[item.action]}
+                        </td>
+                        <td className="py-1 text-gray-500 dark:text-gray-400">{item.reason}</td>
+                      </tr>
+                    ))}
+                    {report.removed.map((name) => (
+                      <tr key={`removed-${name}`} className="border-t border-gray-200/50 dark:border-gray-700/50">
+                        <td />
+                        <td className="py-1 pr-2 text-gray-900 dark:text-white">{name}</td>
+                        <td />
+                        <td className="py-1 pr-2 font-medium text-red-600 dark:text-red-400">Suppression</td>
+                        <td />
+                      </tr>
+                    ))}
+                  </tbody>
+                </table>
+                {/* Éléments d'un autre outil ignorés ou traduits approximativement */}
+                {report.warnings.map((w) => (
+                  <p key={w} className="text-xs text-yellow-700 dark:text-yellow-400">{w}</p>
+                ))}
+                {report.errors.filter((e) => e.startsWith('settings')).map((e) => (
+                  <p key={e} className="text-xs text-red-600 dark:text-red-400">{e}</p>
+                ))}
+              </div>
+            )}
+          </section>
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default ImportExport;
@@ -0,0 +1,387 @@
+// Composant MaintenanceWindows - Planification des fenêtres de maintenance
+// Fenêtres ponctuelles, récurrentes (cron) ou par plages horaires hebdomadaires,
+// attachées à des serveurs ou à des groupes: les checks continuent mais les
+// alertes sont suspendues pendant la fenêtre
+
+import { Edit, Plus, Trash2, Wrench, X } from 'lucide-react';
+import { useEffect, useState } from 'react';
+import toast from 'react-hot-toast';
+import {
+  GetGroups,
+  GetMaintenanceWindows,
+  GetServers,
+  SaveMaintenanceWindows,
+  ValidateMaintenanceWindows,
+} from '../../wailsjs/go/main/App';
+
+// Jours de la semaine (valeurs attendues par le backend)
+const DAYS = [
+  ['mon', 'Lun'], ['tue', 'Mar'], ['wed', 'Mer'], ['thu', 'Jeu'],
+  ['fri', 'Ven'], ['sat', 'Sam'], ['sun', 'Dim'],
+];
+
+const KIND_LABELS = {
+  once: 'Ponctuelle',
+  cron: 'Récurrente (cron)',
+  weekly: 'Plage horaire hebdomadaire',
+};
+
+const COMMON_TIMEZONES = ['Europe/Paris', 'Europe/London', 'UTC', 'America/New_York', 'America/Montreal', 'Asia/Tokyo'];
+
+const inputClass = 'w-full px-2.5 py-1.5 text-sm bg-white dark:bg-gray-700 border border-gray-300 dark:border-gray-600 rounded-md text-gray-900 dark:text-white';
+const labelClass = 'block text-xs font-medium text-gray-600 dark:text-gray-400 mb-1';
+
+const emptyDraft = { name: '', servers: [], groups: [], timezone: '', start: '', end: '', cron: '', duration: '', days: [], from: '', to: '' };
+
+// Type de planification d'une fenêtre
+const windowKind = (w) => (w.cron ? 'cron' : w.start || w.end ? 'once' : 'weekly');
+
+// Fenêtre envoyée au backend: seuls les champs du type choisi sont conservés
+const toWindow = (draft, kind) => {
+  const base = { name: draft.name.trim(), servers: draft.servers, groups: draft.groups, timezone: draft.timezone.trim() };
+  switch (kind) {
+    case 'once':
+      return { ...base, start: draft.start.replace('T', ' '), end: draft.end.replace('T', ' ') };
+    case 'cron':
+      return { ...base, cron: draft.cron.trim(), duration: draft.duration.trim() };
+    default:
+      return { ...base, days: draft.days, from: draft.from, to: draft.to };
+  }
+};
+
+// Résumé lisible de la planification
+const describe = (w) => {
+  const zone = w.timezone ? ` (${w.timezone})` : '';
+  switch (windowKind(w)) {
+    case 'once':
+      return `Du ${w.start} au ${w.end}${zone}`;
+    case 'cron':
+      return `« ${w.cron} » pendant ${w.duration}${zone}`;
+    default: {
+      const days = w.days?.length ? DAYS.filter(([d]) => w.days.includes(d)).map(([, l]) => l).join(', ') : 'Tous les jours';
+      return `${days}, ${w.from} – ${w.to}${zone}`;
+    }
+  }
+};
+
+// Cible de la fenêtre (serveurs et groupes)
+const describeTargets = (w, serverNames) => {
+  const targets = [
+    ...(w.groups || []).map((g) => `groupe ${g}`),
+    ...(w.servers || []).map((id) => serverNames[id] || id),
+  ];
+  return targets.length ? targets.join(', ') : 'Tous les serveurs';
+};
+
+/**
+ * Fenêtre de gestion des maintenances planifiées
+ * @param {Function} onClose - Fermeture de la fenêtre
+ */
+const MaintenanceWindows = ({ onClose }) => {
+  const [statuses, setStatuses] = useState([]);
+  const [servers, setServers] = useState([]);
+  const [groups, setGroups] = useState([]);
+  const [editing, setEditing] = useState(null); // Index de la fenêtre modifiée, -1 pour une nouvelle
+  const [draft, setDraft] = useState(emptyDraft);
+  const [kind, setKind] = useState('cron');
+  const [errors, setErrors] = useState([]);
+
+  const load = () => {
+    GetMaintenanceWindows()
+      .then(setStatuses)
+      .catch((err) => console.error('Impossible de charger les maintenances :', err));
+  };
+
+  useEffect(() => {
+    load();
+    GetServers({ group: '', tags: [], status: '', search: '' }).then(setServers).catch(() => {});
+    GetGroups().then((list) => setGroups(list.map((g) => g.path))).catch(() => {});
+  }, []);
+
+  const windows = statuses.map((s) => s.window);
+  const serverNames = Object.fromEntries(servers.map((s) => [s.id, s.name]));
+
+  const startEdit = (index) => {
+    const w = index >= 0 ? windows[index] : emptyDraft;
+    setEditing(index);
+    setKind(index >= 0 ? windowKind(w) : 'cron');
+    setDraft({
+      ...emptyDraft,
+      ...w,
+      servers: w.servers || [],
+      groups: w.groups || [],
+      days: w.days || [],
+      start: (w.start || '').replace(' ', 'T'),
+      end: (w.end || '').replace(' ', 'T'),
+    });
+    setErrors([]);
+  };
+
+  const toggle = (field, value) => setDraft((prev) => ({
+    ...prev,
+    [field]: prev[field].includes(value) ? prev[field].filter((v) => v !== value) : [...prev[field], value],
+  }));
+
+  // Enregistre la liste complète après validation par le backend
+  const persist = async (next, index) => {
+    const fieldErrors = await ValidateMaintenanceWindows(next);
+    const prefix = `maintenanceWindows[${index}]`;
+    const own = fieldErrors.filter((e) => e.field.startsWith(prefix));
+    if (own.length > 0 || (index < 0 && fieldErrors.length > 0)) {
+      setErrors((own.length > 0 ? own : fieldErrors).map((e) => e.message));
+      return false;
+    }
+    try {
+      await SaveMaintenanceWindows(next);
+      load();
+      return true;
+    } catch (err) {
+      setErrors([String(err)]);
+      return false;
+    }
+  };
+
+  const handleSave = async () => {
+    const window = toWindow(draft, kind);
+    const index = editing >= 0 ? editing : windows.length;
+    const next = [...windows];
+    next[index] = window;
+    if (await persist(next, index)) {
+      toast.success(`Maintenance « ${window.name} » enregistrée`);
+      setEditing(null);
+    }
+  };
+
+  const handleDelete = async (index) => {
+    const name = windows[index].name;
+    if (!window.confirm(`Supprimer la maintenance « ${name} » ?`)) return;
+    if (await persist(windows.filter((_, i) => i !== index), -1)) {
+      toast.success(`Maintenance « ${name} » supprimée`);
+    }
+  };
+
+  const formatNext = (next) => new Date(next).toLocaleString('fr-FR', { weekday: 'short', day: '2-digit', month: '2-digit', hour: '2-digit', minute: '2-digit' });
+
+  return (
+    <div className="fixed inset-0 bg-black/30 backdrop-blur-sm flex items-center justify-center z-50 p-4">
+      <div className="bg-white/95 dark:bg-gray-800/95 backdrop-blur-xl w-full max-w-2xl rounded-xl shadow-2xl border border-gray-200/50 dark:border-gray-700/50 max-h-[90vh] overflow-hidden flex flex-col">
+
+        {/* Header macOS style */}
+        <div className="h-14 px-5 border-b border-gray-200/50 dark:border-gray-700/50 flex items-center justify-between bg-white/50 dark:bg-gray-800/50">
+          <h2 className="text-base font-semibold text-gray-900 dark:text-white">Maintenances planifiées</h2>
+          <button
+            onClick={onClose}
+            className="p-1.5 hover:bg-gray-100 dark:hover:bg-gray-700 rounded-lg transition-colors group"
+          >
+            <X className="w-4 h-4 text-gray-400 group-hover:text-gray-600 dark:group-hover:text-gray-300" />
+          </button>
+        </div>
+
+        <div className="flex-1 overflow-y-auto p-5 space-y-4">
+          <p className="text-xs text-gray-500 dark:text-gray-400">
+            Pendant une maintenance, les serveurs concernés restent vérifiés et leurs incidents enregistrés,
+            mais aucune alerte n'est envoyée. Une panne toujours en cours à la fin de la fenêtre est alors notifiée.
+          </p>
+
+          {/* Liste des fenêtres */}
+          {statuses.length === 0 && editing === null && (
+            <p className="text-sm text-gray-500 dark:text-gray-400 text-center py-6">Aucune maintenance planifiée</p>
+          )}
+          {statuses.map((status, index) => (
+            <div
+              key={`${status.window.name}-${index}`}
+              className="flex items-start gap-3 p-3 rounded-lg border border-gray-200/60 dark:border-gray-700/60 bg-gray-50/50 dark:bg-gray-900/50"
+            >
+              <Wrench className={`w-4 h-4 mt-0.5 ${status.active ? 'text-amber-500' : 'text-gray-400'}`} />
+              <div className="flex-1 min-w-0">
+                <div className="flex items-center gap-2">
+                  <span className="text-sm font-medium text-gray-900 dark:text-white">{status.window.name}</span>
+                  {status.active && (
+                    <span className="px-1.5 py-0.5 rounded text-2xs font-medium bg-amber-100 dark:bg-amber-500/20 text-amber-700 dark:text-amber-300">
+                      En cours
+                    </span>
+                  )}
+                </div>
+                <p className="text-xs text-gray-600 dark:text-gray-300">{describe(status.window)}</p>
+                <p className="text-xs text-gray-500 dark:text-gray-400 truncate">{describeTargets(status.window, serverNames)}</p>
+                {status.next && (
+                  <p className="text-xs text-gray-400 dark:text-gray-500">Prochain début : {formatNext(status.next)}</p>
+                )}
+              </div>
+              <button
+                onClick={() => startEdit(index)}
+                className="p-1.5 text-gray-400 hover:text-blue-500 dark:hover:text-blue-400 hover:bg-blue-50 dark:hover:bg-blue-500/10 rounded-lg transition-colors"
+                title="Modifier"
+              >
+                <Edit className="w-3.5 h-3.5" />
+              </button>
+              <button
+                onClick={() => handleDelete(index)}
+                className="p-1.5 text-gray-400 hover:text-red-500 dark:hover:text-red-400 hover:bg-red-50 dark:hover:bg-red-500/10 rounded-lg transition-colors"
+                title="Supprimer"
+              >
+                <Trash2 className="w-3.5 h-3.5" />
+              </button>
+            </div>
+          ))}
+
+          {editing === null ? (
+            <button
+              onClick={() => startEdit(-1)}
+              className="inline-flex items-center gap-2 px-3 py-1.5 bg-blue-500 hover:bg-blue-600 text-white text-xs font-medium rounded-md shadow-sm"
+            >
+              <Plus className="w-3.5 h-3.5" />
+              Nouvelle maintenance
+            </button>
+          ) : (
+            /* Formulaire de la fenêtre */
+            <section className="space-y-3 p-4 rounded-lg border border-blue-200/60 dark:border-blue-500/30 bg-blue-50/30 dark:bg-blue-500/10">
+              <div className="grid grid-cols-2 gap-3">
+                <div>
+                  <label className={labelClass}>Nom</label>
+                  <input
+                    className={inputClass}
+                    value={draft.name}
+                    onChange={(e) => setDraft({ ...draft, name: e.target.value })}
+                    placeholder="Déploiement hebdomadaire"
+                  />
+                </div>
+                <div>
+                  <label className={labelClass}>Planification</label>
+                  <select className={inputClass} value={kind} onChange={(e) => setKind(e.target.value)}>
+                    {Object.entries(KIND_LABELS).map(([value, label]) => (
+                      <option key={value} value={value}>{label}</option>
+                    ))}
+                  </select>
+                </div>
+              </div>
+
+              {kind === 'once' && (
+                <div className="grid grid-cols-2 gap-3">
+                  <div>
+                    <label className={labelClass}>Début</label>
+                    <input type="datetime-local" className={inputClass} value={draft.start} onChange={(e) => setDraft({ ...draft, start: e.target.value })} />
+                  </div>
+                  <div>
+                    <label className={labelClass}>Fin</label>
+                    <input type="datetime-local" className={inputClass} value={draft.end} onChange={(e) => setDraft({ ...draft, end: e.target.value })} />
+                  </div>
+                </div>
+              )}
+
+              {kind === 'cron' && (
+                <div className="grid grid-cols-2 gap-3">
+                  <div>
+                    <label className={labelClass}>Début (cron)</label>
+                    <input
+                      className={`${inputClass} font-mono`}
+                      value={draft.cron}
+                      onChange={(e) => setDraft({ ...draft, cron: e.target.value })}
+                      placeholder="0 2 * * sun"
+                    />
+                    <p className="mt-1 text-2xs text-gray-400">minute heure jour mois jour-de-semaine</p>
+                  </div>
+                  <div>
+                    <label className={labelClass}>Durée</label>
+                    <input
+                      className={inputClass}
+                      value={draft.duration}
+                      onChange={(e) => setDraft({ ...draft, duration: e.target.value })}
+                      placeholder="2h"
+                    />
+                  </div>
+                </div>
+              )}
+
+              {kind === 'weekly' && (
+                <div className="space-y-2">
+                  <div className="flex flex-wrap gap-1">
+                    {DAYS.map(([day, label]) => (
+                      <button
+                        key={day}
+                        onClick={() => toggle('days', day)}
+                        className={`px-2 py-1 rounded-md text-xs font-medium ${draft.days.includes(day)
+                          ? 'bg-blue-500 text-white'
+                          : 'bg-gray-100 dark:bg-gray-700 text-gray-600 dark:text-gray-300'
+                        }`}
+                      >
+                        {label}
+                      </button>
+                    ))}
+                    <span className="text-2xs text-gray-400 self-center ml-1">aucun jour = tous les jours</span>
+                  </div>
+                  <div className="grid grid-cols-2 gap-3">
+                    <div>
+                      <label className={labelClass}>De</label>
+                      <input type="time" className={inputClass} value={draft.from} onChange={(e) => setDraft({ ...draft, from: e.target.value })} />
+                    </div>
+                    <div>
+                      <label className={labelClass}>À (le lendemain si plus tôt)</label>
+                      <input type="time" className={inputClass} value={draft.to} onChange={(e) => setDraft({ ...draft, to: e.target.value })} />
+                    </div>
+                  </div>
+                </div>
+              )}
+
+              <div>
+                <label className={labelClass}>Fuseau horaire</label>
+                <input
+                  className={inputClass}
+                  list="maintenance-timezones"
+                  value={draft.timezone}
+                  onChange={(e) => setDraft({ ...draft, timezone: e.target.value })}
+                  placeholder={`Fuseau local (${Intl.DateTimeFormat().resolvedOptions().timeZone})`}
+                />
+                <datalist id="maintenance-timezones">
+                  {COMMON_TIMEZONES.map((zone) => <option key={zone} value={zone} />)}
+                </datalist>
+              </div>
+
+              {/* Cibles: groupes et serveurs (aucune = tous les serveurs) */}
+              <div>
+                <label className={labelClass}>Serveurs concernés (aucun = tous)</label>
+                <div className="max-h-36 overflow-y-auto space-y-1 p-2 bg-white dark:bg-gray-800 border border-gray-200 dark:border-gray-600 rounded-md">
+                  {groups.map((group) => (
+                    <label key={`g-${group}`} className="flex items-center gap-2 text-xs text-gray-700 dark:text-gray-300">
+                      <input type="checkbox" checked={draft.groups.includes(group)} onChange={() => toggle('groups', group)} />
+                      Groupe {group}
+                    </label>
+                  ))}
+                  {servers.map((server) => (
+                    <label key={server.id} className="flex items-center gap-2 text-xs text-gray-700 dark:text-gray-300">
+                      <input type="checkbox" checked={draft.servers.includes(server.id)} onChange={() => toggle('servers', server.id)} />
+                      {server.name}
+                      <span className="text-gray-400 truncate">{server.url}</span>
+                    </label>
+                  ))}
+                </div>
+              </div>
+
+              {errors.map((message) => (
+                <p key={message} className="text-xs text-red-600 dark:text-red-400">{message}</p>
+              ))}
+
+              <div className="flex justify-end gap-2">
+                <button
+                  onClick={() => setEditing(null)}
+                  className="px-3 py-1.5 text-xs font-medium text-gray-700 dark:text-gray-300 bg-white dark:bg-gray-800 border border-gray-200 dark:border-gray-600 rounded-md"
+                >
+                  Annuler
+                </button>
+                <button
+                  onClick={handleSave}
+                  disabled={!draft.name.trim()}
+                  className="px-4 py-1.5 text-xs font-medium rounded-md bg-blue-500 hover:bg-blue-600 disabled:bg-gray-300 dark:disabled:bg-gray-600 text-white"
+                >
+                  Enregistrer
+                </button>
+              </div>
+            </section>
+          )}
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default MaintenanceWindows;
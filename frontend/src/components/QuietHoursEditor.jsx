@@ -0,0 +1,169 @@
+// Composant QuietHoursEditor - Édition des heures calmes (nuits, week-ends...)
+// Pendant les plages calmes, seules les alertes critiques sont envoyées ou
+// toutes les alertes sont regroupées dans un résumé à la fin de la période.
+// Utilisé pour le réglage global, par type de check et par serveur
+
+import { Moon, Plus, Trash2 } from 'lucide-react';
+
+// Jours de la semaine (valeurs attendues par le backend)
+const DAYS = [
+  ['mon', 'Lun'], ['tue', 'Mar'], ['wed', 'Mer'], ['thu', 'Jeu'],
+  ['fri', 'Ven'], ['sat', 'Sam'], ['sun', 'Dim'],
+];
+
+// Plages prédéfinies
+const PRESETS = [
+  { label: 'Nuits', period: { days: [], from: '22:00', to: '07:00' } },
+  { label: 'Week-end', period: { days: ['sat', 'sun'], from: '00:00', to: '00:00' } },
+];
+
+const COMMON_TIMEZONES = ['Europe/Paris', 'Europe/London', 'UTC', 'America/New_York', 'America/Montreal', 'Asia/Tokyo'];
+
+const inputClass = 'w-full px-2.5 py-1.5 text-sm bg-white dark:bg-gray-700 border border-gray-300 dark:border-gray-600 rounded-md text-gray-900 dark:text-white';
+const labelClass = 'block text-xs font-medium text-gray-600 dark:text-gray-400 mb-1';
+
+// Heures calmes désactivées (valeur de départ)
+export const emptyQuietHours = { enabled: false, mode: 'critical', timezone: '', periods: [] };
+
+// Heures calmes complétées avec les valeurs par défaut
+export const normalizeQuietHours = (quiet) => ({
+  ...emptyQuietHours,
+  ...(quiet || {}),
+  mode: quiet?.mode || 'critical',
+  periods: quiet?.periods || [],
+});
+
+/**
+ * Éditeur d'heures calmes
+ * @param {Object} value - Heures calmes (enabled, mode, timezone, periods)
+ * @param {Function} onChange - Appelée avec les nouvelles heures calmes
+ * @param {string} error - Message d'erreur de validation (optionnel)
+ * @param {string} id - Préfixe unique des éléments du formulaire
+ */
+const QuietHoursEditor = ({ value, onChange, error, id = 'quiet' }) => {
+  const quiet = normalizeQuietHours(value);
+
+  const update = (changes) => onChange({ ...quiet, ...changes });
+
+  const updatePeriod = (index, changes) => update({
+    periods: quiet.periods.map((period, i) => (i === index ? { ...period, ...changes } : period)),
+  });
+
+  const toggleDay = (index, day) => {
+    const days = quiet.periods[index].days || [];
+    updatePeriod(index, { days: days.includes(day) ? days.filter((d) => d !== day) : [...days, day] });
+  };
+
+  return (
+    <div className="space-y-2">
+      <label className="flex items-center space-x-2 text-sm text-gray-700 dark:text-gray-300">
+        <input
+          type="checkbox"
+          checked={quiet.enabled}
+          onChange={(e) => update({
+            enabled: e.target.checked,
+            // Première activation: les nuits par défaut
+            periods: e.target.checked && quiet.periods.length === 0 ? [PRESETS[0].period] : quiet.periods,
+          })}
+          className="rounded"
+        />
+        <Moon size={14} className="text-indigo-500" />
+        <span>Heures calmes</span>
+      </label>
+
+      {quiet.enabled && (
+        <div className="space-y-2 pl-6">
+          <div className="grid grid-cols-2 gap-2">
+            <div>
+              <label className={labelClass}>Pendant les heures calmes</label>
+              <select className={inputClass} value={quiet.mode} onChange={(e) => update({ mode: e.target.value })}>
+                <option value="critical">Alertes critiques seulement</option>
+                <option value="digest">Résumé à la fin de la période</option>
+              </select>
+            </div>
+            <div>
+              <label className={labelClass}>Fuseau horaire</label>
+              <input
+                className={inputClass}
+                list={`${id}-timezones`}
+                value={quiet.timezone}
+                onChange={(e) => update({ timezone: e.target.value })}
+                placeholder={`Local (${Intl.DateTimeFormat().resolvedOptions().timeZone})`}
+              />
+              <datalist id={`${id}-timezones`}>
+                {COMMON_TIMEZONES.map((zone) => <option key={zone} value={zone} />)}
+              </datalist>
+            </div>
+          </div>
+
+          {quiet.periods.map((period, index) => (
+            <div key={index} className="flex flex-wrap items-center gap-1 p-2 rounded-md bg-white/60 dark:bg-gray-800/60 border border-gray-200 dark:border-gray-700">
+              {DAYS.map(([day, label]) => (
+                <button
+                  key={day}
+                  type="button"
+                  onClick={() => toggleDay(index, day)}
+                  className={`px-1.5 py-0.5 rounded text-2xs font-medium ${(period.days || []).includes(day)
+                    ? 'bg-indigo-500 text-white'
+                    : 'bg-gray-100 dark:bg-gray-700 text-gray-600 dark:text-gray-300'
+                  }`}
+                >
+                  {label}
+                </button>
+              ))}
+              <input
+                type="time"
+                className="px-1.5 py-0.5 text-xs bg-white dark:bg-gray-700 border border-gray-300 dark:border-gray-600 rounded text-gray-900 dark:text-white"
+                value={period.from}
+                onChange={(e) => updatePeriod(index, { from: e.target.value })}
+              />
+              <span className="text-xs text-gray-400">→</span>
+              <input
+                type="time"
+                className="px-1.5 py-0.5 text-xs bg-white dark:bg-gray-700 border border-gray-300 dark:border-gray-600 rounded text-gray-900 dark:text-white"
+                value={period.to}
+                onChange={(e) => updatePeriod(index, { to: e.target.value })}
+              />
+              <button
+                type="button"
+                onClick={() => update({ periods: quiet.periods.filter((_, i) => i !== index) })}
+                className="ml-auto p-1 text-gray-400 hover:text-red-500"
+                title="Supprimer la plage"
+              >
+                <Trash2 size={12} />
+              </button>
+            </div>
+          ))}
+
+          <div className="flex items-center gap-1">
+            <Plus size={12} className="text-gray-400" />
+            {PRESETS.map((preset) => (
+              <button
+                key={preset.label}
+                type="button"
+                onClick={() => update({ periods: [...quiet.periods, preset.period] })}
+                className="px-2 py-0.5 rounded text-2xs bg-gray-100 dark:bg-gray-700 text-gray-600 dark:text-gray-300 hover:bg-gray-200 dark:hover:bg-gray-600"
+              >
+                {preset.label}
+              </button>
+            ))}
+            <button
+              type="button"
+              onClick={() => update({ periods: [...quiet.periods, { days: [], from: '', to: '' }] })}
+              className="px-2 py-0.5 rounded text-2xs bg-gray-100 dark:bg-gray-700 text-gray-600 dark:text-gray-300 hover:bg-gray-200 dark:hover:bg-gray-600"
+            >
+              Plage personnalisée
+            </button>
+          </div>
+          <p className="text-2xs text-gray-400">
+            Aucun jour = tous les jours. Une fin plus tôt que le début passe minuit, une fin égale au début couvre 24 h.
+          </p>
+        </div>
+      )}
+
+      {error && <p className="text-xs text-red-500">{error}</p>}
+    </div>
+  );
+};
+
+export default QuietHoursEditor;
@@ -1,4 +1,4 @@
-import { AlertCircle, CheckCircle, Clock, Edit, RefreshCw, Server, Trash2 } from 'lucide-react';
+import { AlertCircle, CheckCircle, Clock, Edit, Folder, Pause, PauseCircle, Play, RefreshCw, Server, Tag, Trash2, Unlink, Wrench } from 'lucide-react';
 
 /**
  * Composant d'affichage d'une carte serveur style macOS.
@@ -11,8 +11,11 @@ import { AlertCircle, CheckCircle, Clock, Edit, RefreshCw, Server, Trash2 } from
  * - onDelete : fonction appelée lors du clic sur supprimer
  * - onManualCheck : fonction appelée lors du clic sur vérifier maintenant
  * - isHorizontal : booléen, mode d'affichage (liste ou grille)
+ * - onGroupClick : fonction appelée lors du clic sur le groupe (filtrage)
+ * - onTagClick : fonction appelée lors du clic sur un tag (filtrage)
+ * - onTogglePause : fonction appelée lors du clic sur pause / reprendre
  */
-const ServerCard = ({ server, onEdit, onDelete, onManualCheck, isHorizontal = false }) => {
+const ServerCard = ({ server, onEdit, onDelete, onManualCheck, isHorizontal = false, onGroupClick, onTagClick, onTogglePause }) => {
   // Retourne la couleur du texte selon le statut du serveur
   const getStatusColor = (isUp) => isUp ? 'text-green-500 dark:text-green-400' : 'text-red-500 dark:text-red-400';
 
@@ -30,6 +33,77 @@ const ServerCard = ({ server, onEdit, onDelete, onManualCheck, isHorizontal = fa
     return date.toLocaleTimeString('fr-FR', { hour: '2-digit', minute: '2-digit' });
   };
 
+  // Injoignable: un serveur parent est hors ligne (alertes suspendues)
+  const unreachable = !server.paused && !server.status?.is_up && server.status?.unreachable;
+
+  // Badge de statut (un serveur en pause n'est plus vérifié)
+  const statusBadgeClasses = server.paused
+    ? 'bg-gray-100 dark:bg-gray-700 text-gray-600 dark:text-gray-300 ring-1 ring-gray-200 dark:ring-gray-600'
+    : unreachable
+      ? 'bg-orange-100 dark:bg-orange-500/20 text-orange-700 dark:text-orange-300 ring-1 ring-orange-200 dark:ring-orange-500/30'
+      : getStatusBadgeClasses(server.status?.is_up);
+  const statusLabel = server.paused ? 'En pause' : server.status?.is_up ? 'En ligne' : unreachable ? 'Injoignable' : 'Hors ligne';
+  const statusTitle = unreachable ? `${server.status.root_cause} est hors ligne` : undefined;
+
+  // Couleur de l'indicateur latéral / de la bande de statut
+  const statusBarClasses = server.paused
+    ? 'bg-gray-300 dark:bg-gray-600'
+    : server.status?.is_up ? 'bg-green-500' : unreachable ? 'bg-orange-400' : 'bg-red-500';
+  const statusBandClasses = server.paused
+    ? 'bg-gray-300 dark:bg-gray-600'
+    : server.status?.is_up
+      ? 'bg-gradient-to-r from-green-400 to-green-500'
+      : unreachable ? 'bg-gradient-to-r from-orange-300 to-orange-400' : 'bg-gradient-to-r from-red-400 to-red-500';
+
+  // Badge de maintenance planifiée en cours (alertes suspendues)
+  const maintenanceBadge = !server.paused && server.status?.maintenance && (
+    <div
+      className="inline-flex items-center gap-1 px-2 py-1 rounded-full text-xs font-medium bg-amber-100 dark:bg-amber-500/20 text-amber-700 dark:text-amber-300"
+      title={`Maintenance « ${server.status.maintenance} » : alertes suspendues`}
+    >
+      <Wrench className="w-3 h-3" />
+      Maintenance
+    </div>
+  );
+
+  // Bouton pause / reprise du monitoring
+  const pauseButton = (
+    <button
+      onClick={() => onTogglePause?.(server)}
+      className="p-1.5 text-gray-400 hover:text-gray-700 dark:hover:text-gray-200 hover:bg-gray-100 dark:hover:bg-gray-700 rounded-lg transition-colors"
+      title={server.paused ? 'Reprendre le monitoring' : 'Mettre en pause'}
+    >
+      {server.paused ? <Play className="w-3.5 h-3.5" /> : <Pause className="w-3.5 h-3.5" />}
+    </button>
+  );
+
+  // Groupe et tags du serveur, cliquables pour filtrer la liste
+  const labels = (server.group || server.tags?.length > 0) && (
+    <div className="flex flex-wrap items-center gap-1 mt-1">
+      {server.group && (
+        <button
+          onClick={() => onGroupClick?.(server.group)}
+          className="inline-flex items-center gap-1 px-1.5 py-0.5 rounded text-2xs font-medium bg-blue-50 dark:bg-blue-500/10 text-blue-700 dark:text-blue-300 hover:bg-blue-100 dark:hover:bg-blue-500/20"
+          title="Filtrer sur ce groupe"
+        >
+          <Folder className="w-2.5 h-2.5" />
+          {server.group}
+        </button>
+      )}
+      {server.tags?.map((tag) => (
+        <button
+          key={tag}
+          onClick={() => onTagClick?.(tag)}
+          className="inline-flex items-center gap-1 px-1.5 py-0.5 rounded text-2xs font-medium bg-gray-100 dark:bg-gray-700 text-gray-600 dark:text-gray-300 hover:bg-gray-200 dark:hover:bg-gray-600"
+          title="Filtrer sur ce tag"
+        >
+          <Tag className="w-2.5 h-2.5" />
+          {tag}
+        </button>
+      ))}
+    </div>
+  );
+
   // Mode horizontal (liste)
   if (isHorizontal) {
     return (
@@ -42,7 +116,7 @@ const ServerCard = ({ server, onEdit, onDelete, onManualCheck, isHorizontal = fa
       `}>
         <div className="flex items-center p-4 gap-4">
           {/* Indicateur de statut sur le côté */}
-          <div className={`w-1 h-12 rounded-full ${server.status?.is_up ? 'bg-green-500' : 'bg-red-500'}`} />
+          <div className={`w-1 h-12 rounded-full ${statusBarClasses}`} />
 
           {/* Informations principales */}
           <div className="flex items-center gap-3 flex-1 min-w-0">
@@ -58,18 +132,24 @@ const ServerCard = ({ server, onEdit, onDelete, onManualCheck, isHorizontal = fa
             <div className="min-w-0 flex-1">
               <h3 className="font-semibold text-gray-900 dark:text-white text-sm truncate">{server.name}</h3>
               <p className="text-xs text-gray-500 dark:text-gray-400 truncate">{server.url}</p>
+              {labels}
             </div>
           </div>
 
           {/* Métriques */}
           <div className="flex items-center gap-6">
             {/* Badge de statut */}
-            <div className={`
+            <div
+              className={`
               px-2.5 py-1 rounded-full text-xs font-medium
-              ${getStatusBadgeClasses(server.status?.is_up)}
-            `}>
-              {server.status?.is_up ? 'En ligne' : 'Hors ligne'}
+              ${statusBadgeClasses}
+            `}
+              title={statusTitle}
+            >
+              {statusLabel}
+              {!server.paused && server.status?.consecutive_failures > 0 && ` (${server.status.consecutive_failures})`}
             </div>
+            {maintenanceBadge}
 
             {/* Temps de réponse */}
             <div className="text-center min-w-[60px]">
@@ -100,6 +180,7 @@ const ServerCard = ({ server, onEdit, onDelete, onManualCheck, isHorizontal = fa
 
             {/* Actions */}
             <div className="flex items-center gap-1 ml-2">
+              {pauseButton}
               <button
                 onClick={() => onEdit(server)}
                 className="p-1.5 text-gray-400 hover:text-blue-500 dark:hover:text-blue-400 hover:bg-blue-50 dark:hover:bg-blue-500/10 rounded-lg transition-colors"
@@ -149,7 +230,7 @@ const ServerCard = ({ server, onEdit, onDelete, onManualCheck, isHorizontal = fa
       overflow-hidden group
     `}>
       {/* Bande de statut en haut */}
-      <div className={`h-1 ${server.status?.is_up ? 'bg-gradient-to-r from-green-400 to-green-500' : 'bg-gradient-to-r from-red-400 to-red-500'}`} />
+      <div className={`h-1 ${statusBandClasses}`} />
 
       <div className="p-5">
         {/* En-tête */}
@@ -167,11 +248,13 @@ const ServerCard = ({ server, onEdit, onDelete, onManualCheck, isHorizontal = fa
             <div className="min-w-0 flex-1">
               <h3 className="font-semibold text-gray-900 dark:text-white text-sm truncate">{server.name}</h3>
               <p className="text-xs text-gray-500 dark:text-gray-400 truncate mt-0.5">{server.url}</p>
+              {labels}
             </div>
           </div>
 
           {/* Actions */}
           <div className="flex gap-0.5 opacity-0 group-hover:opacity-100 transition-opacity">
+            {pauseButton}
             <button
               onClick={() => onEdit(server)}
               className="p-1.5 text-gray-400 hover:text-blue-500 dark:hover:text-blue-400 hover:bg-blue-50 dark:hover:bg-blue-500/10 rounded-lg transition-colors"
@@ -197,18 +280,26 @@ const ServerCard = ({ server, onEdit, onDelete, onManualCheck, isHorizontal = fa
         </div>
 
         {/* Badge de statut */}
-        <div className="mb-4">
-          <div className={`
+        <div className="mb-4 flex items-center gap-2">
+          <div
+            className={`
             inline-flex items-center gap-1.5 px-2.5 py-1 rounded-full text-xs font-medium
-            ${getStatusBadgeClasses(server.status?.is_up)}
-          `}>
-            {server.status?.is_up ? (
+            ${statusBadgeClasses}
+          `}
+            title={statusTitle}
+          >
+            {server.paused ? (
+              <PauseCircle className="w-3 h-3" />
+            ) : server.status?.is_up ? (
               <CheckCircle className="w-3 h-3" />
+            ) : unreachable ? (
+              <Unlink className="w-3 h-3" />
             ) : (
               <AlertCircle className="w-3 h-3" />
             )}
-            {server.status?.is_up ? 'En ligne' : 'Hors ligne'}
+            {statusLabel}
           </div>
+          {maintenanceBadge}
         </div>
 
         {/* Métriques */}
@@ -232,6 +323,36 @@ const ServerCard = ({ server, onEdit, onDelete, onManualCheck, isHorizontal = fa
             </div>
           </div>
 
+          {/* Échecs consécutifs (si panne en cours) */}
+          {server.status?.consecutive_failures > 0 && (
+            <div className="flex items-center justify-between">
+              <span className="text-xs text-gray-500 dark:text-gray-400">Échecs consécutifs</span>
+              <span className="text-sm font-mono font-medium text-red-600 dark:text-red-400">
+                {server.status.consecutive_failures}
+              </span>
+            </div>
+          )}
+
+          {/* Dernier signal reçu (checks passifs) */}
+          {server.status?.last_heartbeat && (
+            <div className="flex items-center justify-between">
+              <span className="text-xs text-gray-500 dark:text-gray-400">Dernier signal</span>
+              <span className="text-sm font-mono font-medium text-gray-700 dark:text-gray-300">
+                {formatLastCheck(server.status.last_heartbeat)}
+              </span>
+            </div>
+          )}
+
+          {/* Message joint au dernier ping (checks push) */}
+          {server.status?.last_message && (
+            <div className="flex items-center justify-between gap-2">
+              <span className="text-xs text-gray-500 dark:text-gray-400">Message</span>
+              <span className="text-xs text-gray-700 dark:text-gray-300 truncate" title={server.status.last_message}>
+                {server.status.last_message}
+              </span>
+            </div>
+          )}
+
           {/* Séparateur */}
           <div className="h-px bg-gray-200 dark:bg-gray-700" />
 
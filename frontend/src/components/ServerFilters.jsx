@@ -0,0 +1,191 @@
+// Composant ServerFilters - Filtres de la liste des serveurs et actions groupées
+// Affiche l'état agrégé des groupes, filtre par groupe, tag, statut ou texte
+// et applique une action (pause, reprise, intervalle, suppression) à une sélection
+
+import { Folder, Pause, Play, Search, Timer, Trash2, X } from 'lucide-react';
+import { useEffect, useState } from 'react';
+import toast from 'react-hot-toast';
+import { BulkUpdateServers, GetGroups, GetTags } from '../../wailsjs/go/main/App';
+
+// Couleur de l'indicateur d'état agrégé d'un groupe
+const GROUP_STATUS_DOTS = {
+  up: 'bg-green-500',
+  partial: 'bg-yellow-500',
+  down: 'bg-red-500',
+  paused: 'bg-gray-400',
+  unknown: 'bg-gray-300 dark:bg-gray-600',
+};
+
+const GROUP_STATUS_LABELS = {
+  up: 'tous en ligne',
+  partial: 'partiellement en panne',
+  down: 'tous hors ligne',
+  paused: 'en pause',
+  unknown: 'pas encore vérifié',
+};
+
+const INTERVALS = [
+  ['15s', '15 secondes'],
+  ['30s', '30 secondes'],
+  ['60s', '1 minute'],
+  ['300s', '5 minutes'],
+  ['1800s', '30 minutes'],
+  ['3600s', '1 heure'],
+];
+
+const selectClass = 'px-2.5 py-1.5 text-xs bg-white dark:bg-gray-700 border border-gray-200 dark:border-gray-600 rounded-md text-gray-700 dark:text-gray-200';
+const actionClass = 'inline-flex items-center gap-1.5 px-2.5 py-1.5 text-xs font-medium rounded-md bg-gray-100 dark:bg-gray-700 hover:bg-gray-200 dark:hover:bg-gray-600 text-gray-700 dark:text-gray-200 transition-colors';
+
+/**
+ * Barre de filtres et d'actions groupées
+ * @param {Object} filter - Filtre courant { group, tags, status, search }
+ * @param {Function} onFilterChange - Fonction appelée avec le nouveau filtre
+ * @param {Array} servers - Serveurs affichés (rafraîchit l'état des groupes)
+ * @param {Function} onBulkDone - Fonction appelée après une action groupée
+ */
+const ServerFilters = ({ filter, onFilterChange, servers, onBulkDone }) => {
+  const [groups, setGroups] = useState([]);
+  const [tags, setTags] = useState([]);
+  const [bulkInterval, setBulkInterval] = useState('60s');
+
+  // Recharger groupes et tags à chaque rafraîchissement de la liste
+  useEffect(() => {
+    GetGroups()
+      .then(setGroups)
+      .catch((error) => console.error('Impossible de charger les groupes :', error));
+    GetTags()
+      .then(setTags)
+      .catch((error) => console.error('Impossible de charger les tags :', error));
+  }, [servers]);
+
+  const update = (changes) => onFilterChange({ ...filter, ...changes });
+  const tag = filter.tags?.[0] || '';
+  const hasSelection = Boolean(filter.group || tag);
+  const isFiltered = hasSelection || filter.status || filter.search;
+
+  // Libellé de la sélection visée par les actions groupées
+  const selection = [filter.group && `groupe ${filter.group}`, tag && `tag ${tag}`].filter(Boolean).join(' et ');
+
+  const runBulk = async (action) => {
+    if (action === 'delete' && !window.confirm(`Supprimer tous les serveurs du ${selection} ?`)) {
+      return;
+    }
+    try {
+      const affected = await BulkUpdateServers({
+        action,
+        filter: { group: filter.group, tags: filter.tags || [] },
+        interval: bulkInterval,
+      });
+      toast.success(`${affected.length} serveur(s) modifié(s)`);
+      if (onBulkDone) onBulkDone();
+    } catch (error) {
+      toast.error(`Action groupée impossible: ${error}`);
+    }
+  };
+
+  return (
+    <div className="mb-5 space-y-3">
+      {/* État agrégé des groupes */}
+      {groups.length > 0 && (
+        <div className="flex flex-wrap gap-1.5">
+          {groups.map((group) => (
+            <button
+              key={group.path}
+              onClick={() => update({ group: filter.group === group.path ? '' : group.path })}
+              className={`inline-flex items-center gap-1.5 px-2 py-1 rounded-md text-xs border transition-colors ${filter.group === group.path
+                ? 'bg-blue-50 dark:bg-blue-500/10 border-blue-300 dark:border-blue-500/40 text-blue-700 dark:text-blue-300'
+                : 'bg-white/70 dark:bg-gray-800/70 border-gray-200/60 dark:border-gray-700/60 text-gray-700 dark:text-gray-300 hover:bg-gray-50 dark:hover:bg-gray-700'
+              }`}
+              title={`${group.path}: ${GROUP_STATUS_LABELS[group.status]}`}
+            >
+              <span className={`w-1.5 h-1.5 rounded-full ${GROUP_STATUS_DOTS[group.status]}`} />
+              <Folder className="w-3 h-3" />
+              {group.path}
+              <span className="text-gray-400 dark:text-gray-500">
+                {group.up}/{group.total - group.paused}
+              </span>
+            </button>
+          ))}
+        </div>
+      )}
+
+      {/* Filtres */}
+      <div className="flex flex-wrap items-center gap-2">
+        <div className="relative">
+          <Search className="w-3.5 h-3.5 absolute left-2 top-1/2 -translate-y-1/2 text-gray-400" />
+          <input
+            type="text"
+            value={filter.search || ''}
+            onChange={(e) => update({ search: e.target.value })}
+            className={`${selectClass} pl-7 w-48`}
+            placeholder="Rechercher"
+          />
+        </div>
+        <select className={selectClass} value={filter.group || ''} onChange={(e) => update({ group: e.target.value })}>
+          <option value="">Tous les groupes</option>
+          {groups.map((group) => (
+            <option key={group.path} value={group.path}>
+              {'  '.repeat(group.depth)}{group.name} ({group.total})
+            </option>
+          ))}
+        </select>
+        <select className={selectClass} value={tag} onChange={(e) => update({ tags: e.target.value ? [e.target.value] : [] })}>
+          <option value="">Tous les tags</option>
+          {tags.map((t) => (
+            <option key={t.tag} value={t.tag}>{t.tag} ({t.count})</option>
+          ))}
+        </select>
+        <select className={selectClass} value={filter.status || ''} onChange={(e) => update({ status: e.target.value })}>
+          <option value="">Tous les statuts</option>
+          <option value="up">En ligne</option>
+          <option value="down">Hors ligne</option>
+          <option value="paused">En pause</option>
+        </select>
+        {isFiltered && (
+          <button
+            onClick={() => onFilterChange({ group: '', tags: [], status: '', search: '' })}
+            className="inline-flex items-center gap-1 px-2 py-1.5 text-xs text-gray-500 dark:text-gray-400 hover:text-gray-700 dark:hover:text-gray-200"
+          >
+            <X className="w-3 h-3" />
+            Réinitialiser
+          </button>
+        )}
+      </div>
+
+      {/* Actions groupées (sélection par groupe ou tag) */}
+      {hasSelection && (
+        <div className="flex flex-wrap items-center gap-2 p-2.5 rounded-lg bg-white/70 dark:bg-gray-800/70 border border-gray-200/60 dark:border-gray-700/60">
+          <span className="text-xs text-gray-500 dark:text-gray-400 mr-1">
+            Tous les serveurs du {selection} :
+          </span>
+          <button className={actionClass} onClick={() => runBulk('pause')}>
+            <Pause className="w-3 h-3" />
+            Pause
+          </button>
+          <button className={actionClass} onClick={() => runBulk('resume')}>
+            <Play className="w-3 h-3" />
+            Reprendre
+          </button>
+          <select className={selectClass} value={bulkInterval} onChange={(e) => setBulkInterval(e.target.value)}>
+            {INTERVALS.map(([value, label]) => (
+              <option key={value} value={value}>{label}</option>
+            ))}
+          </select>
+          <button className={actionClass} onClick={() => runBulk('interval')}>
+            <Timer className="w-3 h-3" />
+            Changer l'intervalle
+          </button>
+          <button
+            className={`${actionClass} text-red-600 dark:text-red-400`}
+            onClick={() => runBulk('delete')}
+          >
+            <Trash2 className="w-3 h-3" />
+            Supprimer
+          </button>
+        </div>
+      )}
+    </div>
+  );
+};
+
+export default ServerFilters;
@@ -1,7 +1,10 @@
 // Composant ServerForm - Formulaire d'ajout/modification de serveur
 // Interface modale coulissante style macOS pour gérer les serveurs
 
+import { useEffect, useState } from 'react';
 import { X } from 'lucide-react';
+import { GetGroups, GetPushURL, GetServers } from '../../wailsjs/go/main/App';
+import QuietHoursEditor, { emptyQuietHours } from './QuietHoursEditor';
 
 /**
  * Composant de formulaire pour créer ou modifier un serveur
@@ -12,6 +15,46 @@ import { X } from 'lucide-react';
  * @param {Function} onSubmit - Fonction de soumission du formulaire
  */
 const ServerForm = ({ editingServer, newServer, setNewServer, onClose, onSubmit }) => {
+  const isPush = newServer.type === 'push';
+  const isPassive = isPush || newServer.type === 'email-heartbeat';
+  const [pushURL, setPushURL] = useState(''); // URL de ping du serveur push édité
+  const [groups, setGroups] = useState([]); // Groupes existants (suggestions)
+  const [tagsText, setTagsText] = useState((newServer.tags || []).join(', ')); // Saisie libre des tags
+  const [candidates, setCandidates] = useState([]); // Serveurs pouvant servir de parent
+
+  useEffect(() => {
+    GetGroups()
+      .then((list) => setGroups(list.map((g) => g.path)))
+      .catch((error) => console.error('Impossible de charger les groupes :', error));
+    GetServers({ group: '', tags: [], status: '', search: '' })
+      .then((list) => setCandidates(list.filter((s) => s.id !== editingServer?.id).sort((a, b) => a.name.localeCompare(b.name))))
+      .catch((error) => console.error('Impossible de charger les serveurs :', error));
+  }, [editingServer]);
+
+  // Parents: serveurs dont dépend l'accès à celui-ci
+  const parents = newServer.parents || [];
+  const toggleParent = (id) => setNewServer({
+    ...newServer,
+    parents: parents.includes(id) ? parents.filter((p) => p !== id) : [...parents, id],
+  });
+
+  // Tags séparés par des virgules
+  const handleTagsChange = (text) => {
+    setTagsText(text);
+    setNewServer({ ...newServer, tags: text.split(',').map((t) => t.trim()).filter(Boolean) });
+  };
+
+  // Récupérer l'URL de ping complète d'un serveur push existant
+  useEffect(() => {
+    if (!isPush || !editingServer?.push_token) {
+      setPushURL('');
+      return;
+    }
+    GetPushURL(editingServer.id)
+      .then(setPushURL)
+      .catch((error) => setPushURL(String(error)));
+  }, [isPush, editingServer]);
+
   return (
     <>
       {/* Overlay avec effet de flou macOS */}
@@ -54,20 +97,34 @@ const ServerForm = ({ editingServer, newServer, setNewServer, onClose, onSubmit
                 />
               </div>
 
-              {/* URL */}
-              <div>
-                <label className="block text-xs font-medium text-gray-600 dark:text-gray-400 mb-1.5">
-                  URL ou adresse IP
-                </label>
-                <input
-                  type="text"
-                  required
-                  value={newServer.url}
-                  onChange={(e) => setNewServer({ ...newServer, url: e.target.value })}
-                  className="w-full px-3 py-2 bg-gray-50 dark:bg-gray-900/50 border border-gray-200 dark:border-gray-600 rounded-lg focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-transparent text-sm text-gray-900 dark:text-white placeholder-gray-400 dark:placeholder-gray-500 transition-all font-mono"
-                  placeholder="https://example.com"
-                />
-              </div>
+              {/* URL (générée par le backend pour les checks push) */}
+              {isPush ? (
+                <div>
+                  <label className="block text-xs font-medium text-gray-600 dark:text-gray-400 mb-1.5">
+                    URL de ping
+                  </label>
+                  <div className="w-full px-3 py-2 bg-gray-50 dark:bg-gray-900/50 border border-gray-200 dark:border-gray-600 rounded-lg text-xs text-gray-700 dark:text-gray-300 font-mono break-all select-all">
+                    {pushURL || "Générée à l'enregistrement"}
+                  </div>
+                  <p className="mt-1 text-2xs text-gray-400 dark:text-gray-500">
+                    Paramètres optionnels : ?status=up|down&amp;msg=...
+                  </p>
+                </div>
+              ) : (
+                <div>
+                  <label className="block text-xs font-medium text-gray-600 dark:text-gray-400 mb-1.5">
+                    {newServer.type === 'email-heartbeat' ? 'Adresse de réception attendue' : 'URL ou adresse IP'}
+                  </label>
+                  <input
+                    type="text"
+                    required
+                    value={newServer.url}
+                    onChange={(e) => setNewServer({ ...newServer, url: e.target.value })}
+                    className="w-full px-3 py-2 bg-gray-50 dark:bg-gray-900/50 border border-gray-200 dark:border-gray-600 rounded-lg focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-transparent text-sm text-gray-900 dark:text-white placeholder-gray-400 dark:placeholder-gray-500 transition-all font-mono"
+                    placeholder={newServer.type === 'email-heartbeat' ? 'backup-*@monitor.local' : 'https://example.com'}
+                  />
+                </div>
+              )}
 
               {/* Type de vérification */}
               <div>
@@ -83,6 +140,8 @@ const ServerForm = ({ editingServer, newServer, setNewServer, onClose, onSubmit
                     <option value="http">HTTP/HTTPS</option>
                     <option value="tcp">TCP</option>
                     <option value="ping">Ping</option>
+                    <option value="email-heartbeat">Email (heartbeat)</option>
+                    <option value="push">Push (ping entrant)</option>
                   </select>
                   <div className="absolute inset-y-0 right-0 flex items-center pr-2 pointer-events-none">
                     <svg className="w-4 h-4 text-gray-400" fill="none" stroke="currentColor" viewBox="0 0 24 24">
@@ -92,6 +151,103 @@ const ServerForm = ({ editingServer, newServer, setNewServer, onClose, onSubmit
                 </div>
               </div>
 
+              {/* Motif du sujet attendu (email-heartbeat) */}
+              {newServer.type === 'email-heartbeat' && (
+                <div>
+                  <label className="block text-xs font-medium text-gray-600 dark:text-gray-400 mb-1.5">
+                    Sujet attendu (expression régulière, optionnel)
+                  </label>
+                  <input
+                    type="text"
+                    value={newServer.subject_pattern || ''}
+                    onChange={(e) => setNewServer({ ...newServer, subject_pattern: e.target.value })}
+                    className="w-full px-3 py-2 bg-gray-50 dark:bg-gray-900/50 border border-gray-200 dark:border-gray-600 rounded-lg focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-transparent text-sm text-gray-900 dark:text-white placeholder-gray-400 dark:placeholder-gray-500 transition-all font-mono"
+                    placeholder="backup OK"
+                  />
+                </div>
+              )}
+
+              {/* Groupe (hiérarchique) et tags */}
+              <div className="grid grid-cols-2 gap-3">
+                <div>
+                  <label className="block text-xs font-medium text-gray-600 dark:text-gray-400 mb-1.5">
+                    Groupe
+                  </label>
+                  <input
+                    type="text"
+                    list="server-groups"
+                    value={newServer.group || ''}
+                    onChange={(e) => setNewServer({ ...newServer, group: e.target.value })}
+                    className="w-full px-3 py-2 bg-gray-50 dark:bg-gray-900/50 border border-gray-200 dark:border-gray-600 rounded-lg focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-transparent text-sm text-gray-900 dark:text-white placeholder-gray-400 dark:placeholder-gray-500 transition-all"
+                    placeholder="prod/eu"
+                    title="Sous-groupes séparés par /"
+                  />
+                  <datalist id="server-groups">
+                    {groups.map((group) => <option key={group} value={group} />)}
+                  </datalist>
+                </div>
+                <div>
+                  <label className="block text-xs font-medium text-gray-600 dark:text-gray-400 mb-1.5">
+                    Tags
+                  </label>
+                  <input
+                    type="text"
+                    value={tagsText}
+                    onChange={(e) => handleTagsChange(e.target.value)}
+                    className="w-full px-3 py-2 bg-gray-50 dark:bg-gray-900/50 border border-gray-200 dark:border-gray-600 rounded-lg focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-transparent text-sm text-gray-900 dark:text-white placeholder-gray-400 dark:placeholder-gray-500 transition-all"
+                    placeholder="web, critique"
+                    title="Tags séparés par des virgules"
+                  />
+                </div>
+              </div>
+
+              {/* Dépendances: un parent DOWN rend ce serveur injoignable */}
+              {candidates.length > 0 && (
+                <div>
+                  <label className="block text-xs font-medium text-gray-600 dark:text-gray-400 mb-1.5">
+                    Dépend de
+                  </label>
+                  <div className="max-h-28 overflow-y-auto space-y-1 px-3 py-2 bg-gray-50 dark:bg-gray-900/50 border border-gray-200 dark:border-gray-600 rounded-lg">
+                    {candidates.map((server) => (
+                      <label key={server.id} className="flex items-center gap-2 text-xs text-gray-700 dark:text-gray-300">
+                        <input type="checkbox" checked={parents.includes(server.id)} onChange={() => toggleParent(server.id)} />
+                        <span className="truncate">{server.name}</span>
+                      </label>
+                    ))}
+                  </div>
+                  <p className="mt-1 text-2xs text-gray-400 dark:text-gray-500">
+                    Si un parent est hors ligne, ce serveur est marqué injoignable et seule la panne du parent est notifiée
+                  </p>
+                </div>
+              )}
+
+              {/* Heures calmes: réglage global (ou du type de check) ou propre au serveur */}
+              <div>
+                <label className="block text-xs font-medium text-gray-600 dark:text-gray-400 mb-1.5">
+                  Heures calmes
+                </label>
+                <select
+                  value={newServer.quiet_hours ? 'custom' : 'global'}
+                  onChange={(e) => setNewServer({
+                    ...newServer,
+                    quiet_hours: e.target.value === 'custom' ? { ...emptyQuietHours, enabled: true, periods: [{ days: [], from: '22:00', to: '07:00' }] } : null,
+                  })}
+                  className="w-full px-3 py-2 bg-gray-50 dark:bg-gray-900/50 border border-gray-200 dark:border-gray-600 rounded-lg focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-transparent text-sm text-gray-900 dark:text-white transition-all"
+                >
+                  <option value="global">Réglage global (ou du type de check)</option>
+                  <option value="custom">Propres à ce serveur</option>
+                </select>
+                {newServer.quiet_hours && (
+                  <div className="mt-2 px-3 py-2 bg-gray-50 dark:bg-gray-900/50 border border-gray-200 dark:border-gray-600 rounded-lg">
+                    <QuietHoursEditor
+                      id="quiet-server"
+                      value={newServer.quiet_hours}
+                      onChange={(quiet) => setNewServer({ ...newServer, quiet_hours: quiet })}
+                    />
+                  </div>
+                )}
+              </div>
+
               {/* Intervalle et Timeout */}
               <div className="grid grid-cols-2 gap-3">
                 <div>
@@ -110,6 +266,8 @@ const ServerForm = ({ editingServer, newServer, setNewServer, onClose, onSubmit
                       <option value="300s">5 minutes</option>
                       <option value="1800s">30 minutes</option>
                       <option value="3600s">1 heure</option>
+                      <option value="21600s">6 heures</option>
+                      <option value="86400s">24 heures</option>
                     </select>
                     <div className="absolute inset-y-0 right-0 flex items-center pr-2 pointer-events-none">
                       <svg className="w-4 h-4 text-gray-400" fill="none" stroke="currentColor" viewBox="0 0 24 24">
@@ -143,6 +301,57 @@ const ServerForm = ({ editingServer, newServer, setNewServer, onClose, onSubmit
                 </div>
               </div>
 
+              {/* Délai de grâce (checks passifs) */}
+              {isPassive && (
+                <div>
+                  <label className="block text-xs font-medium text-gray-600 dark:text-gray-400 mb-1.5">
+                    Délai de grâce après l'intervalle
+                  </label>
+                  <input
+                    type="text"
+                    value={newServer.grace || ''}
+                    onChange={(e) => setNewServer({ ...newServer, grace: e.target.value })}
+                    className="w-full px-3 py-2 bg-gray-50 dark:bg-gray-900/50 border border-gray-200 dark:border-gray-600 rounded-lg focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-transparent text-sm text-gray-900 dark:text-white placeholder-gray-400 dark:placeholder-gray-500 transition-all font-mono"
+                    placeholder="5m"
+                  />
+                </div>
+              )}
+
+              {/* Seuils d'alerte critique (vide = réglage global) */}
+              <div>
+                <label className="block text-xs font-medium text-gray-600 dark:text-gray-400 mb-1.5">
+                  Alerte critique (vide = réglage global)
+                </label>
+                <div className="grid grid-cols-3 gap-2">
+                  <input
+                    type="number"
+                    min="-1"
+                    value={newServer.critical_failures || ''}
+                    onChange={(e) => setNewServer({ ...newServer, critical_failures: parseInt(e.target.value, 10) || 0 })}
+                    className="w-full px-3 py-2 bg-gray-50 dark:bg-gray-900/50 border border-gray-200 dark:border-gray-600 rounded-lg focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-transparent text-sm text-gray-900 dark:text-white placeholder-gray-400 dark:placeholder-gray-500 transition-all"
+                    placeholder="Échecs"
+                    title="Échecs consécutifs avant alerte critique (-1 = jamais)"
+                  />
+                  <input
+                    type="number"
+                    min="-1"
+                    value={newServer.critical_repeat || ''}
+                    onChange={(e) => setNewServer({ ...newServer, critical_repeat: parseInt(e.target.value, 10) || 0 })}
+                    className="w-full px-3 py-2 bg-gray-50 dark:bg-gray-900/50 border border-gray-200 dark:border-gray-600 rounded-lg focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-transparent text-sm text-gray-900 dark:text-white placeholder-gray-400 dark:placeholder-gray-500 transition-all"
+                    placeholder="Répéter"
+                    title="Répéter l'alerte tous les N échecs (-1 = jamais)"
+                  />
+                  <input
+                    type="text"
+                    value={newServer.critical_after || ''}
+                    onChange={(e) => setNewServer({ ...newServer, critical_after: e.target.value })}
+                    className="w-full px-3 py-2 bg-gray-50 dark:bg-gray-900/50 border border-gray-200 dark:border-gray-600 rounded-lg focus:outline-none focus:ring-2 focus:ring-blue-500 focus:border-transparent text-sm text-gray-900 dark:text-white placeholder-gray-400 dark:placeholder-gray-500 transition-all font-mono"
+                    placeholder="10m"
+                    title="Durée de panne avant alerte critique (off = jamais)"
+                  />
+                </div>
+              </div>
+
               {/* Section d'aide style macOS */}
               <div className="bg-blue-50/50 dark:bg-blue-500/10 backdrop-blur-sm border border-blue-200/50 dark:border-blue-500/20 rounded-lg p-3">
                 <div className="flex items-start gap-2">
@@ -157,6 +366,8 @@ const ServerForm = ({ editingServer, newServer, setNewServer, onClose, onSubmit
                       <li>• <span className="font-medium">HTTP/HTTPS</span> : Sites web et APIs</li>
                       <li>• <span className="font-medium">TCP</span> : Services réseau (ports)</li>
                       <li>• <span className="font-medium">Ping</span> : Connectivité réseau</li>
+                      <li>• <span className="font-medium">Email (heartbeat)</span> : Un email attendu doit arriver à chaque intervalle (ex: sauvegardes)</li>
+                      <li>• <span className="font-medium">Push</span> : Le job appelle son URL de ping à chaque exécution (ex: cron)</li>
                     </ul>
                   </div>
                 </div>
@@ -177,7 +388,7 @@ const ServerForm = ({ editingServer, newServer, setNewServer, onClose, onSubmit
               <button
                 type="button"
                 onClick={onSubmit}
-                disabled={!newServer.name || !newServer.url}
+                disabled={!newServer.name || (!newServer.url && !isPush)}
                 className="flex-1 px-3 py-2 text-sm font-medium bg-blue-500 hover:bg-blue-600 disabled:bg-gray-300 dark:disabled:bg-gray-600 disabled:cursor-not-allowed text-white rounded-lg transition-all shadow-sm hover:shadow-md disabled:shadow-none"
               >
                 {editingServer ? 'Enregistrer' : 'Ajouter'}
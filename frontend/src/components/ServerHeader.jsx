@@ -1,7 +1,7 @@
 // Composant ServerHeader - En-tête principal de l'application
 // Affiche les statistiques des serveurs et les contrôles principaux
 
-import { Cog, LayoutGrid, List, Plus } from 'lucide-react';
+import { ArrowDownUp, Cog, LayoutGrid, List, Plus, Wrench } from 'lucide-react';
 import { TestEmailAlert } from '../../wailsjs/go/main/App';
 
 /**
@@ -10,10 +10,12 @@ import { TestEmailAlert } from '../../wailsjs/go/main/App';
  * @param {number} totalServers - Nombre total de serveurs
  * @param {Function} onAddClick - Fonction pour ajouter un serveur
  * @param {Function} OpenSettings - Fonction pour ouvrir les paramètres
+ * @param {Function} onImportExportClick - Fonction pour ouvrir l'import/export
+ * @param {Function} onMaintenanceClick - Fonction pour ouvrir les maintenances planifiées
  * @param {string} viewMode - Mode d'affichage ('list' ou 'grid')
  * @param {Function} onViewModeChange - Fonction pour changer le mode d'affichage
  */
-const ServerHeader = ({ upServers, totalServers, onAddClick, OpenSettings, viewMode, onViewModeChange }) => {
+const ServerHeader = ({ upServers, totalServers, onAddClick, OpenSettings, onImportExportClick, onMaintenanceClick, viewMode, onViewModeChange }) => {
   /**
    * Fonction de test pour les notifications desktop
    * Envoie une notification de test via le système
@@ -175,6 +177,38 @@ const ServerHeader = ({ upServers, totalServers, onAddClick, OpenSettings, viewM
               <span>Nouveau</span>
             </button>
 
+            {/* Bouton Import / Export */}
+            <button
+              onClick={onImportExportClick}
+              className="
+                ml-1 p-1.5 rounded-md
+                text-gray-500 dark:text-gray-400
+                hover:text-gray-700 dark:hover:text-gray-200
+                hover:bg-gray-200/70 dark:hover:bg-gray-600/70
+                transition-all duration-150 ease-out
+                focus:outline-none focus:ring-2 focus:ring-gray-400 focus:ring-offset-1 focus:ring-offset-gray-100 dark:focus:ring-offset-gray-700
+              "
+              title="Import / Export"
+            >
+              <ArrowDownUp className="w-4 h-4" />
+            </button>
+
+            {/* Bouton Maintenances */}
+            <button
+              onClick={onMaintenanceClick}
+              className="
+                ml-1 p-1.5 rounded-md
+                text-gray-500 dark:text-gray-400
+                hover:text-gray-700 dark:hover:text-gray-200
+                hover:bg-gray-200/70 dark:hover:bg-gray-600/70
+                transition-all duration-150 ease-out
+                focus:outline-none focus:ring-2 focus:ring-gray-400 focus:ring-offset-1 focus:ring-offset-gray-100 dark:focus:ring-offset-gray-700
+              "
+              title="Maintenances planifiées"
+            >
+              <Wrench className="w-4 h-4" />
+            </button>
+
             {/* Bouton Paramètres */}
             <button
               onClick={OpenSettings}
@@ -1,9 +1,28 @@
 // Composant Settings - Interface de configuration de l'application
 // Permet de configurer les thèmes, notifications, emails et SMTP
 
-import { AlertCircle, CheckCircle, Mail, RefreshCw, TestTube, X } from 'lucide-react';
+import { AlertCircle, AlertTriangle, CheckCircle, MinusCircle, Mail, RefreshCw, Stethoscope, TestTube, X } from 'lucide-react';
 import { useCallback, useEffect, useState } from 'react';
-import { GetGmailSMTPConfig, GetOutlookSMTPConfig, GetSettings, GetYahooSMTPConfig, SaveSettings, SendTestEmail } from '../../wailsjs/go/main/App';
+import { ApplySMTPPreset, DiagnoseSMTP, GetDefaultEmailTemplates, GetSecretStoreStatus, GetSMTPPresets, GetSettings, PreviewEmailTemplate, SaveSettings, SendTestEmail, UnlockSecretStore, ValidateSettings } from '../../wailsjs/go/main/App';
+import QuietHoursEditor, { emptyQuietHours, normalizeQuietHours } from './QuietHoursEditor';
+
+// Types de check pouvant surcharger les heures calmes globales
+const QUIET_CHECK_TYPES = ['http', 'tcp', 'ping', 'email-heartbeat', 'push'];
+
+/**
+ * Découpe une liste d'adresses séparées par des virgules ou points-virgules
+ * @param {string} value - Liste saisie par l'utilisateur
+ * @returns {string[]} Adresses non vides
+ */
+const splitAddresses = (value) => value.split(/[,;]/).map(addr => addr.trim()).filter(Boolean);
+
+/**
+ * Message d'erreur de validation affiché sous un champ
+ * @param {string} message - Message renvoyé par le backend (rien si vide)
+ */
+const FieldErrorText = ({ message }) => (
+  message ? <p className="mt-1 text-xs text-red-600 dark:text-red-400">{message}</p> : null
+);
 
 /**
  * Composant Settings - Interface de configuration de l'application
@@ -17,6 +36,22 @@ const Settings = ({ onClose, onSettingsChanged }) => {
   const [notificationCooldown, setNotificationCooldown] = useState(10); // Délai entre notifications
   const [refreshInterval, setRefreshInterval] = useState(60);    // Intervalle de rafraîchissement
   const [userEmail, setUserEmail] = useState('');               // Email utilisateur
+  const [criticalFailures, setCriticalFailures] = useState(3);   // Échecs avant alerte critique
+  const [criticalRepeat, setCriticalRepeat] = useState(5);       // Répétition de l'alerte critique
+  const [criticalAfterMinutes, setCriticalAfterMinutes] = useState(0); // Durée de panne avant alerte critique
+  const [quietHours, setQuietHours] = useState(emptyQuietHours); // Heures calmes globales
+  const [quietHoursByType, setQuietHoursByType] = useState({});  // Heures calmes par type de check
+  const [baseSettings, setBaseSettings] = useState({});          // Settings complets reçus du backend
+  const [emailTemplates, setEmailTemplates] = useState({});      // Modèles d'email personnalisés
+  const [recipients, setRecipients] = useState({ to: '', cc: '', bcc: '' }); // Destinataires supplémentaires (séparés par des virgules)
+  const [templateKind, setTemplateKind] = useState('DOWN');      // Modèle en cours d'édition
+  const [templatePreview, setTemplatePreview] = useState(null);  // Aperçu ou erreur du modèle
+  const [smtpPresets, setSmtpPresets] = useState([]);            // Catalogue des fournisseurs SMTP
+  const [presetNotes, setPresetNotes] = useState('');            // Conseils du fournisseur choisi
+  const [secretStatus, setSecretStatus] = useState(null);        // Stockage des secrets (trousseau ou fichier chiffré)
+  const [passphrase, setPassphrase] = useState('');              // Phrase de passe maîtresse saisie
+  const [secretError, setSecretError] = useState('');            // Erreur de déverrouillage
+  const [fieldErrors, setFieldErrors] = useState([]);            // Erreurs de validation par champ (backend)
 
   // ===== États pour la configuration SMTP =====
   const [smtpConfig, setSmtpConfig] = useState({
@@ -25,7 +60,7 @@ const Settings = ({ onClose, onSettingsChanged }) => {
     username: '',    // Nom d'utilisateur
     password: '',    // Mot de passe
     from: '',        // Adresse expéditeur
-    tls: true        // Utiliser TLS
+    tls_mode: 'starttls' // Chiffrement de la connexion
   });
 
   // ===== États pour l'interface utilisateur =====
@@ -34,6 +69,8 @@ const Settings = ({ onClose, onSettingsChanged }) => {
   const [isTestingSMTP, setIsTestingSMTP] = useState(false);  // Test SMTP en cours
   const [saveStatus, setSaveStatus] = useState(null);        // Statut de sauvegarde
   const [smtpTestStatus, setSmtpTestStatus] = useState(null); // Statut du test SMTP
+  const [isDiagnosingSMTP, setIsDiagnosingSMTP] = useState(false); // Diagnostic SMTP en cours
+  const [smtpDiagReport, setSmtpDiagReport] = useState(null);  // Rapport du diagnostic SMTP
   const [hasChanges, setHasChanges] = useState(false);       // Changements non sauvegardés
   const [initialSettings, setInitialSettings] = useState({}); // Paramètres initiaux
 
@@ -54,13 +91,24 @@ const Settings = ({ onClose, onSettingsChanged }) => {
           notificationCooldown: typeof settings.notificationCooldown === 'number' && settings.notificationCooldown >= 0 ? settings.notificationCooldown : 10,
           refreshInterval: typeof settings.refreshInterval === 'number' && settings.refreshInterval >= 10 ? settings.refreshInterval : 60,
           userEmail: settings.userEmail || '',
+          criticalFailures: typeof settings.criticalFailures === 'number' && settings.criticalFailures >= 0 ? settings.criticalFailures : 3,
+          criticalRepeat: typeof settings.criticalRepeat === 'number' && settings.criticalRepeat >= 0 ? settings.criticalRepeat : 5,
+          criticalAfterMinutes: typeof settings.criticalAfterMinutes === 'number' && settings.criticalAfterMinutes >= 0 ? settings.criticalAfterMinutes : 0,
+          quietHours: normalizeQuietHours(settings.quietHours),
+          quietHoursByType: settings.quietHoursByType || {},
+          emailTemplates: settings.emailTemplates || {},
+          recipients: {
+            to: (settings.recipients?.to || []).join(', '),
+            cc: (settings.recipients?.cc || []).join(', '),
+            bcc: (settings.recipients?.bcc || []).join(', ')
+          },
           smtpConfig: settings.smtp_config || {
             host: '',
             port: 587,
             username: '',
             password: '',
             from: '',
-            tls: true
+            tls_mode: 'starttls'
           }
         };
 
@@ -69,8 +117,16 @@ const Settings = ({ onClose, onSettingsChanged }) => {
         setNotificationCooldown(validatedSettings.notificationCooldown);
         setRefreshInterval(validatedSettings.refreshInterval);
         setUserEmail(validatedSettings.userEmail);
+        setCriticalFailures(validatedSettings.criticalFailures);
+        setCriticalRepeat(validatedSettings.criticalRepeat);
+        setCriticalAfterMinutes(validatedSettings.criticalAfterMinutes);
+        setQuietHours(validatedSettings.quietHours);
+        setQuietHoursByType(validatedSettings.quietHoursByType);
+        setEmailTemplates(validatedSettings.emailTemplates);
+        setRecipients(validatedSettings.recipients);
         setSmtpConfig(validatedSettings.smtpConfig);
         setInitialSettings(validatedSettings);
+        setBaseSettings(settings);
 
       } catch (error) {
         console.error('Erreur lors du chargement des paramètres:', error);
@@ -81,6 +137,16 @@ const Settings = ({ onClose, onSettingsChanged }) => {
     };
 
     loadSettings();
+
+    // Catalogue des fournisseurs pour la configuration rapide
+    GetSMTPPresets()
+      .then(presets => setSmtpPresets(presets || []))
+      .catch(error => console.error('Erreur lors du chargement des fournisseurs SMTP:', error));
+
+    // Stockage des mots de passe et jetons SMTP
+    GetSecretStoreStatus()
+      .then(setSecretStatus)
+      .catch(error => console.error('Erreur lors du chargement du stockage des secrets:', error));
   }, []);
 
   // ===== Détection des changements =====
@@ -95,14 +161,21 @@ const Settings = ({ onClose, onSettingsChanged }) => {
       notificationCooldown,
       refreshInterval,
       userEmail,
+      criticalFailures,
+      criticalRepeat,
+      criticalAfterMinutes,
+      quietHours,
+      quietHoursByType,
+      emailTemplates,
+      recipients,
       smtpConfig,
     };
 
     // Vérifier s'il y a des changements (comparaison spéciale pour smtpConfig)
     const changed = Object.keys(initialSettings).some(
       key => {
-        if (key === 'smtpConfig') {
-          // Comparaison profonde pour l'objet SMTP
+        if (['smtpConfig', 'emailTemplates', 'recipients', 'quietHours', 'quietHoursByType'].includes(key)) {
+          // Comparaison profonde pour les objets (SMTP, modèles, heures calmes...)
           return JSON.stringify(initialSettings[key]) !== JSON.stringify(currentSettings[key]);
         }
         // Comparaison simple pour les autres propriétés
@@ -111,7 +184,7 @@ const Settings = ({ onClose, onSettingsChanged }) => {
     );
 
     setHasChanges(changed);
-  }, [theme, notificationMode, notificationCooldown, refreshInterval, userEmail, smtpConfig, initialSettings]);
+  }, [theme, notificationMode, notificationCooldown, refreshInterval, userEmail, criticalFailures, criticalRepeat, criticalAfterMinutes, quietHours, quietHoursByType, emailTemplates, recipients, smtpConfig, initialSettings]);
 
   // ===== Gestionnaires d'événements =====
   
@@ -124,40 +197,37 @@ const Settings = ({ onClose, onSettingsChanged }) => {
     setNotificationCooldown(10);
     setRefreshInterval(60);
     setUserEmail('');
+    setCriticalFailures(3);
+    setCriticalRepeat(5);
+    setCriticalAfterMinutes(0);
+    setQuietHours(emptyQuietHours);
+    setQuietHoursByType({});
+    setEmailTemplates({});
+    setRecipients({ to: '', cc: '', bcc: '' });
     setSmtpConfig({
       host: '',
       port: 587,
       username: '',
       password: '',
       from: '',
-      tls: true
+      tls_mode: 'starttls'
     });
     // Réinitialiser les statuts
     setSaveStatus(null);
     setSmtpTestStatus(null);
+    setSmtpDiagReport(null);
   }, []);
 
   /**
    * Sélectionne et configure un fournisseur SMTP prédéfini
    * @param {string} provider - gmail, outlook, ou yahoo
    */
-  const handleProviderSelect = async (provider) => {
-    let config;
+  const handleProviderSelect = async (preset) => {
     try {
-      switch (provider) {
-        case 'gmail':
-          config = await GetGmailSMTPConfig();
-          break;
-        case 'outlook':
-          config = await GetOutlookSMTPConfig();
-          break;
-        case 'yahoo':
-          config = await GetYahooSMTPConfig();
-          break;
-        default:
-          return;
-      }
+      // Le backend applique hôte, port, TLS et authentification du fournisseur
+      const config = await ApplySMTPPreset(preset.id, smtpConfig);
       setSmtpConfig(prev => ({ ...prev, ...config }));
+      setPresetNotes(preset.notes || '');
       setSmtpTestStatus(null);
     } catch (error) {
       console.error('Erreur lors du chargement de la configuration:', error);
@@ -170,7 +240,7 @@ const Settings = ({ onClose, onSettingsChanged }) => {
    */
   const handleTestSMTP = async () => {
     // Vérifier que les champs requis sont remplis
-    if (!smtpConfig.host || !smtpConfig.username || !smtpConfig.password) {
+    if (!smtpCredentialsReady) {
       setSmtpTestStatus('error');
       return;
     }
@@ -190,6 +260,46 @@ const Settings = ({ onClose, onSettingsChanged }) => {
     }
   };
 
+  /**
+   * Déverrouille (ou crée) le fichier de secrets chiffré puis recharge
+   * la configuration SMTP dont les secrets sont désormais lisibles
+   */
+  const handleUnlockSecrets = async () => {
+    setSecretError('');
+    try {
+      await UnlockSecretStore(passphrase);
+      setPassphrase('');
+      const settings = await GetSettings();
+      if (settings.smtp_config) {
+        setSmtpConfig(settings.smtp_config);
+        setInitialSettings(prev => ({ ...prev, smtpConfig: settings.smtp_config }));
+      }
+      setSecretStatus(await GetSecretStoreStatus());
+    } catch (error) {
+      console.error('Erreur déverrouillage des secrets:', error);
+      setSecretError(String(error));
+    }
+  };
+
+  /**
+   * Diagnostique pas à pas la configuration SMTP du formulaire
+   * (DNS, TCP, TLS, EHLO, authentification, envoi de test)
+   */
+  const handleDiagnoseSMTP = async () => {
+    setIsDiagnosingSMTP(true);
+    setSmtpDiagReport(null);
+
+    try {
+      const report = await DiagnoseSMTP(smtpConfig, userEmail);
+      setSmtpDiagReport(report);
+    } catch (error) {
+      console.error('Erreur diagnostic SMTP:', error);
+      setSmtpDiagReport({ success: false, steps: [{ id: 'app', label: 'Diagnostic', status: 'error', detail: String(error) }] });
+    } finally {
+      setIsDiagnosingSMTP(false);
+    }
+  };
+
   /**
    * Annule les changements et remet les paramètres initiaux
    */
@@ -200,17 +310,26 @@ const Settings = ({ onClose, onSettingsChanged }) => {
     setNotificationCooldown(initialSettings.notificationCooldown || 10);
     setRefreshInterval(initialSettings.refreshInterval || 60);
     setUserEmail(initialSettings.userEmail || '');
+    setCriticalFailures(initialSettings.criticalFailures ?? 3);
+    setCriticalRepeat(initialSettings.criticalRepeat ?? 5);
+    setCriticalAfterMinutes(initialSettings.criticalAfterMinutes ?? 0);
+    setQuietHours(initialSettings.quietHours || emptyQuietHours);
+    setQuietHoursByType(initialSettings.quietHoursByType || {});
+    setEmailTemplates(initialSettings.emailTemplates || {});
+    setRecipients(initialSettings.recipients || { to: '', cc: '', bcc: '' });
     setSmtpConfig(initialSettings.smtpConfig || {
       host: '',
       port: 587,
       username: '',
       password: '',
       from: '',
-      tls: true
+      tls_mode: 'starttls'
     });
     // Réinitialiser les statuts
     setSaveStatus(null);
     setSmtpTestStatus(null);
+    setSmtpDiagReport(null);
+    setFieldErrors([]);
   }, [initialSettings]);
 
   /**
@@ -223,19 +342,41 @@ const Settings = ({ onClose, onSettingsChanged }) => {
 
     try {
       // Préparer les paramètres à sauvegarder
+      // (les champs non gérés par ce formulaire sont conservés tels quels)
       const settingsToSave = {
+        ...baseSettings,
         theme,
         notificationMode,
         notificationCooldown,
         refreshInterval,
         userEmail,
+        criticalFailures,
+        criticalRepeat,
+        criticalAfterMinutes,
+        quietHours,
+        quietHoursByType,
+        emailTemplates,
+        recipients: {
+          to: splitAddresses(recipients.to),
+          cc: splitAddresses(recipients.cc),
+          bcc: splitAddresses(recipients.bcc)
+        },
         smtp_config: smtpConfig
       };
 
+      // Faire valider par le backend et signaler les champs invalides
+      const errors = await ValidateSettings(settingsToSave);
+      setFieldErrors(errors || []);
+      if (errors && errors.length > 0) {
+        setSaveStatus('invalid');
+        return;
+      }
+
       // Envoyer au backend
       await SaveSettings(settingsToSave);
       // Mettre à jour les paramètres initiaux
-      setInitialSettings({ ...settingsToSave, smtpConfig });
+      setInitialSettings({ ...settingsToSave, smtpConfig, recipients });
+      setBaseSettings(settingsToSave);
       setSaveStatus('success');
 
       // Notifier le parent des changements
@@ -254,7 +395,7 @@ const Settings = ({ onClose, onSettingsChanged }) => {
     } finally {
       setIsSaving(false);
     }
-  }, [theme, notificationMode, notificationCooldown, refreshInterval, userEmail, smtpConfig, onClose, onSettingsChanged]);
+  }, [baseSettings, theme, notificationMode, notificationCooldown, refreshInterval, userEmail, criticalFailures, criticalRepeat, criticalAfterMinutes, quietHours, quietHoursByType, emailTemplates, recipients, smtpConfig, onClose, onSettingsChanged]);
 
   const handleIntervalChange = useCallback((e) => {
     const val = parseInt(e.target.value, 10);
@@ -270,11 +411,91 @@ const Settings = ({ onClose, onSettingsChanged }) => {
     }
   }, []);
 
+  // Fabrique un gestionnaire pour les seuils numériques positifs
+  const handleThresholdChange = (setter, max) => (e) => {
+    const val = parseInt(e.target.value, 10);
+    if (!isNaN(val) && val >= 0 && val <= max) {
+      setter(val);
+    }
+  };
+
+  // Ajoute, modifie (quiet) ou retire (quiet nul) la surcharge des heures calmes d'un type de check
+  const updateQuietHoursByType = (type, quiet) => {
+    setQuietHoursByType(prev => {
+      const next = { ...prev };
+      if (quiet) {
+        next[type] = quiet;
+      } else {
+        delete next[type];
+      }
+      return next;
+    });
+  };
+
+  // Modifie un champ du modèle d'email en cours d'édition
+  const updateEmailTemplate = (field, value) => {
+    setEmailTemplates(prev => ({
+      ...prev,
+      [templateKind]: { subject: '', text: '', html: '', ...prev[templateKind], [field]: value }
+    }));
+    setTemplatePreview(null);
+  };
+
+  /**
+   * Charge le modèle par défaut dans l'éditeur pour servir de base
+   */
+  const handleLoadDefaultTemplate = async () => {
+    try {
+      const defaults = await GetDefaultEmailTemplates();
+      setEmailTemplates(prev => ({ ...prev, [templateKind]: defaults[templateKind] }));
+      setTemplatePreview(null);
+    } catch (error) {
+      console.error('Erreur lors du chargement du modèle par défaut:', error);
+    }
+  };
+
+  /**
+   * Génère un aperçu du modèle avec des données d'exemple
+   */
+  const handlePreviewTemplate = async () => {
+    try {
+      const rendered = await PreviewEmailTemplate(templateKind, emailTemplates[templateKind] || {});
+      setTemplatePreview({ ok: true, text: `${rendered.subject}\n\n${rendered.text}` });
+    } catch (error) {
+      setTemplatePreview({ ok: false, text: String(error) });
+    }
+  };
+
+  // Premier message d'erreur d'un champ (ou de ses sous-champs, ex: "recipients.cc")
+  const fieldError = (field) => fieldErrors.find(error =>
+    error.field === field || error.field.startsWith(field + '.') || error.field.startsWith(field + '[')
+  )?.message;
+
   const updateSmtpConfig = (field, value) => {
     setSmtpConfig(prev => ({ ...prev, [field]: value }));
     setSmtpTestStatus(null);
+    setSmtpDiagReport(null);
+  };
+
+  /**
+   * Met à jour un champ du renouvellement de jeton OAuth2 (xoauth2)
+   */
+  const updateOAuth2 = (field, value) => {
+    setSmtpConfig(prev => ({ ...prev, oauth2: { ...(prev.oauth2 || {}), [field]: value } }));
+    setSmtpTestStatus(null);
+    setSmtpDiagReport(null);
   };
 
+  // Identifiants suffisants pour tester la configuration selon l'authentification
+  const smtpAuth = smtpConfig.auth || 'plain';
+  const smtpCredentialsReady = Boolean(smtpConfig.host) && (
+    smtpAuth === 'none' ||
+    (Boolean(smtpConfig.username) && (
+      Boolean(smtpConfig.password) ||
+      (smtpAuth === 'xoauth2' && Boolean(smtpConfig.oauth2?.client_id) && Boolean(smtpConfig.oauth2?.refresh_token))
+    ))
+  );
+
   const handleClose = useCallback(() => {
     if (hasChanges) {
       const confirmClose = window.confirm('Vous avez des modifications non sauvegardées. Êtes-vous sûr de vouloir fermer ?');
@@ -331,7 +552,9 @@ const Settings = ({ onClose, onSettingsChanged }) => {
               `}>
                 {saveStatus === 'success' ? <CheckCircle size={14} /> : <AlertCircle size={14} />}
                 <span className="font-medium">
-                  {saveStatus === 'success' ? 'Paramètres sauvegardés' : 'Erreur lors de la sauvegarde'}
+                  {saveStatus === 'success' ? 'Paramètres sauvegardés'
+                    : saveStatus === 'invalid' ? 'Paramètres invalides: corrigez les champs signalés'
+                    : 'Erreur lors de la sauvegarde'}
                 </span>
               </div>
             )}
@@ -410,6 +633,76 @@ const Settings = ({ onClose, onSettingsChanged }) => {
                       />
                       <span className="text-xs text-gray-500 dark:text-gray-400">minutes</span>
                     </div>
+                    <FieldErrorText message={fieldError('notificationCooldown')} />
+                  </div>
+                )}
+
+                {notificationMode !== 'none' && (
+                  <div>
+                    <label className="block text-xs font-medium text-gray-600 dark:text-gray-400 mb-1.5">
+                      Alerte critique
+                    </label>
+                    <div className="grid grid-cols-3 gap-2">
+                      {[
+                        { value: criticalFailures, setter: setCriticalFailures, max: 1000, unit: 'échecs' },
+                        { value: criticalRepeat, setter: setCriticalRepeat, max: 1000, unit: 'répétition' },
+                        { value: criticalAfterMinutes, setter: setCriticalAfterMinutes, max: 1440, unit: 'min de panne' }
+                      ].map((field) => (
+                        <div key={field.unit} className="flex items-center space-x-2">
+                          <input
+                            type="number"
+                            value={field.value}
+                            onChange={handleThresholdChange(field.setter, field.max)}
+                            className="w-full px-3 py-2 bg-white dark:bg-gray-800 border border-gray-200 dark:border-gray-600 rounded-lg focus:outline-none focus:ring-2 focus:ring-blue-500 text-sm"
+                            min="0"
+                            max={field.max}
+                          />
+                          <span className="text-xs text-gray-500 dark:text-gray-400 whitespace-nowrap">{field.unit}</span>
+                        </div>
+                      ))}
+                    </div>
+                    <p className="mt-1 text-xs text-gray-400 dark:text-gray-500">0 désactive le critère correspondant</p>
+                    <FieldErrorText message={fieldError('criticalFailures') || fieldError('criticalRepeat') || fieldError('criticalAfterMinutes')} />
+                  </div>
+                )}
+
+                {notificationMode !== 'none' && (
+                  <div className="space-y-2">
+                    <QuietHoursEditor id="quiet-global" value={quietHours} onChange={setQuietHours} error={fieldError('quietHours')} />
+
+                    {/* Surcharges par type de check (ex: pas d'heures calmes pour les checks push) */}
+                    {Object.keys(quietHoursByType).sort().map(type => (
+                      <div key={type} className="p-2 rounded-md border border-gray-200 dark:border-gray-700">
+                        <div className="flex items-center justify-between mb-1">
+                          <span className="text-xs font-medium text-gray-600 dark:text-gray-400">Checks {type}</span>
+                          <button
+                            onClick={() => updateQuietHoursByType(type, null)}
+                            className="p-0.5 text-gray-400 hover:text-red-500"
+                            title="Revenir au réglage global"
+                          >
+                            <X size={12} />
+                          </button>
+                        </div>
+                        <QuietHoursEditor
+                          id={`quiet-${type}`}
+                          value={quietHoursByType[type]}
+                          onChange={(quiet) => updateQuietHoursByType(type, quiet)}
+                          error={fieldError(`quietHoursByType.${type}`)}
+                        />
+                      </div>
+                    ))}
+                    {QUIET_CHECK_TYPES.some(type => !quietHoursByType[type]) && (
+                      <select
+                        value=""
+                        onChange={(e) => e.target.value && updateQuietHoursByType(e.target.value, normalizeQuietHours(quietHours))}
+                        className="w-full px-2 py-1 bg-white dark:bg-gray-800 border border-gray-200 dark:border-gray-600 rounded-md text-xs text-gray-600 dark:text-gray-300"
+                      >
+                        <option value="">Heures calmes différentes pour un type de check…</option>
+                        {QUIET_CHECK_TYPES.filter(type => !quietHoursByType[type]).map(type => (
+                          <option key={type} value={type}>{type}</option>
+                        ))}
+                      </select>
+                    )}
                   </div>
                 )}
               </div>
@@ -434,26 +727,85 @@ const Settings = ({ onClose, onSettingsChanged }) => {
                       placeholder="votre@email.com"
                       className="w-full px-3 py-2 bg-white dark:bg-gray-800 border border-gray-200 dark:border-gray-600 rounded-lg focus:outline-none focus:ring-2 focus:ring-blue-500 text-sm"
                     />
+                    <FieldErrorText message={fieldError('userEmail')} />
                   </div>
 
-                  {/* Boutons providers */}
+                  {/* Destinataires supplémentaires */}
+                  <div className="grid grid-cols-3 gap-2">
+                    {[
+                      { field: 'to', label: 'Autres destinataires' },
+                      { field: 'cc', label: 'Copie (CC)' },
+                      { field: 'bcc', label: 'Copie cachée (BCC)' }
+                    ].map(({ field, label }) => (
+                      <div key={field}>
+                        <label className="block text-xs font-medium text-gray-600 dark:text-gray-400 mb-1">{label}</label>
+                        <input
+                          type="text"
+                          value={recipients[field]}
+                          onChange={(e) => setRecipients(prev => ({ ...prev, [field]: e.target.value }))}
+                          placeholder="a@x.com, b@x.com"
+                          className="w-full px-2 py-1.5 bg-white dark:bg-gray-800 border border-gray-200 dark:border-gray-600 rounded-md focus:outline-none focus:ring-2 focus:ring-blue-500 text-sm"
+                        />
+                        <FieldErrorText message={fieldError(`recipients.${field}`)} />
+                      </div>
+                    ))}
+                  </div>
+
+                  {/* Stockage des secrets (trousseau du système ou fichier chiffré) */}
+                  {secretStatus && (secretStatus.backend === 'keyring' ? (
+                    <p className="text-xs text-gray-500 dark:text-gray-400">
+                      🔐 Mots de passe et jetons rangés dans le trousseau du système
+                    </p>
+                  ) : secretStatus.locked ? (
+                    <div className="p-2 bg-orange-50 dark:bg-orange-500/10 border border-orange-200 dark:border-orange-500/30 rounded-md space-y-1.5">
+                      <p className="text-xs text-orange-700 dark:text-orange-300">
+                        {secretStatus.initialized
+                          ? '🔒 Secrets SMTP chiffrés: saisissez la phrase de passe maîtresse pour les déverrouiller'
+                          : '🔐 Trousseau du système indisponible: choisissez une phrase de passe maîtresse pour chiffrer les secrets SMTP'}
+                      </p>
+                      <div className="flex space-x-2">
+                        <input
+                          type="password"
+                          value={passphrase}
+                          onChange={(e) => setPassphrase(e.target.value)}
+                          onKeyDown={(e) => e.key === 'Enter' && passphrase && handleUnlockSecrets()}
+                          placeholder="Phrase de passe maîtresse"
+                          className="flex-1 px-2 py-1.5 bg-white dark:bg-gray-800 border border-gray-200 dark:border-gray-600 rounded-md focus:outline-none focus:ring-2 focus:ring-blue-500 text-sm"
+                        />
+                        <button
+                          onClick={handleUnlockSecrets}
+                          disabled={!passphrase}
+                          className="px-3 py-1.5 text-xs text-white rounded-md transition-colors bg-blue-500 hover:bg-blue-600 disabled:bg-gray-300 dark:disabled:bg-gray-600"
+                        >
+                          {secretStatus.initialized ? 'Déverrouiller' : 'Créer'}
+                        </button>
+                      </div>
+                      {secretError && <p className="text-xs text-red-600 dark:text-red-400">{secretError}</p>}
+                    </div>
+                  ) : (
+                    <p className="text-xs text-gray-500 dark:text-gray-400">
+                      🔐 Mots de passe et jetons chiffrés dans secrets.enc
+                    </p>
+                  ))}
+
+                  {/* Boutons providers (catalogue chargé depuis le backend) */}
                   <div>
                     <label className="block text-xs font-medium text-gray-600 dark:text-gray-400 mb-1.5">Configuration rapide</label>
-                    <div className="flex space-x-2">
-                      {[
-                        { name: 'gmail', label: 'Gmail', color: 'bg-red-500 hover:bg-red-600' },
-                        { name: 'outlook', label: 'Outlook', color: 'bg-blue-500 hover:bg-blue-600' },
-                        { name: 'yahoo', label: 'Yahoo', color: 'bg-purple-500 hover:bg-purple-600' }
-                      ].map(provider => (
+                    <div className="flex flex-wrap gap-2">
+                      {smtpPresets.map(preset => (
                         <button
-                          key={provider.name}
-                          onClick={() => handleProviderSelect(provider.name)}
-                          className={`flex-1 px-3 py-1.5 text-xs text-white rounded-md transition-colors ${provider.color}`}
+                          key={preset.id}
+                          onClick={() => handleProviderSelect(preset)}
+                          title={preset.notes || preset.host}
+                          className="px-3 py-1.5 text-xs text-white rounded-md transition-colors bg-blue-500 hover:bg-blue-600"
                         >
-                          {provider.label}
+                          {preset.name}
                         </button>
                       ))}
                     </div>
+                    {presetNotes && (
+                      <p className="mt-1.5 text-xs text-gray-500 dark:text-gray-400">{presetNotes}</p>
+                    )}
                   </div>
 
                   {/* Configuration SMTP */}
@@ -467,6 +819,7 @@ const Settings = ({ onClose, onSettingsChanged }) => {
                         placeholder="smtp.gmail.com"
                         className="w-full px-2 py-1.5 bg-white dark:bg-gray-800 border border-gray-200 dark:border-gray-600 rounded-md focus:outline-none focus:ring-2 focus:ring-blue-500 text-sm"
                       />
+                      <FieldErrorText message={fieldError('smtp_config.host')} />
                     </div>
 
                     <div>
@@ -477,6 +830,7 @@ const Settings = ({ onClose, onSettingsChanged }) => {
                         onChange={(e) => updateSmtpConfig('port', parseInt(e.target.value) || 587)}
                         className="w-full px-2 py-1.5 bg-white dark:bg-gray-800 border border-gray-200 dark:border-gray-600 rounded-md focus:outline-none focus:ring-2 focus:ring-blue-500 text-sm"
                       />
+                      <FieldErrorText message={fieldError('smtp_config.port')} />
                     </div>
 
                     <div>
@@ -502,25 +856,174 @@ const Settings = ({ onClose, onSettingsChanged }) => {
                     </div>
                   </div>
 
-                  {/* Checkbox TLS */}
-                  <div className="flex items-center space-x-2">
-                    <input
-                      type="checkbox"
-                      id="tls"
-                      checked={smtpConfig.tls}
-                      onChange={(e) => updateSmtpConfig('tls', e.target.checked)}
-                      className="rounded border-gray-300 text-blue-500 focus:ring-blue-500"
-                    />
-                    <label htmlFor="tls" className="text-xs font-medium text-gray-700 dark:text-gray-300">
-                      Utiliser TLS
+                  {/* Chiffrement TLS */}
+                  <div className="grid grid-cols-2 gap-3">
+                    <div>
+                      <label className="block text-xs font-medium text-gray-600 dark:text-gray-400 mb-1">Chiffrement</label>
+                      <select
+                        value={smtpConfig.tls_mode || 'starttls'}
+                        onChange={(e) => updateSmtpConfig('tls_mode', e.target.value)}
+                        className="w-full px-2 py-1.5 bg-white dark:bg-gray-800 border border-gray-200 dark:border-gray-600 rounded-md focus:outline-none focus:ring-2 focus:ring-blue-500 text-sm"
+                      >
+                        <option value="none">Aucun</option>
+                        <option value="opportunistic">STARTTLS opportuniste</option>
+                        <option value="starttls">STARTTLS obligatoire</option>
+                        <option value="implicit">TLS implicite (SMTPS, 465)</option>
+                      </select>
+                      <FieldErrorText message={fieldError('smtp_config.tls_mode')} />
+                    </div>
+
+                    {smtpConfig.tls_mode !== 'none' && (
+                      <div className="flex items-end pb-2 space-x-2">
+                        <input
+                          type="checkbox"
+                          id="skip_verify"
+                          checked={Boolean(smtpConfig.skip_verify)}
+                          onChange={(e) => updateSmtpConfig('skip_verify', e.target.checked)}
+                          className="rounded border-gray-300 text-blue-500 focus:ring-blue-500"
+                        />
+                        <label htmlFor="skip_verify" className="text-xs font-medium text-gray-700 dark:text-gray-300">
+                          Ne pas vérifier le certificat
+                        </label>
+                      </div>
+                    )}
+                  </div>
+
+                  {/* Autorité de certification personnalisée (serveur interne) */}
+                  {smtpConfig.tls_mode !== 'none' && (
+                    <div>
+                      <label className="block text-xs font-medium text-gray-600 dark:text-gray-400 mb-1">
+                        Certificat CA (PEM ou chemin du fichier, optionnel)
+                      </label>
+                      <textarea
+                        value={smtpConfig.ca_cert || ''}
+                        onChange={(e) => updateSmtpConfig('ca_cert', e.target.value)}
+                        placeholder="-----BEGIN CERTIFICATE----- ou /etc/ssl/certs/ca-interne.pem"
+                        rows={2}
+                        className="w-full px-2 py-1.5 bg-white dark:bg-gray-800 border border-gray-200 dark:border-gray-600 rounded-md focus:outline-none focus:ring-2 focus:ring-blue-500 text-xs font-mono"
+                      />
+                      <FieldErrorText message={fieldError('smtp_config.ca_cert')} />
+                      {smtpConfig.skip_verify && (
+                        <p className="mt-1 text-xs text-orange-600 dark:text-orange-400">
+                          ⚠️ Certificat non vérifié: la connexion est exposée à l'interception
+                        </p>
+                      )}
+                    </div>
+                  )}
+
+                  {/* Authentification */}
+                  <div>
+                    <label className="block text-xs font-medium text-gray-600 dark:text-gray-400 mb-1">Authentification</label>
+                    <select
+                      value={smtpAuth}
+                      onChange={(e) => updateSmtpConfig('auth', e.target.value)}
+                      className="w-full px-2 py-1.5 bg-white dark:bg-gray-800 border border-gray-200 dark:border-gray-600 rounded-md focus:outline-none focus:ring-2 focus:ring-blue-500 text-sm"
+                    >
+                      <option value="plain">PLAIN</option>
+                      <option value="login">LOGIN</option>
+                      <option value="cram-md5">CRAM-MD5</option>
+                      <option value="xoauth2">OAuth2 (XOAUTH2)</option>
+                      <option value="none">Aucune</option>
+                    </select>
+                    <FieldErrorText message={fieldError('smtp_config.auth') || fieldError('smtp_config.oauth2')} />
+                  </div>
+
+                  {/* Renouvellement du jeton OAuth2 (sinon le mot de passe est le jeton d'accès) */}
+                  {smtpAuth === 'xoauth2' && (
+                    <div className="grid grid-cols-2 gap-3">
+                      {[
+                        { field: 'token_url', label: 'URL des jetons', type: 'text', placeholder: 'https://login.microsoftonline.com/common/oauth2/v2.0/token' },
+                        { field: 'scopes', label: 'Portées', type: 'text', placeholder: 'https://outlook.office.com/SMTP.Send offline_access' },
+                        { field: 'client_id', label: 'Client ID', type: 'text', placeholder: '00000000-0000-0000-0000-000000000000' },
+                        { field: 'client_secret', label: 'Client secret', type: 'password', placeholder: '••••••••' },
+                        { field: 'refresh_token', label: 'Refresh token', type: 'password', placeholder: '••••••••' }
+                      ].map(({ field, label, type, placeholder }) => (
+                        <div key={field}>
+                          <label className="block text-xs font-medium text-gray-600 dark:text-gray-400 mb-1">{label}</label>
+                          <input
+                            type={type}
+                            value={smtpConfig.oauth2?.[field] || ''}
+                            onChange={(e) => updateOAuth2(field, e.target.value)}
+                            placeholder={placeholder}
+                            className="w-full px-2 py-1.5 bg-white dark:bg-gray-800 border border-gray-200 dark:border-gray-600 rounded-md focus:outline-none focus:ring-2 focus:ring-blue-500 text-sm"
+                          />
+                        </div>
+                      ))}
+                    </div>
+                  )}
+
+                  {/* Modèles d'email */}
+                  <div>
+                    <label className="block text-xs font-medium text-gray-600 dark:text-gray-400 mb-1.5">
+                      Modèles d'email (vide = modèle par défaut)
                     </label>
+                    <div className="flex space-x-2 mb-2">
+                      {['DOWN', 'UP', 'CRITICAL', 'SUMMARY', 'DIGEST'].map(kind => (
+                        <button
+                          key={kind}
+                          onClick={() => { setTemplateKind(kind); setTemplatePreview(null); }}
+                          className={`flex-1 px-2 py-1 text-xs rounded-md transition-colors ${templateKind === kind
+                            ? 'bg-blue-500 text-white'
+                            : 'bg-white dark:bg-gray-800 border border-gray-200 dark:border-gray-600 text-gray-700 dark:text-gray-300'
+                          }`}
+                        >
+                          {kind}
+                        </button>
+                      ))}
+                    </div>
+                    <div className="space-y-2">
+                      <input
+                        type="text"
+                        value={emailTemplates[templateKind]?.subject || ''}
+                        onChange={(e) => updateEmailTemplate('subject', e.target.value)}
+                        placeholder="Sujet, ex: ALERTE: {{.Server.Name}} est DOWN"
+                        className="w-full px-2 py-1.5 bg-white dark:bg-gray-800 border border-gray-200 dark:border-gray-600 rounded-md focus:outline-none focus:ring-2 focus:ring-blue-500 text-xs font-mono"
+                      />
+                      <textarea
+                        rows={4}
+                        value={emailTemplates[templateKind]?.text || ''}
+                        onChange={(e) => updateEmailTemplate('text', e.target.value)}
+                        placeholder="Texte brut"
+                        className="w-full px-2 py-1.5 bg-white dark:bg-gray-800 border border-gray-200 dark:border-gray-600 rounded-md focus:outline-none focus:ring-2 focus:ring-blue-500 text-xs font-mono"
+                      />
+                      <textarea
+                        rows={4}
+                        value={emailTemplates[templateKind]?.html || ''}
+                        onChange={(e) => updateEmailTemplate('html', e.target.value)}
+                        placeholder="HTML"
+                        className="w-full px-2 py-1.5 bg-white dark:bg-gray-800 border border-gray-200 dark:border-gray-600 rounded-md focus:outline-none focus:ring-2 focus:ring-blue-500 text-xs font-mono"
+                      />
+                      <FieldErrorText message={fieldError(`emailTemplates.${templateKind}`)} />
+                      <div className="flex space-x-2">
+                        <button
+                          onClick={handleLoadDefaultTemplate}
+                          className="px-3 py-1.5 text-xs font-medium text-gray-700 dark:text-gray-300 bg-white dark:bg-gray-800 border border-gray-200 dark:border-gray-600 rounded-md"
+                        >
+                          Modèle par défaut
+                        </button>
+                        <button
+                          onClick={handlePreviewTemplate}
+                          className="px-3 py-1.5 text-xs font-medium text-gray-700 dark:text-gray-300 bg-white dark:bg-gray-800 border border-gray-200 dark:border-gray-600 rounded-md"
+                        >
+                          Aperçu
+                        </button>
+                      </div>
+                      {templatePreview && (
+                        <pre className={`p-2 rounded-md text-xs whitespace-pre-wrap ${templatePreview.ok
+                          ? 'bg-white dark:bg-gray-800 text-gray-700 dark:text-gray-300'
+                          : 'bg-red-50 dark:bg-red-500/10 text-red-600 dark:text-red-400'
+                        }`}>
+                          {templatePreview.text}
+                        </pre>
+                      )}
+                    </div>
                   </div>
 
                   {/* Test SMTP */}
                   <div className="flex items-center space-x-3 pt-2">
                     <button
                       onClick={handleTestSMTP}
-                      disabled={isTestingSMTP || !smtpConfig.host || !smtpConfig.username || !smtpConfig.password}
+                      disabled={isTestingSMTP || !smtpCredentialsReady}
                       className="flex items-center space-x-2 px-3 py-1.5 bg-green-500 hover:bg-green-600 disabled:bg-gray-300 dark:disabled:bg-gray-600 disabled:cursor-not-allowed text-white rounded-md transition-colors text-xs font-medium"
                     >
                       {isTestingSMTP ? (
@@ -531,6 +1034,19 @@ const Settings = ({ onClose, onSettingsChanged }) => {
                       <span>{isTestingSMTP ? 'Test...' : 'Tester'}</span>
                     </button>
 
+                    <button
+                      onClick={handleDiagnoseSMTP}
+                      disabled={isDiagnosingSMTP || !smtpConfig.host}
+                      className="flex items-center space-x-2 px-3 py-1.5 text-gray-700 dark:text-gray-300 bg-white dark:bg-gray-800 border border-gray-200 dark:border-gray-600 hover:bg-gray-50 dark:hover:bg-gray-700 disabled:opacity-50 disabled:cursor-not-allowed rounded-md transition-colors text-xs font-medium"
+                    >
+                      {isDiagnosingSMTP ? (
+                        <RefreshCw className="animate-spin" size={12} />
+                      ) : (
+                        <Stethoscope size={12} />
+                      )}
+                      <span>{isDiagnosingSMTP ? 'Diagnostic...' : 'Diagnostiquer'}</span>
+                    </button>
+
                     {smtpTestStatus && (
                       <div className={`flex items-center space-x-1 text-xs ${smtpTestStatus === 'success' ? 'text-green-600' : 'text-red-600'}`}>
                         {smtpTestStatus === 'success' ? <CheckCircle size={12} /> : <AlertCircle size={12} />}
@@ -538,6 +1054,38 @@ const Settings = ({ onClose, onSettingsChanged }) => {
                       </div>
                     )}
                   </div>
+
+                  {/* Rapport du diagnostic SMTP */}
+                  {smtpDiagReport && (
+                    <div className="space-y-1.5 p-2 bg-white dark:bg-gray-800 border border-gray-200 dark:border-gray-600 rounded-md">
+                      {smtpDiagReport.steps.map(step => (
+                        <div key={step.id} className="flex items-start space-x-2 text-xs">
+                          <span className="mt-0.5">
+                            {step.status === 'ok' && <CheckCircle size={12} className="text-green-600" />}
+                            {step.status === 'warning' && <AlertTriangle size={12} className="text-orange-500" />}
+                            {step.status === 'error' && <AlertCircle size={12} className="text-red-600" />}
+                            {step.status === 'skipped' && <MinusCircle size={12} className="text-gray-400" />}
+                          </span>
+                          <div className="flex-1 min-w-0">
+                            <div className="flex justify-between">
+                              <span className="font-medium text-gray-700 dark:text-gray-300">{step.label}</span>
+                              {step.status !== 'skipped' && (
+                                <span className="text-gray-400">{step.duration_ms} ms</span>
+                              )}
+                            </div>
+                            {step.detail && (
+                              <p className={`break-words ${step.status === 'error' ? 'text-red-600 dark:text-red-400' : 'text-gray-500 dark:text-gray-400'}`}>
+                                {step.detail}
+                              </p>
+                            )}
+                            {step.hint && (
+                              <p className="text-orange-600 dark:text-orange-400">💡 {step.hint}</p>
+                            )}
+                          </div>
+                        </div>
+                      ))}
+                    </div>
+                  )}
                 </div>
               </div>
             )}
@@ -1,23 +1,40 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {backend} from '../models';
 import {time} from '../models';
 
 export function ClearCooldowns():Promise<void>;
 
+export function Defer(arg1:backend.DeferredAlert):Promise<void>;
+
 export function GetCooldown():Promise<number>;
 
 export function GetLastNotificationTime(arg1:string,arg2:string):Promise<time.Time>;
 
 export function IsEnabled():Promise<boolean>;
 
+export function MaintenanceFor(arg1:string):Promise<string>;
+
+export function PendingDigest():Promise<Array<backend.DeferredAlert>>;
+
 export function Send(arg1:string,arg2:string):Promise<void>;
 
 export function SendCritical(arg1:string,arg2:string):Promise<void>;
 
+export function SendDigest(arg1:Array<backend.DeferredAlert>):Promise<void>;
+
+export function SendRootCause(arg1:string,arg2:Array<string>):Promise<void>;
+
 export function SendSummary(arg1:Array<string>):Promise<void>;
 
 export function SetCooldown(arg1:number):Promise<void>;
 
 export function SetEnabled(arg1:boolean):Promise<void>;
 
+export function SetMaintenance(arg1:string,arg2:string):Promise<void>;
+
 export function ShouldNotify(arg1:string,arg2:string):Promise<boolean>;
+
+export function ShouldNotifyCritical(arg1:string):Promise<boolean>;
+
+export function TakeDigest(arg1:time.Time):Promise<Array<backend.DeferredAlert>>;
@@ -6,6 +6,10 @@ export function ClearCooldowns() {
   return window['go']['backend']['NotificationManager']['ClearCooldowns']();
 }
 
+export function Defer(arg1) {
+  return window['go']['backend']['NotificationManager']['Defer'](arg1);
+}
+
 export function GetCooldown() {
   return window['go']['backend']['NotificationManager']['GetCooldown']();
 }
@@ -18,6 +22,14 @@ export function IsEnabled() {
   return window['go']['backend']['NotificationManager']['IsEnabled']();
 }
 
+export function MaintenanceFor(arg1) {
+  return window['go']['backend']['NotificationManager']['MaintenanceFor'](arg1);
+}
+
+export function PendingDigest() {
+  return window['go']['backend']['NotificationManager']['PendingDigest']();
+}
+
 export function Send(arg1, arg2) {
   return window['go']['backend']['NotificationManager']['Send'](arg1, arg2);
 }
@@ -26,6 +38,14 @@ export function SendCritical(arg1, arg2) {
   return window['go']['backend']['NotificationManager']['SendCritical'](arg1, arg2);
 }
 
+export function SendDigest(arg1) {
+  return window['go']['backend']['NotificationManager']['SendDigest'](arg1);
+}
+
+export function SendRootCause(arg1, arg2) {
+  return window['go']['backend']['NotificationManager']['SendRootCause'](arg1, arg2);
+}
+
 export function SendSummary(arg1) {
   return window['go']['backend']['NotificationManager']['SendSummary'](arg1);
 }
@@ -38,6 +58,18 @@ export function SetEnabled(arg1) {
   return window['go']['backend']['NotificationManager']['SetEnabled'](arg1);
 }
 
+export function SetMaintenance(arg1, arg2) {
+  return window['go']['backend']['NotificationManager']['SetMaintenance'](arg1, arg2);
+}
+
 export function ShouldNotify(arg1, arg2) {
   return window['go']['backend']['NotificationManager']['ShouldNotify'](arg1, arg2);
 }
+
+export function ShouldNotifyCritical(arg1) {
+  return window['go']['backend']['NotificationManager']['ShouldNotifyCritical'](arg1);
+}
+
+export function TakeDigest(arg1) {
+  return window['go']['backend']['NotificationManager']['TakeDigest'](arg1);
+}
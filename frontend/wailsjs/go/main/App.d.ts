@@ -5,36 +5,76 @@ import {backend} from '../models';
 
 export function AddServer(arg1:main.Server):Promise<main.Server>;
 
+export function ApplySMTPPreset(arg1:string,arg2:backend.SMTPConfig):Promise<backend.SMTPConfig>;
+
+export function BulkUpdateServers(arg1:main.BulkAction):Promise<Array<string>>;
+
 export function ClearNotificationCooldowns():Promise<void>;
 
+export function DeleteQueuedEmail(arg1:string):Promise<void>;
+
 export function DeleteServer(arg1:string):Promise<void>;
 
-export function GetGmailSMTPConfig():Promise<backend.SMTPConfig>;
+export function DiagnoseSMTP(arg1:backend.SMTPConfig,arg2:string):Promise<backend.SMTPDiagReport>;
+
+export function ExportServers(arg1:main.ExportOptions):Promise<string>;
+
+export function GetConfigMigrations():Promise<Array<backend.ConfigMigrationReport>>;
+
+export function GetDefaultEmailTemplates():Promise<Record<string, backend.EmailTemplate>>;
+
+export function GetGroups():Promise<Array<main.GroupStatus>>;
+
+export function GetMailQueue():Promise<Array<backend.QueuedEmail>>;
+
+export function GetMaintenanceWindows():Promise<Array<backend.MaintenanceWindowStatus>>;
 
 export function GetNotificationCooldown():Promise<number>;
 
 export function GetNotificationsEnabled():Promise<boolean>;
 
-export function GetOutlookSMTPConfig():Promise<backend.SMTPConfig>;
+export function GetPendingDigest():Promise<Array<backend.DeferredAlert>>;
+
+export function GetPushURL(arg1:string):Promise<string>;
 
 export function GetSMTPConfig():Promise<backend.SMTPConfig>;
 
 export function GetSMTPPort():Promise<number>;
 
-export function GetServers():Promise<Array<main.Server>>;
+export function GetSMTPPresets():Promise<Array<backend.SMTPPreset>>;
+
+export function GetSecretStoreStatus():Promise<backend.SecretStoreStatus>;
+
+export function GetServerHistory(arg1:string):Promise<Array<main.ServerStatus>>;
+
+export function GetServers(arg1:main.ServerFilter):Promise<Array<main.Server>>;
 
 export function GetSettings():Promise<backend.Settings>;
 
 export function GetSystemTheme():Promise<string>;
 
-export function GetYahooSMTPConfig():Promise<backend.SMTPConfig>;
+export function GetTags():Promise<Array<main.TagCount>>;
+
+export function ImportServers(arg1:string,arg2:main.ImportOptions):Promise<main.ImportReport>;
 
 export function ManualCheck(arg1:main.Server):Promise<main.ServerStatus>;
 
 export function NotifyServerDown(arg1:string):Promise<void>;
 
+export function PauseServer(arg1:string):Promise<main.Server>;
+
+export function PreviewEmailTemplate(arg1:string,arg2:backend.EmailTemplate):Promise<backend.RenderedEmail>;
+
 export function RestartEmbeddedSMTP():Promise<void>;
 
+export function RestartPushServer():Promise<void>;
+
+export function ResumeServer(arg1:string):Promise<main.Server>;
+
+export function RetryQueuedEmail(arg1:string):Promise<void>;
+
+export function SaveMaintenanceWindows(arg1:Array<backend.MaintenanceWindow>):Promise<void>;
+
 export function SaveSetting(arg1:backend.Settings):Promise<void>;
 
 export function SaveSettings(arg1:backend.Settings):Promise<void>;
@@ -55,8 +95,16 @@ export function SetUserEmail(arg1:string):Promise<void>;
 
 export function StartEmbeddedSMTP():Promise<void>;
 
+export function StartPushServer():Promise<void>;
+
 export function StopSMTP():Promise<void>;
 
 export function TestEmailAlert():Promise<void>;
 
+export function UnlockSecretStore(arg1:string):Promise<void>;
+
 export function UpdateServer(arg1:main.Server):Promise<main.Server>;
+
+export function ValidateMaintenanceWindows(arg1:Array<backend.MaintenanceWindow>):Promise<Array<backend.FieldError>>;
+
+export function ValidateSettings(arg1:backend.Settings):Promise<Array<backend.FieldError>>;
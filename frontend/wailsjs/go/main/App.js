@@ -6,16 +6,52 @@ export function AddServer(arg1) {
   return window['go']['main']['App']['AddServer'](arg1);
 }
 
+export function ApplySMTPPreset(arg1, arg2) {
+  return window['go']['main']['App']['ApplySMTPPreset'](arg1, arg2);
+}
+
+export function BulkUpdateServers(arg1) {
+  return window['go']['main']['App']['BulkUpdateServers'](arg1);
+}
+
 export function ClearNotificationCooldowns() {
   return window['go']['main']['App']['ClearNotificationCooldowns']();
 }
 
+export function DeleteQueuedEmail(arg1) {
+  return window['go']['main']['App']['DeleteQueuedEmail'](arg1);
+}
+
 export function DeleteServer(arg1) {
   return window['go']['main']['App']['DeleteServer'](arg1);
 }
 
-export function GetGmailSMTPConfig() {
-  return window['go']['main']['App']['GetGmailSMTPConfig']();
+export function DiagnoseSMTP(arg1, arg2) {
+  return window['go']['main']['App']['DiagnoseSMTP'](arg1, arg2);
+}
+
+export function ExportServers(arg1) {
+  return window['go']['main']['App']['ExportServers'](arg1);
+}
+
+export function GetConfigMigrations() {
+  return window['go']['main']['App']['GetConfigMigrations']();
+}
+
+export function GetDefaultEmailTemplates() {
+  return window['go']['main']['App']['GetDefaultEmailTemplates']();
+}
+
+export function GetGroups() {
+  return window['go']['main']['App']['GetGroups']();
+}
+
+export function GetMailQueue() {
+  return window['go']['main']['App']['GetMailQueue']();
+}
+
+export function GetMaintenanceWindows() {
+  return window['go']['main']['App']['GetMaintenanceWindows']();
 }
 
 export function GetNotificationCooldown() {
@@ -26,8 +62,12 @@ export function GetNotificationsEnabled() {
   return window['go']['main']['App']['GetNotificationsEnabled']();
 }
 
-export function GetOutlookSMTPConfig() {
-  return window['go']['main']['App']['GetOutlookSMTPConfig']();
+export function GetPendingDigest() {
+  return window['go']['main']['App']['GetPendingDigest']();
+}
+
+export function GetPushURL(arg1) {
+  return window['go']['main']['App']['GetPushURL'](arg1);
 }
 
 export function GetSMTPConfig() {
@@ -38,8 +78,20 @@ export function GetSMTPPort() {
   return window['go']['main']['App']['GetSMTPPort']();
 }
 
-export function GetServers() {
-  return window['go']['main']['App']['GetServers']();
+export function GetSMTPPresets() {
+  return window['go']['main']['App']['GetSMTPPresets']();
+}
+
+export function GetSecretStoreStatus() {
+  return window['go']['main']['App']['GetSecretStoreStatus']();
+}
+
+export function GetServerHistory(arg1) {
+  return window['go']['main']['App']['GetServerHistory'](arg1);
+}
+
+export function GetServers(arg1) {
+  return window['go']['main']['App']['GetServers'](arg1);
 }
 
 export function GetSettings() {
@@ -50,8 +102,12 @@ export function GetSystemTheme() {
   return window['go']['main']['App']['GetSystemTheme']();
 }
 
-export function GetYahooSMTPConfig() {
-  return window['go']['main']['App']['GetYahooSMTPConfig']();
+export function GetTags() {
+  return window['go']['main']['App']['GetTags']();
+}
+
+export function ImportServers(arg1, arg2) {
+  return window['go']['main']['App']['ImportServers'](arg1, arg2);
 }
 
 export function ManualCheck(arg1) {
@@ -62,10 +118,34 @@ export function NotifyServerDown(arg1) {
   return window['go']['main']['App']['NotifyServerDown'](arg1);
 }
 
+export function PauseServer(arg1) {
+  return window['go']['main']['App']['PauseServer'](arg1);
+}
+
+export function PreviewEmailTemplate(arg1, arg2) {
+  return window['go']['main']['App']['PreviewEmailTemplate'](arg1, arg2);
+}
+
 export function RestartEmbeddedSMTP() {
   return window['go']['main']['App']['RestartEmbeddedSMTP']();
 }
 
+export function RestartPushServer() {
+  return window['go']['main']['App']['RestartPushServer']();
+}
+
+export function ResumeServer(arg1) {
+  return window['go']['main']['App']['ResumeServer'](arg1);
+}
+
+export function RetryQueuedEmail(arg1) {
+  return window['go']['main']['App']['RetryQueuedEmail'](arg1);
+}
+
+export function SaveMaintenanceWindows(arg1) {
+  return window['go']['main']['App']['SaveMaintenanceWindows'](arg1);
+}
+
 export function SaveSetting(arg1) {
   return window['go']['main']['App']['SaveSetting'](arg1);
 }
@@ -106,6 +186,10 @@ export function StartEmbeddedSMTP() {
   return window['go']['main']['App']['StartEmbeddedSMTP']();
 }
 
+export function StartPushServer() {
+  return window['go']['main']['App']['StartPushServer']();
+}
+
 export function StopSMTP() {
   return window['go']['main']['App']['StopSMTP']();
 }
@@ -114,6 +198,18 @@ export function TestEmailAlert() {
   return window['go']['main']['App']['TestEmailAlert']();
 }
 
+export function UnlockSecretStore(arg1) {
+  return window['go']['main']['App']['UnlockSecretStore'](arg1);
+}
+
 export function UpdateServer(arg1) {
   return window['go']['main']['App']['UpdateServer'](arg1);
 }
+
+export function ValidateMaintenanceWindows(arg1) {
+  return window['go']['main']['App']['ValidateMaintenanceWindows'](arg1);
+}
+
+export function ValidateSettings(arg1) {
+  return window['go']['main']['App']['ValidateSettings'](arg1);
+}
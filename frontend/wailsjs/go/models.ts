@@ -1,12 +1,377 @@
 export namespace backend {
 	
+	export class ConfigMigrationReport {
+	    kind: string;
+	    path: string;
+	    from_version: number;
+	    to_version: number;
+	    changes: string[];
+	    migrated_at: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfigMigrationReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.path = source["path"];
+	        this.from_version = source["from_version"];
+	        this.to_version = source["to_version"];
+	        this.changes = source["changes"];
+	        this.migrated_at = this.convertValues(source["migrated_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DeferredAlert {
+	    server: string;
+	    kind: string;
+	    message?: string;
+	    time: time.Time;
+	    release: time.Time;
+	    count?: number;
+	    last: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new DeferredAlert(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.server = source["server"];
+	        this.kind = source["kind"];
+	        this.message = source["message"];
+	        this.time = this.convertValues(source["time"], time.Time);
+	        this.release = this.convertValues(source["release"], time.Time);
+	        this.count = source["count"];
+	        this.last = this.convertValues(source["last"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EmailAttachment {
+	    filename: string;
+	    content_type: string;
+	    content_id?: string;
+	    inline?: boolean;
+	    data: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new EmailAttachment(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filename = source["filename"];
+	        this.content_type = source["content_type"];
+	        this.content_id = source["content_id"];
+	        this.inline = source["inline"];
+	        this.data = source["data"];
+	    }
+	}
+	export class EmailTemplate {
+	    subject: string;
+	    text: string;
+	    html: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new EmailTemplate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.subject = source["subject"];
+	        this.text = source["text"];
+	        this.html = source["html"];
+	    }
+	}
+	export class FieldError {
+	    field: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FieldError(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.field = source["field"];
+	        this.message = source["message"];
+	    }
+	}
+	export class MaintenanceWindow {
+	    name: string;
+	    servers?: string[];
+	    groups?: string[];
+	    timezone?: string;
+	    start?: string;
+	    end?: string;
+	    cron?: string;
+	    duration?: string;
+	    days?: string[];
+	    from?: string;
+	    to?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MaintenanceWindow(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.servers = source["servers"];
+	        this.groups = source["groups"];
+	        this.timezone = source["timezone"];
+	        this.start = source["start"];
+	        this.end = source["end"];
+	        this.cron = source["cron"];
+	        this.duration = source["duration"];
+	        this.days = source["days"];
+	        this.from = source["from"];
+	        this.to = source["to"];
+	    }
+	}
+	export class MaintenanceWindowStatus {
+	    window: MaintenanceWindow;
+	    active: boolean;
+	    next?: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new MaintenanceWindowStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.window = this.convertValues(source["window"], MaintenanceWindow);
+	        this.active = source["active"];
+	        this.next = this.convertValues(source["next"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class OAuth2Config {
+	    token_url: string;
+	    client_id?: string;
+	    client_secret?: string;
+	    refresh_token?: string;
+	    scopes?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new OAuth2Config(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.token_url = source["token_url"];
+	        this.client_id = source["client_id"];
+	        this.client_secret = source["client_secret"];
+	        this.refresh_token = source["refresh_token"];
+	        this.scopes = source["scopes"];
+	    }
+	}
+	export class QueuedEmail {
+	    id: string;
+	    from: string;
+	    to: string[];
+	    cc?: string[];
+	    bcc?: string[];
+	    reply_to?: string[];
+	    subject: string;
+	    body: string;
+	    html?: string;
+	    attachments?: EmailAttachment[];
+	    status: string;
+	    attempts: number;
+	    last_error?: string;
+	    created_at: time.Time;
+	    next_attempt: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new QueuedEmail(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.from = source["from"];
+	        this.to = source["to"];
+	        this.cc = source["cc"];
+	        this.bcc = source["bcc"];
+	        this.reply_to = source["reply_to"];
+	        this.subject = source["subject"];
+	        this.body = source["body"];
+	        this.html = source["html"];
+	        this.attachments = this.convertValues(source["attachments"], EmailAttachment);
+	        this.status = source["status"];
+	        this.attempts = source["attempts"];
+	        this.last_error = source["last_error"];
+	        this.created_at = this.convertValues(source["created_at"], time.Time);
+	        this.next_attempt = this.convertValues(source["next_attempt"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class QuietPeriod {
+	    days?: string[];
+	    from: string;
+	    to: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new QuietPeriod(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.days = source["days"];
+	        this.from = source["from"];
+	        this.to = source["to"];
+	    }
+	}
+	export class QuietHours {
+	    enabled: boolean;
+	    mode?: string;
+	    timezone?: string;
+	    periods?: QuietPeriod[];
+	
+	    static createFrom(source: any = {}) {
+	        return new QuietHours(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.mode = source["mode"];
+	        this.timezone = source["timezone"];
+	        this.periods = this.convertValues(source["periods"], QuietPeriod);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class RecipientList {
+	    to: string[];
+	    cc?: string[];
+	    bcc?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RecipientList(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.to = source["to"];
+	        this.cc = source["cc"];
+	        this.bcc = source["bcc"];
+	    }
+	}
+	export class RenderedEmail {
+	    subject: string;
+	    text: string;
+	    html: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RenderedEmail(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.subject = source["subject"];
+	        this.text = source["text"];
+	        this.html = source["html"];
+	    }
+	}
 	export class SMTPConfig {
 	    host: string;
 	    port: number;
 	    username: string;
 	    password: string;
 	    from: string;
-	    tls: boolean;
+	    auth?: string;
+	    oauth2?: OAuth2Config;
+	    tls_mode: string;
+	    ca_cert?: string;
+	    skip_verify?: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new SMTPConfig(source);
@@ -19,7 +384,162 @@ export namespace backend {
 	        this.username = source["username"];
 	        this.password = source["password"];
 	        this.from = source["from"];
-	        this.tls = source["tls"];
+	        this.auth = source["auth"];
+	        this.oauth2 = this.convertValues(source["oauth2"], OAuth2Config);
+	        this.tls_mode = source["tls_mode"];
+	        this.ca_cert = source["ca_cert"];
+	        this.skip_verify = source["skip_verify"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SMTPDiagStep {
+	    id: string;
+	    label: string;
+	    status: string;
+	    detail?: string;
+	    hint?: string;
+	    code?: number;
+	    duration_ms: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SMTPDiagStep(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.label = source["label"];
+	        this.status = source["status"];
+	        this.detail = source["detail"];
+	        this.hint = source["hint"];
+	        this.code = source["code"];
+	        this.duration_ms = source["duration_ms"];
+	    }
+	}
+	export class SMTPDiagReport {
+	    host: string;
+	    port: number;
+	    tls_mode: string;
+	    auth: string;
+	    success: boolean;
+	    steps: SMTPDiagStep[];
+	    started_at: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new SMTPDiagReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.host = source["host"];
+	        this.port = source["port"];
+	        this.tls_mode = source["tls_mode"];
+	        this.auth = source["auth"];
+	        this.success = source["success"];
+	        this.steps = this.convertValues(source["steps"], SMTPDiagStep);
+	        this.started_at = this.convertValues(source["started_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class SMTPPreset {
+	    id: string;
+	    name: string;
+	    host: string;
+	    port: number;
+	    tls_mode: string;
+	    auth: string;
+	    username?: string;
+	    oauth2?: OAuth2Config;
+	    notes?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SMTPPreset(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.host = source["host"];
+	        this.port = source["port"];
+	        this.tls_mode = source["tls_mode"];
+	        this.auth = source["auth"];
+	        this.username = source["username"];
+	        this.oauth2 = this.convertValues(source["oauth2"], OAuth2Config);
+	        this.notes = source["notes"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SecretStoreStatus {
+	    backend: string;
+	    locked: boolean;
+	    initialized: boolean;
+	    pending: number;
+	    unsaved: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SecretStoreStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.backend = source["backend"];
+	        this.locked = source["locked"];
+	        this.initialized = source["initialized"];
+	        this.pending = source["pending"];
+	        this.unsaved = source["unsaved"];
 	    }
 	}
 	export class Settings {
@@ -28,7 +548,20 @@ export namespace backend {
 	    notificationCooldown: number;
 	    refreshInterval: number;
 	    userEmail: string;
+	    criticalFailures: number;
+	    criticalRepeat: number;
+	    criticalAfterMinutes: number;
 	    smtp_config: SMTPConfig;
+	    recipients: RecipientList;
+	    severityRecipients?: Record<string, RecipientList>;
+	    emailTemplates?: Record<string, EmailTemplate>;
+	    relayAllowList?: string[];
+	    heartbeatSmtpAddr?: string;
+	    pushListenAddr?: string;
+	    pushBaseUrl?: string;
+	    maintenanceWindows?: MaintenanceWindow[];
+	    quietHours: QuietHours;
+	    quietHoursByType?: Record<string, QuietHours>;
 	
 	    static createFrom(source: any = {}) {
 	        return new Settings(source);
@@ -41,7 +574,20 @@ export namespace backend {
 	        this.notificationCooldown = source["notificationCooldown"];
 	        this.refreshInterval = source["refreshInterval"];
 	        this.userEmail = source["userEmail"];
+	        this.criticalFailures = source["criticalFailures"];
+	        this.criticalRepeat = source["criticalRepeat"];
+	        this.criticalAfterMinutes = source["criticalAfterMinutes"];
 	        this.smtp_config = this.convertValues(source["smtp_config"], SMTPConfig);
+	        this.recipients = this.convertValues(source["recipients"], RecipientList);
+	        this.severityRecipients = this.convertValues(source["severityRecipients"], RecipientList, true);
+	        this.emailTemplates = this.convertValues(source["emailTemplates"], EmailTemplate, true);
+	        this.relayAllowList = source["relayAllowList"];
+	        this.heartbeatSmtpAddr = source["heartbeatSmtpAddr"];
+	        this.pushListenAddr = source["pushListenAddr"];
+	        this.pushBaseUrl = source["pushBaseUrl"];
+	        this.maintenanceWindows = this.convertValues(source["maintenanceWindows"], MaintenanceWindow);
+	        this.quietHours = this.convertValues(source["quietHours"], QuietHours);
+	        this.quietHoursByType = this.convertValues(source["quietHoursByType"], QuietHours, true);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -67,11 +613,208 @@ export namespace backend {
 
 export namespace main {
 	
+	export class ServerFilter {
+	    group: string;
+	    tags: string[];
+	    status: string;
+	    search: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ServerFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.group = source["group"];
+	        this.tags = source["tags"];
+	        this.status = source["status"];
+	        this.search = source["search"];
+	    }
+	}
+	export class BulkAction {
+	    action: string;
+	    filter: ServerFilter;
+	    interval: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BulkAction(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.action = source["action"];
+	        this.filter = this.convertValues(source["filter"], ServerFilter);
+	        this.interval = source["interval"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ExportOptions {
+	    format: string;
+	    include_history: boolean;
+	    include_settings: boolean;
+	    include_push_tokens: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExportOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.format = source["format"];
+	        this.include_history = source["include_history"];
+	        this.include_settings = source["include_settings"];
+	        this.include_push_tokens = source["include_push_tokens"];
+	    }
+	}
+	export class GroupStatus {
+	    path: string;
+	    name: string;
+	    parent: string;
+	    depth: number;
+	    total: number;
+	    up: number;
+	    down: number;
+	    paused: number;
+	    pending: number;
+	    status: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GroupStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.name = source["name"];
+	        this.parent = source["parent"];
+	        this.depth = source["depth"];
+	        this.total = source["total"];
+	        this.up = source["up"];
+	        this.down = source["down"];
+	        this.paused = source["paused"];
+	        this.pending = source["pending"];
+	        this.status = source["status"];
+	    }
+	}
+	export class ImportItem {
+	    row: number;
+	    id: string;
+	    name: string;
+	    url: string;
+	    action: string;
+	    matched_id?: string;
+	    reason?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.row = source["row"];
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.url = source["url"];
+	        this.action = source["action"];
+	        this.matched_id = source["matched_id"];
+	        this.reason = source["reason"];
+	    }
+	}
+	export class ImportOptions {
+	    format: string;
+	    mode: string;
+	    match_by: string;
+	    conflict: string;
+	    settings: boolean;
+	    dry_run: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.format = source["format"];
+	        this.mode = source["mode"];
+	        this.match_by = source["match_by"];
+	        this.conflict = source["conflict"];
+	        this.settings = source["settings"];
+	        this.dry_run = source["dry_run"];
+	    }
+	}
+	export class ImportReport {
+	    dry_run: boolean;
+	    applied: boolean;
+	    format: string;
+	    items: ImportItem[];
+	    removed: string[];
+	    settings: boolean;
+	    warnings: string[];
+	    errors: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.dry_run = source["dry_run"];
+	        this.applied = source["applied"];
+	        this.format = source["format"];
+	        this.items = this.convertValues(source["items"], ImportItem);
+	        this.removed = source["removed"];
+	        this.settings = source["settings"];
+	        this.warnings = source["warnings"];
+	        this.errors = source["errors"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class ServerStatus {
 	    is_up: boolean;
 	    response_time_ms: number;
 	    last_check: time.Time;
 	    last_error?: string;
+	    consecutive_failures: number;
+	    down_since?: time.Time;
+	    last_heartbeat?: time.Time;
+	    last_message?: string;
+	    maintenance?: string;
+	    unreachable?: boolean;
+	    root_cause?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new ServerStatus(source);
@@ -83,6 +826,13 @@ export namespace main {
 	        this.response_time_ms = source["response_time_ms"];
 	        this.last_check = this.convertValues(source["last_check"], time.Time);
 	        this.last_error = source["last_error"];
+	        this.consecutive_failures = source["consecutive_failures"];
+	        this.down_since = this.convertValues(source["down_since"], time.Time);
+	        this.last_heartbeat = this.convertValues(source["last_heartbeat"], time.Time);
+	        this.last_message = source["last_message"];
+	        this.maintenance = source["maintenance"];
+	        this.unreachable = source["unreachable"];
+	        this.root_cause = source["root_cause"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -111,6 +861,17 @@ export namespace main {
 	    interval: string;
 	    timeout: string;
 	    status: ServerStatus;
+	    group?: string;
+	    tags?: string[];
+	    paused?: boolean;
+	    parents?: string[];
+	    quiet_hours?: backend.QuietHours;
+	    critical_failures?: number;
+	    critical_repeat?: number;
+	    critical_after?: string;
+	    subject_pattern?: string;
+	    push_token?: string;
+	    grace?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new Server(source);
@@ -125,6 +886,17 @@ export namespace main {
 	        this.interval = source["interval"];
 	        this.timeout = source["timeout"];
 	        this.status = this.convertValues(source["status"], ServerStatus);
+	        this.group = source["group"];
+	        this.tags = source["tags"];
+	        this.paused = source["paused"];
+	        this.parents = source["parents"];
+	        this.quiet_hours = this.convertValues(source["quiet_hours"], backend.QuietHours);
+	        this.critical_failures = source["critical_failures"];
+	        this.critical_repeat = source["critical_repeat"];
+	        this.critical_after = source["critical_after"];
+	        this.subject_pattern = source["subject_pattern"];
+	        this.push_token = source["push_token"];
+	        this.grace = source["grace"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -145,6 +917,22 @@ export namespace main {
 		    return a;
 		}
 	}
+	
+	
+	export class TagCount {
+	    tag: string;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TagCount(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tag = source["tag"];
+	        this.count = source["count"];
+	    }
+	}
 
 }
 
@@ -4,6 +4,7 @@ import (
 	"embed"
 	"fmt"
 	backend "monitoring_serv/backend"
+	"os"
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
@@ -16,6 +17,17 @@ import (
 var assets embed.FS
 
 func main() {
+	// Commande de validation du fichier de configuration déclaratif
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		os.Exit(runValidate(os.Args[2:]))
+	}
+
+	// Reprendre les fichiers laissés dans le dossier courant par les anciennes
+	// versions, avant toute lecture de la configuration
+	if err := backend.MigrateLegacyFiles(); err != nil {
+		fmt.Println("⚠️ Migration des fichiers de configuration impossible :", err)
+	}
+
 	// Create an instance of the app structure
 	loadedSettings, err := backend.LoadSettings()
 	if err != nil {
@@ -29,7 +41,7 @@ func main() {
 	case "inapp":
 		notifier.SetEnabled(true)
 	case "email":
-		notifier.SetEnabled(false)
+		notifier.SetEnabled(true)
 	case "none":
 		notifier.SetEnabled(false)
 	default:
@@ -91,3 +103,29 @@ func main() {
 		println("Error:", err.Error())
 	}
 }
+
+// runValidate - Vérifie un fichier de configuration déclaratif (YAML ou TOML)
+// sans démarrer l'application: monitoring_serv validate [fichier]
+// Affiche une erreur par ligne (fichier:ligne: message) et renvoie le code de sortie
+func runValidate(args []string) int {
+	path := backend.DeclarativeConfigPath()
+	if len(args) > 0 {
+		path = args[0]
+	}
+	if path == "" || len(args) > 1 {
+		fmt.Fprintf(os.Stderr, "Usage: monitoring_serv validate [fichier.yaml|fichier.toml]\n")
+		fmt.Fprintf(os.Stderr, "Sans fichier: $%s, sinon monitoring.yaml/.yml/.toml du dossier de configuration\n", backend.DeclarativeConfigEnv)
+		return 2
+	}
+
+	servers, _, errs := loadDeclarativeConfig(path, backend.DefaultSettings(), nil)
+	if len(errs) > 0 {
+		for _, err := range errs {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		fmt.Fprintf(os.Stderr, "❌ %s: %d erreur(s)\n", path, len(errs))
+		return 1
+	}
+	fmt.Printf("✅ %s valide: %d serveur(s)\n", path, len(servers))
+	return 0
+}